@@ -0,0 +1,488 @@
+package handler
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"api/internal/core"
+	"api/internal/core/item"
+	"api/internal/platform/exporter"
+	"api/internal/platform/spreadsheet"
+)
+
+/*
+Formatos de saída suportados pelos comandos.
+
+- OutputTable: tabela alinhada, pensada para leitura humana;
+- OutputJSON: JSON, pensado para scripts (ex: `| jq`).
+*/
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// importPollInterval é o intervalo entre as consultas a uma importação que roda em segundo plano.
+const importPollInterval = 200 * time.Millisecond
+
+/*
+importOutput é a saída JSON de `items import`: o job e, ao contrário da REST (que
+oferece o relatório para download), os erros por linha junto.
+*/
+type importOutput struct {
+	item.ImportJob
+	Errors []item.ImportRowError `json:"errors"` // Relatório de erros por linha
+}
+
+/*
+itemCmds agrupa os subcomandos `items ...` da CLI.
+
+Assim como os handlers REST e gRPC, depende apenas das portas dos casos de uso
+(core.ItemUsecasePort e core.ImportUsecasePort), então as mesmas regras de negócio
+valem para a linha de comando.
+*/
+type itemCmds struct {
+	core      core.ItemUsecasePort   // Interface da camada de caso de uso relacionada a "item"
+	imports   core.ImportUsecasePort // Importação de planilhas (`items import`)
+	out       io.Writer              // Destino da saída (normalmente os.Stdout)
+	output    string                 // Formato de saída: OutputTable ou OutputJSON
+	retention time.Duration          // Retenção padrão da lixeira em `items purge` (config trash_retention)
+}
+
+/*
+NewItemCmds cria os comandos de item recebendo os casos de uso de itens e de importação,
+o destino da saída, o formato desejado (OutputTable ou OutputJSON) e a retenção padrão da lixeira.
+*/
+func NewItemCmds(u core.ItemUsecasePort, imports core.ImportUsecasePort, out io.Writer, output string, retention time.Duration) *itemCmds {
+	return &itemCmds{
+		core:      u,
+		imports:   imports,
+		out:       out,
+		output:    output,
+		retention: retention,
+	}
+}
+
+/*
+Run despacha os argumentos para o subcomando correspondente.
+
+O contexto é repassado ao caso de uso; na CLI ele é cancelado pelo Ctrl+C.
+
+Exemplos:
+
+	items list
+	items get 1
+	items create --code ITEM001 --title "Caneta" --price 2.5 --stock 10
+	items update 1 --stock 20
+	items delete 1
+	items trash
+	items restore 1
+	items purge --retention 168h
+	items import itens.csv
+	items import --mapping '{"SKU": "code", "Preço": "price"}' --dry-run fornecedor.xlsx
+	items export --format csv
+	items export --format xlsx --file itens.xlsx
+*/
+func (c *itemCmds) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("informe um subcomando: list, get, create, update, delete, trash, restore, purge, import ou export")
+	}
+
+	switch args[0] {
+	case "list":
+		return c.list(ctx)
+	case "get":
+		return c.get(ctx, args[1:])
+	case "create":
+		return c.create(ctx, args[1:])
+	case "update":
+		return c.update(ctx, args[1:])
+	case "delete":
+		return c.delete(ctx, args[1:])
+	case "trash":
+		return c.trash(ctx)
+	case "restore":
+		return c.restore(ctx, args[1:])
+	case "purge":
+		return c.purge(ctx, args[1:])
+	case "import":
+		return c.importFile(ctx, args[1:])
+	case "export":
+		return c.export(ctx, args[1:])
+	default:
+		return fmt.Errorf("subcomando desconhecido: %q", args[0])
+	}
+}
+
+// list imprime todos os itens cadastrados.
+func (c *itemCmds) list(ctx context.Context) error {
+	its, err := c.sortedItems(ctx)
+	if err != nil {
+		return err
+	}
+	return c.printList(its)
+}
+
+// get imprime um único item a partir do ID informado.
+func (c *itemCmds) get(ctx context.Context, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	it, err := c.core.GetItem(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.printOne(it)
+}
+
+// create cria um novo item a partir das flags informadas e imprime o item salvo, com o ID gerado.
+func (c *itemCmds) create(ctx context.Context, args []string) error {
+	var it item.Item
+	fs := itemFlagSet("create", &it)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	saved, err := c.core.SaveItem(ctx, it)
+	if err != nil {
+		return err
+	}
+	return c.printOne(saved)
+}
+
+/*
+update altera um item existente.
+
+Apenas as flags informadas são alteradas; os demais campos são mantidos
+com os valores atuais do item. Imprime o item atualizado.
+*/
+func (c *itemCmds) update(ctx context.Context, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	it, err := c.core.GetItem(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	fs := itemFlagSet("update", &it)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	updated, err := c.core.UpdateItem(ctx, it)
+	if err != nil {
+		return err
+	}
+	return c.printOne(updated)
+}
+
+// delete move para a lixeira o item com o ID informado.
+func (c *itemCmds) delete(ctx context.Context, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	if err := c.core.DeleteItem(ctx, id, 0); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, "item deletado com sucesso")
+	return nil
+}
+
+// trash imprime os itens que estão na lixeira.
+func (c *itemCmds) trash(ctx context.Context) error {
+	page, err := c.core.ListTrash(ctx, item.ListFilter{})
+	if err != nil {
+		return err
+	}
+	return c.printList(page.Items)
+}
+
+// restore tira da lixeira o item com o ID informado e imprime o item restaurado.
+func (c *itemCmds) restore(ctx context.Context, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	it, err := c.core.RestoreItem(ctx, id, 0)
+	if err != nil {
+		return err
+	}
+	return c.printOne(it)
+}
+
+/*
+purge remove definitivamente os itens que estão na lixeira há mais que a retenção.
+
+A retenção padrão vem da configuração (trash_retention / TRASH_RETENTION) e pode
+ser trocada com --retention; `--retention 0` esvazia a lixeira inteira.
+Pensado para rodar periodicamente (ex: cron).
+*/
+func (c *itemCmds) purge(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	retention := fs.Duration("retention", c.retention, "tempo mínimo na lixeira antes da remoção (ex: 720h)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	n, err := c.core.PurgeDeleted(ctx, *retention)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "%d item(ns) removido(s) da lixeira\n", n)
+	return nil
+}
+
+/*
+importFile importa itens de uma planilha CSV ou XLSX pelo core.ImportUsecasePort, com as
+mesmas regras de POST /imports: cada linha é um upsert pelo código, e as linhas inválidas
+são rejeitadas sem interromper as demais.
+
+Flags:
+- --mapping: objeto JSON de coluna do arquivo → campo do item, ex: {"SKU": "code"}
+- --format: csv ou xlsx (padrão: o da extensão do arquivo)
+- --dry-run: apenas valida as linhas, sem gravar nada
+
+Arquivos grandes são processados em segundo plano pelo caso de uso; o comando acompanha
+o job até o fim. Imprime os contadores e, se houver, o relatório de erros por linha.
+*/
+func (c *itemCmds) importFile(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	mapping := fs.String("mapping", "", `mapeamento JSON de coluna para campo (ex: {"SKU": "code"})`)
+	format := fs.String("format", "", "formato do arquivo: csv ou xlsx (padrão: o da extensão)")
+	dryRun := fs.Bool("dry-run", false, "apenas valida as linhas, sem gravar nada")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("uso: items import [--mapping JSON] [--format csv|xlsx] [--dry-run] <arquivo>")
+	}
+
+	req := item.ImportRequest{Filename: filepath.Base(fs.Arg(0)), DryRun: *dryRun}
+	if *mapping != "" {
+		if err := json.Unmarshal([]byte(*mapping), &req.Mapping); err != nil {
+			return fmt.Errorf("mapping deve ser um objeto JSON de coluna para campo: %w", err)
+		}
+	}
+	if *format == "" {
+		*format = spreadsheet.FormatOf(fs.Arg(0))
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if req.Rows, err = spreadsheet.Read(f, *format); err != nil {
+		return err
+	}
+
+	job, err := c.imports.StartImport(ctx, req)
+	if err != nil {
+		return err
+	}
+	if job, err = c.waitImport(ctx, job); err != nil {
+		return err
+	}
+	return c.printImport(job)
+}
+
+// waitImport acompanha uma importação em segundo plano até que ela termine (ou ctx seja cancelado).
+func (c *itemCmds) waitImport(ctx context.Context, job item.ImportJob) (item.ImportJob, error) {
+	ticker := time.NewTicker(importPollInterval)
+	defer ticker.Stop()
+	for !job.Finished() {
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+		var err error
+		if job, err = c.imports.GetImport(ctx, job.ID); err != nil {
+			return job, err
+		}
+	}
+	return job, nil
+}
+
+/*
+printImport imprime o resultado de uma importação: os contadores e o relatório de erros.
+Uma falha geral (item.ImportFailed) é devolvida como erro, depois dos contadores.
+*/
+func (c *itemCmds) printImport(job item.ImportJob) error {
+	if c.output == OutputJSON {
+		out := importOutput{ImportJob: job, Errors: job.Errors}
+		if out.Errors == nil {
+			out.Errors = []item.ImportRowError{} // Lista vazia em vez de null, para scripts
+		}
+		if err := writeJSON(c.out, out); err != nil {
+			return err
+		}
+		return job.Err
+	}
+
+	verb := "importada(s)"
+	if job.DryRun {
+		verb = "validada(s), nada foi gravado"
+	}
+	fmt.Fprintf(c.out, "%d de %d linha(s) %s: %d criada(s), %d atualizada(s), %d sem alteração, %d com erro\n",
+		job.Processed, job.Total, verb, job.Created, job.Updated, job.Unchanged, job.Failed)
+	if len(job.Errors) > 0 {
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ROW\tCODE\tFIELD\tMESSAGE")
+		for _, e := range job.Errors {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", e.Row, e.Code, e.Field, e.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return job.Err
+}
+
+/*
+export escreve os itens no formato escolhido, com o mesmo exportador de GET /items/export.
+
+Os itens são lidos do repositório aos poucos e escritos à medida que chegam,
+então um dump do catálogo inteiro não precisa caber em memória.
+
+Flags:
+- --format: json (padrão), csv, ndjson ou xlsx
+- --file: caminho do arquivo de destino (padrão: saída padrão)
+- --gzip: comprime a saída com gzip
+- --status e --q: os mesmos filtros de status e de texto de GET /items
+*/
+func (c *itemCmds) export(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", exporter.FormatJSON, "formato da exportação: "+strings.Join(exporter.Formats, ", "))
+	file := fs.String("file", "", "arquivo de destino (padrão: saída padrão)")
+	gz := fs.Bool("gzip", false, "comprime a saída com gzip")
+	var f item.ListFilter
+	fs.StringVar(&f.Status, "status", "", "exporta apenas os itens com este status")
+	fs.StringVar(&f.Search, "q", "", "exporta apenas os itens com este texto no título ou na descrição")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var out io.Writer = c.out
+	if *file != "" {
+		dst, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer dst.Close()
+		out = dst
+	}
+	var zw *gzip.Writer
+	if *gz {
+		zw = gzip.NewWriter(out)
+		out = zw
+	}
+
+	w, err := exporter.New(out, *format)
+	if err != nil {
+		return err
+	}
+	defer w.Abort() // Libera o XLSX se a exportação falhar antes do Close
+	if err := c.core.ExportItems(ctx, f, w.Write); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if zw != nil {
+		return zw.Close()
+	}
+	return nil
+}
+
+// sortedItems retorna todos os itens ordenados por ID.
+func (c *itemCmds) sortedItems(ctx context.Context) ([]item.Item, error) {
+	page, err := c.core.ListItems(ctx, item.ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+/*
+printOne imprime um único item (get, create, update, restore): em JSON, sempre um objeto.
+*/
+func (c *itemCmds) printOne(it item.Item) error {
+	if c.output == OutputJSON {
+		return writeJSON(c.out, it)
+	}
+	return c.printTable([]item.Item{it})
+}
+
+/*
+printList imprime uma lista de itens (list, trash): em JSON, sempre um array,
+mesmo com um só item ou nenhum, para que scripts não precisem tratar cada caso.
+*/
+func (c *itemCmds) printList(its []item.Item) error {
+	if c.output == OutputJSON {
+		if its == nil {
+			its = []item.Item{} // Lista vazia em vez de null
+		}
+		return writeJSON(c.out, its)
+	}
+	return c.printTable(its)
+}
+
+// printTable imprime os itens em uma tabela alinhada.
+func (c *itemCmds) printTable(its []item.Item) error {
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCODE\tTITLE\tPRICE\tSTOCK\tSTATUS")
+	for _, it := range its {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%.2f\t%d\t%s\n", it.ID, it.Code, it.Title, it.Price, it.Stock, it.Status)
+	}
+	return w.Flush()
+}
+
+// itemFlagSet registra as flags comuns de create/update apontando para os campos do item.
+func itemFlagSet(name string, it *item.Item) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&it.Code, "code", it.Code, "código (SKU) do item")
+	fs.StringVar(&it.Title, "title", it.Title, "título do item")
+	fs.StringVar(&it.Description, "description", it.Description, "descrição do item")
+	fs.Float64Var(&it.Price, "price", it.Price, "preço do item")
+	fs.IntVar(&it.Stock, "stock", it.Stock, "quantidade em estoque")
+	fs.StringVar(&it.Status, "status", it.Status, "status do item")
+	return fs
+}
+
+// parseID lê o ID do primeiro argumento posicional.
+func parseID(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New("informe o ID do item")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("ID do item inválido: %q", args[0])
+	}
+	return id, nil
+}
+
+// writeJSON escreve v como JSON indentado.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
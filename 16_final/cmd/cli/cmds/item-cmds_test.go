@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"api/internal/core"
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+// newTestCmds cria os comandos de item sobre um MapRepository vazio, escrevendo em out.
+func newTestCmds(output string) (*itemCmds, *bytes.Buffer) {
+	repo := item.NewMapRepository()
+	out := &bytes.Buffer{}
+	return NewItemCmds(core.NewItemUsecase(repo), core.NewImportUsecase(repo), out, output, time.Hour), out
+}
+
+// runCmd executa o subcomando e devolve a saída, falhando o teste em caso de erro.
+func runCmd(t *testing.T, c *itemCmds, out *bytes.Buffer, args ...string) string {
+	t.Helper()
+	out.Reset()
+	if err := c.Run(context.Background(), args); err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+	return out.String()
+}
+
+func TestJSONOutputShapes(t *testing.T) {
+	c, out := newTestCmds(OutputJSON)
+
+	// Lista vazia é um array, não null
+	if got := strings.TrimSpace(runCmd(t, c, out, "list")); got != "[]" {
+		t.Fatalf("list sem itens = %s, esperado []", got)
+	}
+
+	// Comandos de um item imprimem um objeto
+	var created item.Item
+	if err := json.Unmarshal([]byte(runCmd(t, c, out, "create", "--code", "ITEM001", "--title", "Caneta", "--price", "2.5")), &created); err != nil {
+		t.Fatalf("create não imprimiu um objeto: %v", err)
+	}
+	if created.ID == 0 || created.Code != "ITEM001" {
+		t.Fatalf("create = %+v", created)
+	}
+	var got item.Item
+	if err := json.Unmarshal([]byte(runCmd(t, c, out, "get", "1")), &got); err != nil || got.ID != created.ID {
+		t.Fatalf("get = %+v, %v; esperado o item %d", got, err, created.ID)
+	}
+
+	// Lista com um único item continua sendo um array
+	var list []item.Item
+	if err := json.Unmarshal([]byte(runCmd(t, c, out, "list")), &list); err != nil || len(list) != 1 {
+		t.Fatalf("list com um item = %+v, %v; esperado um array de 1 item", list, err)
+	}
+
+	runCmd(t, c, out, "delete", "1")
+	var trash []item.Item
+	if err := json.Unmarshal([]byte(runCmd(t, c, out, "trash")), &trash); err != nil || len(trash) != 1 {
+		t.Fatalf("trash = %+v, %v; esperado um array de 1 item", trash, err)
+	}
+	var restored item.Item
+	if err := json.Unmarshal([]byte(runCmd(t, c, out, "restore", "1")), &restored); err != nil || restored.Deleted() {
+		t.Fatalf("restore = %+v, %v", restored, err)
+	}
+}
+
+func TestTableOutput(t *testing.T) {
+	c, out := newTestCmds(OutputTable)
+	runCmd(t, c, out, "create", "--code", "ITEM001", "--title", "Caneta", "--price", "2.5", "--stock", "10")
+
+	lines := strings.Split(strings.TrimSpace(runCmd(t, c, out, "list")), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("list = %q, esperado cabeçalho e uma linha", lines)
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "ID CODE TITLE PRICE STOCK STATUS" {
+		t.Fatalf("cabeçalho = %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "1 ITEM001 Caneta 2.50 10 active" {
+		t.Fatalf("linha = %q", lines[1])
+	}
+
+	if got := runCmd(t, c, out, "delete", "1"); got != "item deletado com sucesso\n" {
+		t.Fatalf("delete = %q", got)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	c, _ := newTestCmds(OutputTable)
+	ctx := context.Background()
+
+	cases := []struct {
+		args []string
+		want string // Trecho esperado na mensagem de erro
+	}{
+		{nil, "informe um subcomando"},
+		{[]string{"bogus"}, "subcomando desconhecido"},
+		{[]string{"get"}, "informe o ID"},
+		{[]string{"get", "abc"}, "ID do item inválido"},
+		{[]string{"create", "--bogus"}, "flag provided but not defined"},
+	}
+	for _, tc := range cases {
+		if err := c.Run(ctx, tc.args); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v: erro %v, esperado contendo %q", tc.args, err, tc.want)
+		}
+	}
+
+	// Erros do caso de uso chegam intactos, para o main decidir a mensagem
+	if err := c.Run(ctx, []string{"get", "99"}); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("get de item inexistente: esperado ErrNotFound, obtido %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	core "api/internal/core" // Casos de uso (AuthUsecasePort)
+	"api/internal/core/auth" // Papéis aceitos nas chaves
+)
+
+/*
+keyCmds agrupa os subcomandos `keys ...` da CLI, que gerenciam as chaves de API da REST.
+
+A chave completa só aparece na saída de `keys create`: o banco guarda apenas o prefixo
+e o hash, então uma chave perdida precisa ser revogada e recriada.
+*/
+type keyCmds struct {
+	auth   core.AuthUsecasePort // Caso de uso de autenticação (chaves de API)
+	out    io.Writer            // Destino da saída (normalmente os.Stdout)
+	output string               // Formato de saída: OutputTable ou OutputJSON
+}
+
+/*
+NewKeyCmds cria os comandos de chaves de API recebendo o caso de uso, o destino
+da saída e o formato desejado (OutputTable ou OutputJSON).
+*/
+func NewKeyCmds(u core.AuthUsecasePort, out io.Writer, output string) *keyCmds {
+	return &keyCmds{
+		auth:   u,
+		out:    out,
+		output: output,
+	}
+}
+
+/*
+Run despacha os argumentos para o subcomando correspondente.
+
+Exemplos:
+
+	keys create --name "integração ERP" --roles catalog,warehouse
+	keys list
+	keys revoke 3
+*/
+func (c *keyCmds) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("informe um subcomando: create, list ou revoke")
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("create", flag.ContinueOnError)
+		name := fs.String("name", "", "nome descritivo da chave (obrigatório)")
+		roles := fs.String("roles", "", "papéis da chave, separados por vírgula: "+strings.Join(auth.Roles(), ", ")+" (obrigatório)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return c.create(ctx, *name, splitList(*roles))
+	case "list":
+		return c.list(ctx)
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New("uso: keys revoke <id>")
+		}
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("id inválido %q", args[1])
+		}
+		if err := c.auth.RevokeAPIKey(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "chave %d revogada\n", id)
+		return nil
+	default:
+		return fmt.Errorf("subcomando desconhecido: %q", args[0])
+	}
+}
+
+// createdKey é a saída de `keys create --output json`: a chave gravada mais o segredo.
+type createdKey struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Roles  []string `json:"roles"`
+	Key    string   `json:"key"`
+}
+
+// create gera a chave e imprime o segredo, que não pode ser recuperado depois.
+func (c *keyCmds) create(ctx context.Context, name string, roles []string) error {
+	k, key, err := c.auth.CreateAPIKey(ctx, name, roles)
+	if err != nil {
+		return err
+	}
+	if c.output == OutputJSON {
+		return writeJSON(c.out, createdKey{k.ID, k.Name, k.Prefix, k.Roles, key})
+	}
+	fmt.Fprintf(c.out, "chave %d criada (%s, papéis %s)\n%s\nguarde a chave agora: ela não será mostrada novamente\n", k.ID, k.Name, strings.Join(k.Roles, ","), key)
+	return nil
+}
+
+// list imprime as chaves cadastradas, sem o segredo.
+func (c *keyCmds) list(ctx context.Context) error {
+	keys, err := c.auth.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	if c.output == OutputJSON {
+		if keys == nil {
+			keys = []auth.APIKey{} // Lista vazia em vez de null
+		}
+		return writeJSON(c.out, keys)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tROLES\tCREATED_AT\tREVOKED_AT")
+	for _, k := range keys {
+		revoked := ""
+		if k.Revoked() {
+			revoked = k.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", k.ID, k.Name, k.Prefix, strings.Join(k.Roles, ","), k.CreatedAt.Format(time.RFC3339), revoked)
+	}
+	return w.Flush()
+}
+
+// splitList separa uma lista informada em uma flag (ex: "catalog, warehouse"), ignorando espaços e itens vazios.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"api/internal/platform/migrations"
+)
+
+/*
+migrateCmds agrupa os subcomandos `migrate ...` da CLI.
+
+Diferente de itemCmds, trabalha direto sobre o banco (via migrations.Migrator),
+pois migrações não fazem parte da regra de negócio.
+*/
+type migrateCmds struct {
+	migrator *migrations.Migrator // Aplica/desfaz as migrações embutidas no binário
+	out      io.Writer            // Destino da saída (normalmente os.Stdout)
+	output   string               // Formato de saída: OutputTable ou OutputJSON
+}
+
+/*
+NewMigrateCmds cria os comandos de migração recebendo o Migrator, o destino da saída
+e o formato desejado (OutputTable ou OutputJSON).
+*/
+func NewMigrateCmds(m *migrations.Migrator, out io.Writer, output string) *migrateCmds {
+	return &migrateCmds{
+		migrator: m,
+		out:      out,
+		output:   output,
+	}
+}
+
+/*
+Run despacha os argumentos para o subcomando correspondente.
+
+Exemplos:
+
+	migrate up
+	migrate down --steps 2
+	migrate status
+*/
+func (c *migrateCmds) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("informe um subcomando: up, down ou status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := c.migrator.Up(ctx)
+		c.report("aplicada", applied)
+		return err
+	case "down":
+		fs := flag.NewFlagSet("down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "quantidade de migrações a desfazer")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *steps < 1 {
+			return errors.New("--steps deve ser maior que zero")
+		}
+		reverted, err := c.migrator.Down(ctx, *steps)
+		c.report("desfeita", reverted)
+		return err
+	case "status":
+		return c.status(ctx)
+	default:
+		return fmt.Errorf("subcomando desconhecido: %q", args[0])
+	}
+}
+
+// report imprime uma linha por migração aplicada ou desfeita.
+func (c *migrateCmds) report(verb string, ms []migrations.Migration) {
+	if len(ms) == 0 {
+		fmt.Fprintln(c.out, "nenhuma migração", verb)
+		return
+	}
+	for _, m := range ms {
+		fmt.Fprintf(c.out, "migração %s: %04d_%s\n", verb, m.Version, m.Name)
+	}
+}
+
+// migrationStatus é a linha de `migrate status --output json`.
+type migrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Modified  bool       `json:"modified"`
+}
+
+// status imprime todas as migrações conhecidas e se já foram aplicadas.
+func (c *migrateCmds) status(ctx context.Context) error {
+	sts, err := c.migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.output == OutputJSON {
+		out := make([]migrationStatus, 0, len(sts))
+		for _, st := range sts {
+			out = append(out, migrationStatus{st.Version, st.Name, st.Applied, st.AppliedAt, st.Modified})
+		}
+		return writeJSON(c.out, out)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED_AT")
+	for _, st := range sts {
+		state, at := "pending", ""
+		if st.Applied {
+			state, at = "applied", st.AppliedAt.Format(time.RFC3339)
+		}
+		if st.Modified {
+			state = "modified"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, state, at)
+	}
+	return w.Flush()
+}
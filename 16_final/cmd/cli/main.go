@@ -1 +1,187 @@
 package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"os/user"
+	"strings"
+
+	cmds "api/cmd/cli/cmds"                 // Subcomandos da CLI
+	core "api/internal/core"                // Camada de lógica de negócio
+	"api/internal/core/auth"                // Principal e papéis do operador
+	backend "api/internal/platform/backend" // Seleção do repositório (mysql, memory ou sqlite)
+	"api/internal/platform/migrations"      // Migrações versionadas do schema
+	"api/pkg/config"                        // Configuração tipada (arquivo + variáveis de ambiente)
+)
+
+const usage = `Uso: cli [flags] items|migrate|keys <subcomando> [argumentos]
+
+Subcomandos de items:
+  list                              lista todos os itens
+  get <id>                          mostra um item
+  create --code X --title Y ...     cria um item
+  update <id> [--stock N ...]       altera apenas os campos informados
+  delete <id>                       move um item para a lixeira
+  trash                             lista os itens da lixeira
+  restore <id>                      tira um item da lixeira
+  purge [--retention 720h]          remove de vez os itens há mais tempo na lixeira
+  import [--mapping JSON] [--dry-run] <arquivo.csv|xlsx>
+                                    importa itens de uma planilha (upsert pelo código,
+                                    como POST /imports)
+  export [--format json|csv|ndjson|xlsx] [--file F] [--gzip] [--status S] [--q texto]
+                                    exporta os itens (lidos do banco aos poucos)
+
+Subcomandos de migrate (apenas mysql e sqlite):
+  up                                aplica as migrações pendentes
+  down [--steps N]                  desfaz as últimas N migrações (padrão 1)
+  status                            lista as migrações e se já foram aplicadas
+
+Subcomandos de keys (chaves de API da REST; apenas mysql e sqlite):
+  create --name N --roles R[,R]     cria uma chave e mostra o segredo (uma única vez);
+                                    papéis: viewer, warehouse, catalog, manager, admin
+  list                              lista as chaves, sem o segredo
+  revoke <id>                       revoga uma chave
+
+Os comandos de items seguem as mesmas permissões da API, conforme os papéis em --roles.
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+/*
+run executa a CLI com os argumentos informados (sem o nome do programa) e
+retorna o código de saída: 0 em caso de sucesso, 1 se o comando falhar e
+2 para argumentos inválidos (como o pacote flag).
+
+A saída dos comandos vai para stdout; o uso e as mensagens de erro, para stderr.
+*/
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("cli", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFlag := flags.String("config", "", "arquivo de configuração .yaml ou .toml (opcional)")
+	repoFlag := flags.String("repo", "", "repositório utilizado: mysql, memory ou sqlite (padrão: o da configuração)")
+	outputFlag := flags.String("output", cmds.OutputTable, "formato de saída: table ou json")
+	rolesFlag := flags.String("roles", auth.RoleAdmin, "papéis do operador nos comandos de items, separados por vírgula")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	args = flags.Args()
+	if len(args) == 0 || (args[0] != "items" && args[0] != "migrate" && args[0] != "keys") {
+		flags.Usage()
+		return 2
+	}
+
+	// fail imprime a mensagem de erro em stderr e devolve o código de saída 1
+	fail := func(format string, a ...any) int {
+		fmt.Fprintf(stderr, "erro: "+format+"\n", a...)
+		return 1
+	}
+
+	/*
+		Carrega a configuração; a flag --repo, se informada, tem
+		prioridade sobre REPOSITORY_BACKEND e o arquivo.
+	*/
+	cfg, err := config.Load(*configFlag)
+	if err != nil {
+		return fail("não foi possível carregar a configuração: %v", err)
+	}
+	if *repoFlag != "" {
+		cfg.Repository = *repoFlag
+		if err := cfg.Validate(); err != nil {
+			return fail("%v", err)
+		}
+	}
+
+	/*
+		Inicializa o repositório escolhido em `repository` (mysql, memory ou sqlite).
+		O pacote backend encapsula a conexão com o banco.
+	*/
+	store, err := backend.New(cfg)
+	if err != nil {
+		return fail("não foi possível configurar o repositório %s: %v", cfg.Repository, err)
+	}
+	// Fecha a conexão com o banco ao encerrar o comando
+	defer store.Close()
+
+	// Ctrl+C cancela o contexto, interrompendo a operação em andamento no banco
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var runner interface {
+		Run(ctx context.Context, args []string) error
+	}
+	switch args[0] {
+	case "items":
+		/*
+			O operador entra no contexto como um principal com os papéis de --roles, para
+			que os casos de uso apliquem a mesma política de permissões da REST e do gRPC.
+		*/
+		p, err := operator(*rolesFlag)
+		if err != nil {
+			return fail("%v", err)
+		}
+		ctx = auth.WithPrincipal(ctx, p)
+
+		// Repositório -> caso de uso -> comandos (injeção de dependência)
+		usecase := core.NewItemUsecase(store.Items)
+		imports := core.NewImportUsecase(store.Items)
+		runner = cmds.NewItemCmds(usecase, imports, stdout, *outputFlag, cfg.TrashRetention.Duration)
+	case "migrate":
+		// Migrações trabalham direto sobre a conexão com o banco
+		if store.DB == nil {
+			return fail("o repositório %s não usa migrações", store.Name)
+		}
+		migrator, err := migrations.New(store.DB, store.Name)
+		if err != nil {
+			return fail("não foi possível carregar as migrações: %v", err)
+		}
+		runner = cmds.NewMigrateCmds(migrator, stdout, *outputFlag)
+	case "keys":
+		// Chaves em memória sumiriam ao fim do comando, sem chegar à API
+		if store.DB == nil {
+			return fail("o repositório %s não guarda chaves de API", store.Name)
+		}
+		runner = cmds.NewKeyCmds(core.NewAuthUsecase(store.APIKeys, nil), stdout, *outputFlag)
+	}
+
+	if err := runner.Run(ctx, args[1:]); err != nil {
+		return fail("%v", err)
+	}
+	return 0
+}
+
+/*
+operator monta o principal do operador da CLI: o usuário do sistema operacional,
+com os papéis informados em --roles (separados por vírgula).
+*/
+func operator(roles string) (auth.Principal, error) {
+	p := auth.Principal{Type: auth.PrincipalCLI, ID: os.Getenv("USER")}
+	if u, err := user.Current(); err == nil {
+		p.ID = u.Username
+	}
+	for _, r := range strings.Split(roles, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			p.Roles = append(p.Roles, r)
+		}
+	}
+	if err := auth.ValidateRoles(p.Roles); err != nil {
+		return auth.Principal{}, fmt.Errorf("--roles inválido: %w", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunExitCodes(t *testing.T) {
+	cases := []struct {
+		name       string
+		args       []string
+		code       int
+		stdout     string // Saída esperada (exata), se não vazia
+		stderrHint string // Trecho esperado em stderr, se não vazio
+	}{
+		{"sucesso", []string{"--repo", "memory", "--output", "json", "items", "list"}, 0, "[]\n", ""},
+		{"sem subcomando", nil, 2, "", "Uso: cli"},
+		{"grupo desconhecido", []string{"--repo", "memory", "bogus"}, 2, "", "Uso: cli"},
+		{"flag desconhecida", []string{"--bogus", "items", "list"}, 2, "", "flag provided but not defined"},
+		{"ajuda", []string{"-h"}, 0, "", "Uso: cli"},
+		{"item inexistente", []string{"--repo", "memory", "items", "get", "1"}, 1, "", "erro: "},
+		{"papel inválido", []string{"--repo", "memory", "--roles", "root", "items", "list"}, 1, "", "--roles inválido"},
+		{"repositório inválido", []string{"--repo", "bogus", "items", "list"}, 1, "", "erro: "},
+		{"migrate sem banco", []string{"--repo", "memory", "migrate", "status"}, 1, "", "não usa migrações"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tc.args, &stdout, &stderr); code != tc.code {
+				t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, tc.code, stderr.String())
+			}
+			if tc.stdout != "" && stdout.String() != tc.stdout {
+				t.Fatalf("stdout = %q, esperado %q", stdout.String(), tc.stdout)
+			}
+			if !strings.Contains(stderr.String(), tc.stderrHint) {
+				t.Fatalf("stderr = %q, esperado contendo %q", stderr.String(), tc.stderrHint)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"api/internal/core/auth"
+	"api/internal/core/domainerr"
+)
+
+/*
+APIKeyMetadata é a chave de metadata alternativa a `authorization` para enviar a
+chave de API (o equivalente gRPC do header X-API-Key da REST).
+*/
+const APIKeyMetadata = "x-api-key"
+
+/*
+Authenticator valida uma credencial e devolve o principal que ela identifica.
+É satisfeito por core.AuthUsecasePort.
+*/
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (auth.Principal, error)
+}
+
+/*
+UnaryAuth é o interceptor que exige uma credencial válida em toda chamada unária.
+
+A credencial é lida da metadata `authorization: Bearer <chave ou token>` ou de
+`x-api-key: <chave>`, como na REST. Sem credencial, ou com uma credencial inválida,
+a chamada falha com codes.Unauthenticated. O principal é colocado no contexto
+(auth.WithPrincipal), e as permissões são conferidas pelos casos de uso.
+*/
+func UnaryAuth(a Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		credential := credentialFrom(ctx)
+		if credential == "" {
+			return nil, toStatus(domainerr.Unauthenticatedf("informe uma chave de API (x-api-key) ou um token (authorization: Bearer)"))
+		}
+		p, err := a.Authenticate(ctx, credential)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		return next(auth.WithPrincipal(ctx, p), req)
+	}
+}
+
+// credentialFrom extrai a credencial da metadata `authorization` (esquema Bearer) ou de x-api-key.
+func credentialFrom(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if scheme, value, ok := strings.Cut(v, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(value)
+		}
+	}
+	if keys := md.Get(APIKeyMetadata); len(keys) > 0 {
+		return strings.TrimSpace(keys[0])
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"api/cmd/grpc/pb"
+	"api/internal/core"
+	"api/internal/core/auth"
+	"api/internal/core/item"
+)
+
+// TestUnaryAuth confere a autenticação pela metadata e as permissões aplicadas pelo caso de uso.
+func TestUnaryAuth(t *testing.T) {
+	authn := core.NewAuthUsecase(auth.NewMapRepository(), nil)
+	keys := map[string]string{}
+	for _, role := range []string{auth.RoleWarehouse, auth.RoleManager} {
+		_, key, err := authn.CreateAPIKey(context.Background(), role, []string{role})
+		if err != nil {
+			t.Fatalf("CreateAPIKey(%s): %v", role, err)
+		}
+		keys[role] = key
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnaryInterceptor(UnaryAuth(authn)))
+	pb.RegisterItemServiceServer(server, NewHandler(core.NewItemUsecase(item.NewMapRepository())))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("falha ao conectar no bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewItemServiceClient(conn)
+	save := &pb.SaveItemRequest{Item: &pb.Item{Code: "ITEM001", Title: "Caneta", Price: 2.5}}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want codes.Code
+	}{
+		{"sem credencial", nil, codes.Unauthenticated},
+		{"chave inválida", metadata.Pairs(APIKeyMetadata, "inv_invalida"), codes.Unauthenticated},
+		{"warehouse não cria item", metadata.Pairs(APIKeyMetadata, keys[auth.RoleWarehouse]), codes.PermissionDenied},
+		{"manager cria item", metadata.Pairs("authorization", "Bearer "+keys[auth.RoleManager]), codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+			if _, err := client.Save(ctx, save); status.Code(err) != tt.want {
+				t.Fatalf("Save = %v, esperado %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"api/cmd/grpc/pb"
+	"api/internal/core"
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+/*
+handler implementa o serviço gRPC `ItemService` definido em `pb/item.proto`.
+
+Assim como o handler REST, ele não conhece o repositório: depende apenas da
+interface core.ItemUsecasePort, garantindo que as mesmas regras de negócio
+sejam aplicadas independentemente do protocolo de entrada.
+*/
+type handler struct {
+	pb.UnimplementedItemServiceServer
+
+	core core.ItemUsecasePort // Interface da camada de caso de uso relacionada a "item"
+}
+
+/*
+NewHandler cria uma nova instância do handler gRPC, recebendo como dependência
+um objeto que implementa a interface ItemUsecasePort.
+*/
+func NewHandler(u core.ItemUsecasePort) pb.ItemServiceServer {
+	return &handler{
+		core: u,
+	}
+}
+
+/*
+Save lida com a chamada gRPC para salvar um novo item.
+
+O ID enviado é ignorado: o item salvo volta na resposta com o ID gerado.
+Retorna InvalidArgument se o item não for enviado.
+*/
+func (h *handler) Save(ctx context.Context, req *pb.SaveItemRequest) (*pb.SaveItemResponse, error) {
+	if req.GetItem() == nil {
+		return nil, status.Error(codes.InvalidArgument, "item é obrigatório")
+	}
+
+	saved, err := h.core.SaveItem(ctx, fromProto(req.GetItem()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.SaveItemResponse{Item: toProto(saved)}, nil
+}
+
+/*
+List lida com a chamada gRPC para listar os itens cadastrados, ordenados por ID.
+
+A listagem é paginada como GET /items: `limit` zero usa item.DefaultLimit, acima de
+item.MaxLimit é reduzido, e a próxima página é pedida com o `next_cursor` da resposta.
+Retorna InvalidArgument se o limite for negativo ou o cursor for inválido.
+*/
+func (h *handler) List(ctx context.Context, req *pb.ListItemsRequest) (*pb.ListItemsResponse, error) {
+	f := item.ListFilter{Limit: item.DefaultLimit}
+	if limit := int(req.GetLimit()); limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limit inválido: %d (use de 1 a %d)", limit, item.MaxLimit)
+	} else if limit > 0 {
+		f.Limit = min(limit, item.MaxLimit)
+	}
+	if cursor := req.GetCursor(); cursor != "" {
+		var err error
+		if f.Offset, err = item.DecodeCursor(cursor); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "cursor inválido: %q", cursor)
+		}
+	}
+
+	page, err := h.core.ListItems(ctx, f)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListItemsResponse{
+		Items:      make([]*pb.Item, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+		Total:      int64(page.Total),
+	}
+	for _, it := range page.Items {
+		resp.Items = append(resp.Items, toProto(it))
+	}
+
+	return resp, nil
+}
+
+/*
+Get lida com a chamada gRPC para buscar um único item pelo ID.
+
+Retorna NotFound caso o item não exista.
+*/
+func (h *handler) Get(ctx context.Context, req *pb.GetItemRequest) (*pb.GetItemResponse, error) {
+	it, err := h.core.GetItem(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.GetItemResponse{Item: toProto(it)}, nil
+}
+
+/*
+Update lida com a chamada gRPC para atualizar um item existente.
+
+Retorna o item atualizado, com a nova versão.
+*/
+func (h *handler) Update(ctx context.Context, req *pb.UpdateItemRequest) (*pb.UpdateItemResponse, error) {
+	if req.GetItem() == nil {
+		return nil, status.Error(codes.InvalidArgument, "item é obrigatório")
+	}
+
+	updated, err := h.core.UpdateItem(ctx, fromProto(req.GetItem()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.UpdateItemResponse{Item: toProto(updated)}, nil
+}
+
+/*
+Delete lida com a chamada gRPC para deletar um item pelo ID.
+*/
+func (h *handler) Delete(ctx context.Context, req *pb.DeleteItemRequest) (*pb.DeleteItemResponse, error) {
+	if err := h.core.DeleteItem(ctx, int(req.GetId()), int(req.GetVersion())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.DeleteItemResponse{}, nil
+}
+
+/*
+toStatus converte os erros retornados pelo caso de uso em status gRPC.
+
+Mapeamento:
+- domainerr.ErrNotFound           → codes.NotFound
+- domainerr.ErrAlreadyExists      → codes.AlreadyExists
+- domainerr.ErrValidation         → codes.InvalidArgument
+- domainerr.ErrConflict           → codes.FailedPrecondition (ex: estoque insuficiente)
+- domainerr.ErrPreconditionFailed → codes.Aborted (conflito de concorrência)
+- domainerr.ErrUnauthenticated    → codes.Unauthenticated (credencial ausente ou inválida)
+- domainerr.ErrForbidden          → codes.PermissionDenied (falta uma permissão ao principal)
+- context.DeadlineExceeded        → codes.DeadlineExceeded (prazo do cliente esgotado)
+- context.Canceled                → codes.Canceled (o cliente cancelou a chamada)
+- qualquer outro erro             → codes.Internal com mensagem genérica (o erro original só vai para o log)
+*/
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, domainerr.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domainerr.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domainerr.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domainerr.ErrConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domainerr.ErrPreconditionFailed):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domainerr.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, domainerr.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		log.Printf("grpc: erro interno: %v", err)
+		return status.Error(codes.Internal, "erro interno do servidor")
+	}
+}
+
+// toProto converte a entidade item.Item para a mensagem protobuf.
+func toProto(it item.Item) *pb.Item {
+	return &pb.Item{
+		Id:          int64(it.ID),
+		Code:        it.Code,
+		Title:       it.Title,
+		Description: it.Description,
+		Price:       it.Price,
+		Stock:       int64(it.Stock),
+		Status:      it.Status,
+		Version:     int64(it.Version),
+		CreatedAt:   timestamppb.New(it.CreatedAt),
+		UpdatedAt:   timestamppb.New(it.UpdatedAt),
+	}
+}
+
+// fromProto converte a mensagem protobuf para a entidade item.Item.
+func fromProto(p *pb.Item) item.Item {
+	return item.Item{
+		ID:          int(p.GetId()),
+		Code:        p.GetCode(),
+		Title:       p.GetTitle(),
+		Description: p.GetDescription(),
+		Price:       p.GetPrice(),
+		Stock:       int(p.GetStock()),
+		Status:      p.GetStatus(),
+		Version:     int(p.GetVersion()),
+		CreatedAt:   p.GetCreatedAt().AsTime(),
+		UpdatedAt:   p.GetUpdatedAt().AsTime(),
+	}
+}
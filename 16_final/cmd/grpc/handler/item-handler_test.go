@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"api/cmd/grpc/pb"
+	"api/internal/core"
+	"api/internal/core/item"
+)
+
+// newTestClient sobe o ItemService em memória (bufconn) sobre um MapRepository.
+func newTestClient(t *testing.T) pb.ItemServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterItemServiceServer(server, NewHandler(core.NewItemUsecase(item.NewMapRepository())))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("falha ao conectar no bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewItemServiceClient(conn)
+}
+
+func TestItemServiceCRUD(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	// O ID enviado é ignorado: o repositório gera o seu e a resposta o devolve
+	saved, err := client.Save(ctx, &pb.SaveItemRequest{Item: &pb.Item{Id: 99, Code: "ITEM001", Title: "Caneta", Price: 2.5, Stock: 10}})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	id := saved.GetItem().GetId()
+	if id == 0 || id == 99 || saved.Item.Version != 1 || saved.Item.Status != "active" {
+		t.Fatalf("Save retornou item inesperado: %+v", saved.Item)
+	}
+
+	got, err := client.Get(ctx, &pb.GetItemRequest{Id: id})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Item.Code != "ITEM001" || got.Item.Stock != 10 {
+		t.Fatalf("Get retornou item inesperado: %+v", got.Item)
+	}
+
+	updated, err := client.Update(ctx, &pb.UpdateItemRequest{Item: &pb.Item{Id: id, Code: "ITEM001", Title: "Caneta azul", Stock: 5, Status: "active"}})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Item.Title != "Caneta azul" || updated.Item.Version != 2 {
+		t.Fatalf("Update retornou item inesperado: %+v", updated.Item)
+	}
+
+	list, err := client.List(ctx, &pb.ListItemsRequest{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Title != "Caneta azul" {
+		t.Fatalf("List retornou itens inesperados: %+v", list.Items)
+	}
+
+	if _, err := client.Delete(ctx, &pb.DeleteItemRequest{Id: id}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func TestItemServiceListPages(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	for _, code := range []string{"ITEM001", "ITEM002", "ITEM003"} {
+		if _, err := client.Save(ctx, &pb.SaveItemRequest{Item: &pb.Item{Code: code, Title: "Caneta"}}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	// Percorre o catálogo de 2 em 2 seguindo o next_cursor
+	var seen []string
+	req := &pb.ListItemsRequest{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages == 3 {
+			t.Fatalf("next_cursor não terminou: %v", seen)
+		}
+		resp, err := client.List(ctx, req)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if resp.Total != 3 || len(resp.Items) > 2 {
+			t.Fatalf("List = %d itens, total %d", len(resp.Items), resp.Total)
+		}
+		for _, it := range resp.Items {
+			seen = append(seen, it.Code)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		req.Cursor = resp.NextCursor
+	}
+	if fmt.Sprint(seen) != "[ITEM001 ITEM002 ITEM003]" {
+		t.Fatalf("itens paginados = %v", seen)
+	}
+}
+
+func TestItemServiceStatusCodes(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	saved, err := client.Save(ctx, &pb.SaveItemRequest{Item: &pb.Item{Code: "ITEM007", Title: "Lápis"}})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	id := saved.GetItem().GetId()
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"get inexistente", func() error {
+			_, err := client.Get(ctx, &pb.GetItemRequest{Id: 42})
+			return err
+		}, codes.NotFound},
+		{"update inexistente", func() error {
+			_, err := client.Update(ctx, &pb.UpdateItemRequest{Item: &pb.Item{Id: 42, Code: "ITEM042", Title: "Borracha", Status: "active"}})
+			return err
+		}, codes.NotFound},
+		{"delete inexistente", func() error {
+			_, err := client.Delete(ctx, &pb.DeleteItemRequest{Id: 42})
+			return err
+		}, codes.NotFound},
+		{"save sem item", func() error {
+			_, err := client.Save(ctx, &pb.SaveItemRequest{})
+			return err
+		}, codes.InvalidArgument},
+		{"update com versão desatualizada", func() error {
+			_, err := client.Update(ctx, &pb.UpdateItemRequest{Item: &pb.Item{Id: id, Code: "ITEM007", Title: "Lápis", Status: "active", Version: 5}})
+			return err
+		}, codes.Aborted},
+		{"delete com versão desatualizada", func() error {
+			_, err := client.Delete(ctx, &pb.DeleteItemRequest{Id: id, Version: 5})
+			return err
+		}, codes.Aborted},
+		{"save com código duplicado", func() error {
+			_, err := client.Save(ctx, &pb.SaveItemRequest{Item: &pb.Item{Code: "ITEM007", Title: "Lápis"}})
+			return err
+		}, codes.AlreadyExists},
+		{"list com limite negativo", func() error {
+			_, err := client.List(ctx, &pb.ListItemsRequest{Limit: -1})
+			return err
+		}, codes.InvalidArgument},
+		{"list com cursor inválido", func() error {
+			_, err := client.List(ctx, &pb.ListItemsRequest{Cursor: "nao-e-um-cursor"})
+			return err
+		}, codes.InvalidArgument},
+		{"save com preço negativo e status inválido", func() error {
+			_, err := client.Save(ctx, &pb.SaveItemRequest{Item: &pb.Item{Code: "ITEM009", Title: "Cola", Price: -1, Status: "sold"}})
+			return err
+		}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Fatalf("esperava %v, recebeu %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestToStatusHidesInternalErrors garante que erros inesperados (ex: do driver) não vazam para o cliente.
+func TestToStatusHidesInternalErrors(t *testing.T) {
+	st := status.Convert(toStatus(errors.New("Error 1146 (42S02): Table 'inventory.items' doesn't exist")))
+	if st.Code() != codes.Internal || st.Message() != "erro interno do servidor" {
+		t.Fatalf("toStatus = %v %q; esperado Internal com mensagem genérica", st.Code(), st.Message())
+	}
+}
@@ -1 +1,105 @@
 package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+
+	handler "api/cmd/grpc/handler"          // Implementação do serviço gRPC ItemService
+	"api/cmd/grpc/pb"                       // Código gerado a partir de pb/item.proto
+	core "api/internal/core"                // Camada de lógica de negócio
+	"api/internal/core/auth"                // Principal e validação de credenciais
+	backend "api/internal/platform/backend" // Seleção do repositório (mysql, memory ou sqlite)
+	"api/internal/platform/jwt"             // Validação de tokens JWT (HS256/RS256)
+	"api/internal/platform/migrations"      // Migrações versionadas do schema
+	"api/pkg/config"                        // Configuração tipada (arquivo + variáveis de ambiente)
+)
+
+func main() {
+	// Carrega a configuração (padrões -> arquivo -> variáveis de ambiente)
+	configFlag := flag.String("config", "", "arquivo de configuração .yaml ou .toml (opcional)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFlag)
+	if err != nil {
+		log.Fatalf("Não foi possível carregar a configuração: %v", err)
+	}
+
+	/*
+		Inicializa o repositório de itens escolhido em `repository` (mysql, memory ou sqlite).
+		O pacote backend encapsula a conexão com o banco; se der erro, o programa encerra.
+	*/
+	store, err := backend.New(cfg)
+	if err != nil {
+		log.Fatalf("Não foi possível configurar o repositório %s: %v", cfg.Repository, err)
+	}
+	// Fecha a conexão com o banco ao encerrar o programa
+	defer store.Close()
+	repo := store.Items
+
+	/*
+		Com `migrate_on_start` (MIGRATE_ON_START=true), aplica as migrações pendentes
+		antes de atender requisições. No MySQL a aplicação acontece sob uma trava
+		consultiva, então várias réplicas podem subir ao mesmo tempo com segurança.
+	*/
+	if cfg.MigrateOnStart && store.DB != nil {
+		migrator, err := migrations.New(store.DB, store.Name)
+		if err != nil {
+			log.Fatalf("Não foi possível carregar as migrações: %v", err)
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Fatalf("Não foi possível aplicar as migrações: %v", err)
+		}
+		for _, m := range applied {
+			log.Printf("Migração aplicada: %04d_%s", m.Version, m.Name)
+		}
+	}
+
+	// Repositório -> caso de uso -> handler (injeção de dependência)
+	usecase := core.NewItemUsecase(repo)
+
+	/*
+		Autenticação (auth.enabled), com as mesmas credenciais da REST: chaves de API
+		(metadata x-api-key ou authorization) e tokens JWT. As permissões de cada papel
+		são conferidas pelos casos de uso, a partir do principal colocado no contexto.
+	*/
+	verifier, err := jwt.New(cfg.Auth.JWT)
+	if err != nil {
+		log.Fatalf("Não foi possível carregar as chaves JWT: %v", err)
+	}
+	var tokens auth.TokenVerifier // Continua nil (JWT desabilitado) se nenhuma chave foi configurada
+	if verifier != nil {
+		tokens = verifier
+	}
+	var opts []grpc.ServerOption
+	if cfg.Auth.Enabled {
+		if tokens == nil && store.DB == nil {
+			log.Fatalf("auth.enabled exige chaves JWT (auth.jwt) no repositório %s, que não guarda chaves de API entre processos", store.Name)
+		}
+		opts = append(opts, grpc.UnaryInterceptor(handler.UnaryAuth(core.NewAuthUsecase(store.APIKeys, tokens))))
+	} else {
+		log.Println("Atenção: autenticação desabilitada (auth.enabled=false); todas as chamadas estão abertas")
+	}
+
+	/*
+		Cria o servidor gRPC e registra o serviço de itens.
+		O handler depende apenas de core.ItemUsecasePort.
+	*/
+	server := grpc.NewServer(opts...)
+	pb.RegisterItemServiceServer(server, handler.NewHandler(usecase))
+
+	// Abre o listener TCP no endereço configurado (padrão :50051)
+	lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+	if err != nil {
+		log.Fatalf("Não foi possível abrir %s: %v", cfg.GRPC.Addr, err)
+	}
+
+	log.Printf("Servidor gRPC iniciado em %s (repositório %s)", cfg.GRPC.Addr, cfg.Repository)
+	if err := server.Serve(lis); err != nil {
+		log.Fatal(err)
+	}
+}
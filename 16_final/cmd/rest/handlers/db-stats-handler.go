@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+dbStatsHandler expõe as estatísticas do pool de conexões do banco (`sql.DBStats`)
+em JSON, para coleta por ferramentas de métricas.
+*/
+type dbStatsHandler struct {
+	stats func() sql.DBStats // Fonte das estatísticas (ex: MySQLClient.Stats ou (*sql.DB).Stats)
+}
+
+// DBStatsResponse é o corpo JSON de GET /metrics/db.
+type DBStatsResponse struct {
+	MaxOpenConnections int   `json:"max_open_connections"` // Limite do pool (0 = ilimitado)
+	OpenConnections    int   `json:"open_connections"`     // Conexões abertas (em uso + ociosas)
+	InUse              int   `json:"in_use"`               // Conexões executando algo agora
+	Idle               int   `json:"idle"`                 // Conexões paradas no pool
+	WaitCount          int64 `json:"wait_count"`           // Total de esperas por uma conexão livre
+	WaitDurationMs     int64 `json:"wait_duration_ms"`     // Tempo total esperando por conexões
+	MaxIdleClosed      int64 `json:"max_idle_closed"`      // Fechadas por excederem max_idle_conns
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"` // Fechadas por excederem conn_max_idle_time
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`  // Fechadas por excederem conn_max_lifetime
+}
+
+/*
+NewDBStatsHandler cria o handler de estatísticas a partir da função que as fornece.
+*/
+func NewDBStatsHandler(stats func() sql.DBStats) *dbStatsHandler {
+	return &dbStatsHandler{stats: stats}
+}
+
+/*
+DBStats responde 200 com um retrato atual do pool de conexões.
+*/
+func (h *dbStatsHandler) DBStats(c *gin.Context) {
+	s := h.stats()
+	c.JSON(http.StatusOK, DBStatsResponse{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDurationMs:     s.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	})
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readyCheckTimeout é o prazo de cada verificação do /readyz (ex: ping no banco).
+const readyCheckTimeout = 2 * time.Second
+
+/*
+ReadinessCheck verifica uma dependência da API (banco, migrações, ...).
+Retorna nil se a dependência estiver pronta.
+*/
+type ReadinessCheck func(ctx context.Context) error
+
+/*
+healthHandler expõe as verificações usadas pelo orquestrador (Docker, Kubernetes):
+
+- /healthz (liveness): o processo está vivo e respondendo;
+- /readyz (readiness): o processo pode receber tráfego (dependências prontas e sem desligamento em curso).
+*/
+type healthHandler struct {
+	checks   map[string]ReadinessCheck // Verificações do /readyz, por nome (ex: "database")
+	draining atomic.Bool               // true depois que o desligamento começou
+}
+
+// HealthResponse é o corpo JSON de /healthz e /readyz.
+type HealthResponse struct {
+	Status string            `json:"status"`           // "ok" ou "unavailable"
+	Checks map[string]string `json:"checks,omitempty"` // Resultado de cada verificação ("ok" ou a mensagem de erro)
+}
+
+/*
+NewHealthHandler cria o handler de saúde com as verificações de prontidão informadas.
+*/
+func NewHealthHandler(checks map[string]ReadinessCheck) *healthHandler {
+	return &healthHandler{checks: checks}
+}
+
+/*
+SetDraining marca o início do desligamento: a partir daqui o /readyz responde 503,
+para que o orquestrador pare de mandar novas requisições enquanto as atuais terminam.
+*/
+func (h *healthHandler) SetDraining() {
+	h.draining.Store(true)
+}
+
+/*
+Healthz responde 200 enquanto o processo estiver de pé (liveness).
+
+Não consulta dependências: um banco fora do ar não deve fazer o orquestrador
+reiniciar a API, apenas tirá-la do balanceamento (ver Readyz).
+*/
+func (h *healthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+/*
+Readyz executa todas as verificações de prontidão.
+
+Retorna:
+- 200 com o resultado de cada verificação, se todas passarem;
+- 503 se alguma falhar ou se o desligamento já tiver começado.
+*/
+func (h *healthHandler) Readyz(c *gin.Context) {
+	resp := HealthResponse{Status: "ok", Checks: map[string]string{}}
+	status := http.StatusOK
+
+	if h.draining.Load() {
+		resp.Checks["shutdown"] = "desligamento em andamento"
+		status = http.StatusServiceUnavailable
+	}
+
+	for name, check := range h.checks {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readyCheckTimeout)
+		err := check(ctx)
+		cancel()
+
+		if err != nil {
+			resp.Checks[name] = err.Error()
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[name] = "ok"
+	}
+
+	if status != http.StatusOK {
+		resp.Status = "unavailable"
+	}
+	c.JSON(status, resp)
+}
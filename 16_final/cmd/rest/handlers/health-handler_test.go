@@ -0,0 +1,66 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	handler "api/cmd/rest/handlers"
+)
+
+func TestReadyz(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("banco fora do ar") }
+
+	tests := []struct {
+		name       string
+		checks     map[string]handler.ReadinessCheck
+		draining   bool
+		wantStatus int
+		wantChecks map[string]string
+	}{
+		{"sem dependências", nil, false, http.StatusOK, map[string]string{}},
+		{"tudo pronto", map[string]handler.ReadinessCheck{"database": ok}, false, http.StatusOK,
+			map[string]string{"database": "ok"}},
+		{"banco fora do ar", map[string]handler.ReadinessCheck{"database": down, "migrations": ok}, false,
+			http.StatusServiceUnavailable, map[string]string{"database": "banco fora do ar", "migrations": "ok"}},
+		{"desligando", map[string]handler.ReadinessCheck{"database": ok}, true, http.StatusServiceUnavailable,
+			map[string]string{"database": "ok", "shutdown": "desligamento em andamento"}},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewHealthHandler(tt.checks)
+			if tt.draining {
+				h.SetDraining()
+			}
+			router := gin.New()
+			router.GET("/readyz", h.Readyz)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, esperado %d (corpo %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+
+			var body handler.HealthResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("corpo inválido: %v", err)
+			}
+			if len(body.Checks) != len(tt.wantChecks) {
+				t.Fatalf("checks = %v, esperado %v", body.Checks, tt.wantChecks)
+			}
+			for name, want := range tt.wantChecks {
+				if body.Checks[name] != want {
+					t.Fatalf("checks[%q] = %q, esperado %q", name, body.Checks[name], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"api/internal/core"
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+	"api/internal/platform/spreadsheet"
+
+	middleware "api/cmd/rest/middlewares"
+)
+
+// maxImportSize é o maior corpo aceito em POST /imports (arquivo e demais campos do formulário).
+const maxImportSize = 32 << 20
+
+/*
+importHandler lida com as requisições de importação de planilhas (/imports).
+Depende apenas de core.ImportUsecasePort, como o handler de itens depende de core.ItemUsecasePort.
+*/
+type importHandler struct {
+	core core.ImportUsecasePort // Interface da camada de caso de uso de importação
+}
+
+// NewImportHandler cria o handler de importação recebendo o caso de uso como dependência.
+func NewImportHandler(u core.ImportUsecasePort) *importHandler {
+	return &importHandler{
+		core: u,
+	}
+}
+
+/*
+importResponse é o corpo de POST /imports e GET /imports/:id: o job com a falha geral
+(se houver) no formato das respostas de erro e o link para o relatório de erros por linha.
+
+Exemplo:
+
+	{
+	  "id": "9f1c...", "status": "done", "filename": "fornecedor.xlsx", "dry_run": false,
+	  "total_rows": 3, "processed_rows": 3, "created": 1, "updated": 1, "unchanged": 0, "failed": 1,
+	  "created_at": "...", "finished_at": "...",
+	  "errors_url": "/imports/9f1c.../errors"
+	}
+*/
+type importResponse struct {
+	item.ImportJob
+	Error     *middleware.ErrorResponse `json:"error,omitempty"`      // Falha geral (status failed)
+	ErrorsURL string                    `json:"errors_url,omitempty"` // Relatório de erros por linha (quando há linhas rejeitadas)
+}
+
+/*
+StartImport lida com POST /imports, que importa itens de uma planilha CSV ou XLSX.
+
+O corpo é multipart/form-data com:
+  - file: o arquivo (até 32 MiB); o formato vem da extensão ou do parâmetro `format` (csv ou xlsx);
+  - mapping (opcional): objeto JSON de coluna do arquivo → campo do item, ex: {"SKU": "code", "Preço": "price"}.
+
+Parâmetros de query:
+  - dry_run=true: apenas valida as linhas, sem gravar nada;
+  - async=true: processa em segundo plano mesmo que o arquivo seja pequeno.
+
+Cada linha é um upsert pelo código (ver core.ImportUsecase). Retorna 200 com o job
+terminado ou, para arquivos grandes, 202 com o job pendente e o header `Location`
+para acompanhá-lo. Arquivo ausente ou ilegível é 400, formato desconhecido é 415 e
+mapeamento inválido é 422.
+*/
+func (h *importHandler) StartImport(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			err = fmt.Errorf("o arquivo passa do limite de %d MiB", maxImportSize>>20)
+		}
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	req := item.ImportRequest{Filename: fh.Filename}
+	if req.DryRun, err = queryBool(c, "dry_run"); err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+	if req.Async, err = queryBool(c, "async"); err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+	if m := c.PostForm("mapping"); m != "" {
+		if err := json.Unmarshal([]byte(m), &req.Mapping); err != nil {
+			c.Error(middleware.BadRequest(fmt.Errorf("mapping deve ser um objeto JSON de coluna para campo: %w", err)))
+			return
+		}
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+	defer f.Close()
+
+	req.Rows, err = spreadsheet.Read(f, c.DefaultQuery("format", spreadsheet.FormatOf(fh.Filename)))
+	if errors.Is(err, spreadsheet.ErrUnsupportedFormat) {
+		c.Error(middleware.UnsupportedMediaType(err))
+		return
+	}
+	if err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	job, err := h.core.StartImport(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if !job.Finished() {
+		c.Header("Location", "/imports/"+job.ID)
+		c.JSON(http.StatusAccepted, toImportResponse(c, job))
+		return
+	}
+	c.JSON(http.StatusOK, toImportResponse(c, job))
+}
+
+/*
+GetImport lida com GET /imports/:id, que mostra o andamento e os contadores da importação.
+Retorna 404 se o job não existir, já tiver expirado ou tiver sido iniciado por outro principal (exceto para admins).
+*/
+func (h *importHandler) GetImport(c *gin.Context) {
+	job, err := h.core.GetImport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, toImportResponse(c, job))
+}
+
+/*
+ImportErrors lida com GET /imports/:id/errors, que baixa o relatório de erros da importação.
+
+O relatório é um CSV (colunas row, code, field e message), com uma linha por problema;
+`row` é o número da linha na planilha enviada, contando o cabeçalho como linha 1.
+Enquanto a importação não termina, responde 409.
+*/
+func (h *importHandler) ImportErrors(c *gin.Context) {
+	job, err := h.core.GetImport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !job.Finished() {
+		c.Error(domainerr.Conflictf("a importação %s ainda está em andamento (%d de %d linhas)", job.ID, job.Processed, job.Total))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%s-errors.csv"`, job.ID))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"row", "code", "field", "message"})
+	for _, e := range job.Errors {
+		w.Write([]string{strconv.Itoa(e.Row), e.Code, e.Field, e.Message})
+	}
+	w.Flush()
+}
+
+// queryBool lê um parâmetro de query booleano (ausente = false).
+func queryBool(c *gin.Context, name string) (bool, error) {
+	v, err := strconv.ParseBool(c.DefaultQuery(name, "false"))
+	if err != nil {
+		return false, fmt.Errorf("%s inválido: %q (use true ou false)", name, c.Query(name))
+	}
+	return v, nil
+}
+
+/*
+toImportResponse monta a importResponse do job. A falha geral é traduzida como no
+ErrorHandler, inclusive com o registro no log quando é um erro interno.
+*/
+func toImportResponse(c *gin.Context, job item.ImportJob) importResponse {
+	resp := importResponse{ImportJob: job}
+	if job.Failed > 0 {
+		resp.ErrorsURL = "/imports/" + job.ID + "/errors"
+	}
+	if job.Err != nil {
+		status, errResp := middleware.ToResponse(job.Err)
+		errResp.RequestID = middleware.GetRequestID(c)
+		if status == http.StatusInternalServerError {
+			log.Printf("request_id=%s importação %s: erro interno: %v", errResp.RequestID, job.ID, job.Err)
+		}
+		resp.Error = &errResp
+	}
+	return resp
+}
@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+
+	handler "api/cmd/rest/handlers"
+	middleware "api/cmd/rest/middlewares"
+	"api/internal/core"
+	"api/internal/core/item"
+)
+
+// importBody é o subconjunto da resposta de /imports usado nos testes.
+type importBody struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	Total     int    `json:"total_rows"`
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Failed    int    `json:"failed"`
+	ErrorsURL string `json:"errors_url"`
+}
+
+// newImportRouter monta um roteador com as rotas de importação e de itens sobre o mesmo repositório.
+func newImportRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	repo := item.NewMapRepository()
+	h := handler.NewHandler(core.NewItemUsecase(repo))
+	imports := handler.NewImportHandler(core.NewImportUsecase(repo))
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/items/code/:code", h.GetItemByCode)
+	router.POST("/imports", imports.StartImport)
+	router.GET("/imports/:id", imports.GetImport)
+	router.GET("/imports/:id/errors", imports.ImportErrors)
+	return router
+}
+
+// upload envia o arquivo em POST /imports (multipart), com o mapeamento opcional.
+func upload(t *testing.T, router http.Handler, query, filename string, data []byte, mapping string) (*httptest.ResponseRecorder, importBody) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, _ := mw.CreateFormFile("file", filename)
+	fw.Write(data)
+	if mapping != "" {
+		mw.WriteField("mapping", mapping)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/imports"+query, &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp importBody
+	if w.Code == http.StatusOK || w.Code == http.StatusAccepted {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("corpo inválido: %s", w.Body)
+		}
+	}
+	return w, resp
+}
+
+func TestImportCSV(t *testing.T) {
+	router := newImportRouter()
+	csv := "\uFEFFSKU;Nome;price;stock\nITEM001;Caneta;2,50;10\nITEM002;;1;x\n"
+
+	// Dry-run: valida sem gravar
+	w, resp := upload(t, router, "?dry_run=true", "itens.csv", []byte(csv), `{"SKU": "code", "Nome": "title"}`)
+	if w.Code != http.StatusOK || resp.Status != "done" || resp.Created != 1 || resp.Failed != 1 {
+		t.Fatalf("dry-run: status %d, corpo %s", w.Code, w.Body)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/code/ITEM001", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("o dry-run gravou o item: status %d", w.Code)
+	}
+
+	w, resp = upload(t, router, "", "itens.csv", []byte(csv), `{"SKU": "code", "Nome": "title"}`)
+	if w.Code != http.StatusOK || resp.Created != 1 || resp.Failed != 1 || resp.ErrorsURL != "/imports/"+resp.ID+"/errors" {
+		t.Fatalf("importação: status %d, corpo %s", w.Code, w.Body)
+	}
+
+	// O relatório de erros é um CSV com o número da linha e o motivo
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, resp.ErrorsURL, nil))
+	want := "row,code,field,message\n3,ITEM002,stock,\"\"\"x\"\" não é um número inteiro\"\n"
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") || w.Body.String() != want {
+		t.Fatalf("relatório: status %d, corpo %q", w.Code, w.Body)
+	}
+}
+
+func TestImportXLSXAsync(t *testing.T) {
+	router := newImportRouter()
+
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"code", "title", "price", "stock"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"ITEM001", "Caneta", 2.5, 10})
+	f.SetSheetRow("Sheet1", "A3", &[]any{"ITEM002", "Lápis", 1, 3})
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatalf("excelize: %v", err)
+	}
+
+	w, resp := upload(t, router, "?async=true", "itens.xlsx", buf.Bytes(), "")
+	if w.Code != http.StatusAccepted || w.Header().Get("Location") != "/imports/"+resp.ID {
+		t.Fatalf("importação assíncrona: status %d, headers %v, corpo %s", w.Code, w.Header(), w.Body)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for resp.Status != "done" {
+		if time.Now().After(deadline) {
+			t.Fatalf("a importação não terminou: %+v", resp)
+		}
+		time.Sleep(10 * time.Millisecond)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/imports/"+resp.ID, nil))
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("GET /imports/%s: status %d, corpo %s", resp.ID, w.Code, w.Body)
+		}
+	}
+	if resp.Total != 2 || resp.Created != 2 || resp.Failed != 0 || resp.ErrorsURL != "" {
+		t.Fatalf("job = %+v", resp)
+	}
+}
+
+func TestImportRejectsInvalidUploads(t *testing.T) {
+	router := newImportRouter()
+	csv := []byte("code,title\nITEM001,Caneta\n")
+
+	tests := []struct {
+		name     string
+		query    string
+		filename string
+		data     []byte
+		mapping  string
+		want     int
+	}{
+		{"formato desconhecido", "", "itens.txt", csv, "", http.StatusUnsupportedMediaType},
+		{"XLSX corrompido", "", "itens.xlsx", csv, "", http.StatusBadRequest},
+		{"mapeamento não é JSON", "", "itens.csv", csv, "SKU=code", http.StatusBadRequest},
+		{"mapeamento para campo inexistente", "", "itens.csv", csv, `{"title": "nome"}`, http.StatusUnprocessableEntity},
+		{"arquivo sem linhas de dados", "", "itens.csv", []byte("code,title\n"), "", http.StatusUnprocessableEntity},
+		{"dry_run inválido", "?dry_run=talvez", "itens.csv", csv, "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w, _ := upload(t, router, tt.query, tt.filename, tt.data, tt.mapping); w.Code != tt.want {
+				t.Fatalf("status = %d, esperado %d (corpo: %s)", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/imports/nao-existe", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("GET de importação inexistente: status %d", w.Code)
+	}
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"api/internal/core/item"
+
+	middleware "api/cmd/rest/middlewares"
+)
+
+/*
+batchResponse é o corpo das respostas de POST, PATCH e DELETE /items/batch.
+
+Exemplo (best_effort, com uma linha rejeitada):
+
+	{
+	  "mode": "best_effort",
+	  "applied": 1,
+	  "failed": 1,
+	  "results": [
+	    {"index": 0, "status": 201, "id": 7, "item": {"id": 7, "code": "ITEM007", "...": "..."}},
+	    {"index": 1, "status": 409, "error": {"code": "already_exists", "message": "...", "request_id": "5f0c..."}}
+	  ]
+	}
+*/
+type batchResponse struct {
+	Mode    string        `json:"mode"`    // atomic ou best_effort
+	Applied int           `json:"applied"` // Linhas gravadas
+	Failed  int           `json:"failed"`  // Linhas rejeitadas (em atomic, todas se alguma falhar)
+	Results []batchResult `json:"results"` // Um resultado por linha, na ordem recebida
+}
+
+// batchResult é o resultado de uma linha do lote.
+type batchResult struct {
+	Index  int                       `json:"index"`           // Posição da linha no corpo da requisição
+	Status int                       `json:"status"`          // Status HTTP que a linha teria sozinha
+	ID     int                       `json:"id,omitempty"`    // ID do item (ausente se a linha falhou)
+	Item   *item.Item                `json:"item,omitempty"`  // Item gravado (POST e PATCH)
+	Error  *middleware.ErrorResponse `json:"error,omitempty"` // Erro da linha, no formato das respostas de erro
+}
+
+/*
+SaveItems lida com POST /items/batch, que cria vários itens de uma vez.
+
+O corpo é uma lista de itens no formato de POST /items, e o parâmetro de query
+`mode` escolhe entre `atomic` (padrão: tudo ou nada, em uma transação) e `best_effort`
+(grava as linhas válidas e reporta as demais).
+
+Retorna 201 se todas as linhas foram gravadas e 207 (Multi-Status) caso contrário,
+sempre com o resultado de cada linha (ver batchResponse). Problemas com o lote como
+um todo (corpo inválido, lote vazio ou com mais de item.MaxBatchSize linhas) seguem
+o formato de erro comum.
+*/
+func (h *handler) SaveItems(c *gin.Context) {
+	atomic, ok := batchMode(c)
+	if !ok {
+		return
+	}
+
+	var its []item.Item
+	if err := c.ShouldBindJSON(&its); err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	results, err := h.core.SaveItems(c.Request.Context(), its, atomic)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondBatch(c, atomic, http.StatusCreated, results, true)
+}
+
+/*
+PatchItems lida com PATCH /items/batch, que altera vários itens de uma vez.
+
+O corpo é uma lista de JSON Merge Patches (Content-Type application/merge-patch+json
+ou application/json), cada um com o `id` do item e, opcionalmente, a `version` esperada:
+
+	[{"id": 1, "price": 9.9}, {"id": 2, "version": 4, "stock": 0}]
+
+O modo e as respostas seguem SaveItems, com 200 quando todas as linhas foram aplicadas.
+*/
+func (h *handler) PatchItems(c *gin.Context) {
+	atomic, ok := batchMode(c)
+	if !ok {
+		return
+	}
+
+	if ct := c.ContentType(); ct != item.PatchMerge && ct != "application/json" {
+		c.Header("Accept-Patch", item.PatchMerge)
+		c.Error(middleware.UnsupportedMediaType(fmt.Errorf("Content-Type %q não suportado em PATCH /items/batch", ct)))
+		return
+	}
+
+	var docs []json.RawMessage
+	if err := c.ShouldBindJSON(&docs); err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	patches := make([]item.BatchPatch, len(docs))
+	for i, doc := range docs {
+		var ref struct {
+			ID int `json:"id"`
+		}
+		_ = json.Unmarshal(doc, &ref) // Sem ID válido, o caso de uso rejeita a linha
+		patches[i] = item.BatchPatch{ID: ref.ID, Patch: item.Patch{Type: item.PatchMerge, Body: doc}}
+	}
+
+	results, err := h.core.PatchItems(c.Request.Context(), patches, atomic)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondBatch(c, atomic, http.StatusOK, results, true)
+}
+
+/*
+DeleteItems lida com DELETE /items/batch, que move vários itens para a lixeira de uma vez.
+
+O corpo é uma lista de `{"id": 1, "version": 3}` (version é opcional, como o If-Match de DELETE /items/:id).
+O modo e as respostas seguem SaveItems, com 200 quando todas as linhas foram aplicadas.
+*/
+func (h *handler) DeleteItems(c *gin.Context) {
+	atomic, ok := batchMode(c)
+	if !ok {
+		return
+	}
+
+	var refs []item.ItemRef
+	if err := c.ShouldBindJSON(&refs); err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	results, err := h.core.DeleteItems(c.Request.Context(), refs, atomic)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondBatch(c, atomic, http.StatusOK, results, false)
+}
+
+/*
+batchMode lê o parâmetro `mode`, indicando se o lote é atômico.
+
+Retorna ok=false (com o erro já registrado) se o modo for inválido.
+*/
+func batchMode(c *gin.Context) (atomic, ok bool) {
+	switch mode := c.DefaultQuery("mode", item.BatchAtomic); mode {
+	case item.BatchAtomic:
+		return true, true
+	case item.BatchBestEffort:
+		return false, true
+	default:
+		c.Error(middleware.BadRequest(fmt.Errorf("mode inválido %q (use %s)", mode, strings.Join(item.BatchModes, " ou "))))
+		return false, false
+	}
+}
+
+/*
+respondBatch monta a batchResponse a partir dos resultados do caso de uso.
+
+O status da resposta é okStatus se todas as linhas foram aplicadas e 207 caso contrário;
+withItem indica se o item gravado vai no resultado de cada linha (POST e PATCH).
+Erros internos de uma linha são registrados no log, como no ErrorHandler.
+*/
+func respondBatch(c *gin.Context, atomic bool, okStatus int, results []item.BatchResult, withItem bool) {
+	resp := batchResponse{Mode: item.BatchBestEffort, Results: make([]batchResult, len(results))}
+	if atomic {
+		resp.Mode = item.BatchAtomic
+	}
+
+	requestID := middleware.GetRequestID(c)
+	for i, r := range results {
+		row := batchResult{Index: r.Index, Status: okStatus}
+		if r.Err != nil {
+			status, errResp := middleware.ToResponse(r.Err)
+			errResp.RequestID = requestID
+			if status == http.StatusInternalServerError {
+				log.Printf("request_id=%s linha %d do lote: erro interno: %v", requestID, r.Index, r.Err)
+			}
+			row.Status, row.Error = status, &errResp
+			resp.Failed++
+		} else {
+			row.ID = r.Item.ID
+			if withItem {
+				row.Item = &results[i].Item
+			}
+			resp.Applied++
+		}
+		resp.Results[i] = row
+	}
+
+	status := okStatus
+	if resp.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, resp)
+}
@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// batchBody é o subconjunto da resposta das rotas em lote usado nos testes.
+type batchBody struct {
+	Mode    string `json:"mode"`
+	Applied int    `json:"applied"`
+	Failed  int    `json:"failed"`
+	Results []struct {
+		Index  int `json:"index"`
+		Status int `json:"status"`
+		ID     int `json:"id"`
+		Error  *struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	} `json:"results"`
+}
+
+// doBatch envia uma requisição em lote e decodifica a resposta.
+func doBatch(t *testing.T, router http.Handler, method, path, body string) (int, batchBody) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp batchBody
+	if w.Code == http.StatusOK || w.Code == http.StatusCreated || w.Code == http.StatusMultiStatus {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: corpo inválido %s", method, path, w.Body)
+		}
+	}
+	return w.Code, resp
+}
+
+// statuses devolve o status de cada linha, na ordem.
+func (b batchBody) statuses() []int {
+	out := make([]int, len(b.Results))
+	for i, r := range b.Results {
+		out[i] = r.Status
+	}
+	return out
+}
+
+func TestBatchItems(t *testing.T) {
+	router := newItemRouter()
+
+	// Atômico: uma linha inválida impede a gravação das outras
+	body := `[{"code": "ITEM001", "title": "Caneta"}, {"code": "x", "title": ""}]`
+	status, resp := doBatch(t, router, http.MethodPost, "/items/batch", body)
+	if status != http.StatusMultiStatus || resp.Mode != "atomic" || resp.Applied != 0 || resp.Failed != 2 {
+		t.Fatalf("POST atômico: status %d, resposta %+v", status, resp)
+	}
+	if got := resp.statuses(); got[0] != http.StatusConflict || got[1] != http.StatusUnprocessableEntity {
+		t.Fatalf("POST atômico: status das linhas %v, esperado [409 422]", got)
+	}
+
+	// Best-effort: as linhas válidas são gravadas
+	body = `[{"code": "ITEM001", "title": "Caneta"}, {"code": "x", "title": ""}, {"code": "ITEM002", "title": "Lápis"}, {"code": "ITEM001", "title": "Repetido"}]`
+	status, resp = doBatch(t, router, http.MethodPost, "/items/batch?mode=best_effort", body)
+	if status != http.StatusMultiStatus || resp.Applied != 2 || resp.Failed != 2 {
+		t.Fatalf("POST best-effort: status %d, resposta %+v", status, resp)
+	}
+	if got := resp.statuses(); got[0] != 201 || got[1] != 422 || got[2] != 201 || got[3] != 409 {
+		t.Fatalf("POST best-effort: status das linhas %v, esperado [201 422 201 409]", got)
+	}
+	if resp.Results[0].ID != 1 || resp.Results[2].ID != 2 || resp.Results[3].Error.Code != "already_exists" {
+		t.Fatalf("POST best-effort: resultados %+v", resp.Results)
+	}
+
+	// Todas as linhas válidas: 200 no PATCH
+	body = `[{"id": 1, "price": 3.5}, {"id": 2, "version": 1, "stock": 7}]`
+	status, resp = doBatch(t, router, http.MethodPatch, "/items/batch", body)
+	if status != http.StatusOK || resp.Applied != 2 {
+		t.Fatalf("PATCH atômico: status %d, resposta %+v", status, resp)
+	}
+
+	body = `[{"id": 1, "stock": 1}, {"id": 2, "version": 1, "stock": 0}, {"id": 99, "stock": 1}, {"stock": 1}]`
+	status, resp = doBatch(t, router, http.MethodPatch, "/items/batch?mode=best_effort", body)
+	if got := resp.statuses(); status != http.StatusMultiStatus || got[0] != 200 || got[1] != 412 || got[2] != 404 || got[3] != 422 {
+		t.Fatalf("PATCH best-effort: status %d, linhas %v; esperado [200 412 404 422]", status, got)
+	}
+
+	// DELETE em lote e conferência das listagens
+	status, resp = doBatch(t, router, http.MethodDelete, "/items/batch", `[{"id": 1}, {"id": 2}]`)
+	if status != http.StatusOK || resp.Applied != 2 {
+		t.Fatalf("DELETE atômico: status %d, resposta %+v", status, resp)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/trash", nil))
+	if !strings.Contains(w.Body.String(), `"total":2`) {
+		t.Fatalf("lixeira depois do DELETE em lote: %s", w.Body)
+	}
+
+	// Erros do lote como um todo
+	tests := []struct {
+		name, method, path, body string
+		want                     int
+	}{
+		{"lote vazio", http.MethodPost, "/items/batch", `[]`, http.StatusUnprocessableEntity},
+		{"modo inválido", http.MethodPost, "/items/batch?mode=parcial", `[{"code": "ITEM003", "title": "x"}]`, http.StatusBadRequest},
+		{"corpo que não é lista", http.MethodDelete, "/items/batch", `{"id": 1}`, http.StatusBadRequest},
+		{"rota parecida", http.MethodPost, "/itemsbatch", `[]`, http.StatusNotFound},
+		{"sufixo colado em /items", http.MethodDelete, "/items:xyz", `[]`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := doBatch(t, router, tt.method, tt.path, tt.body); status != tt.want {
+				t.Fatalf("status %d, esperado %d", status, tt.want)
+			}
+		})
+	}
+}
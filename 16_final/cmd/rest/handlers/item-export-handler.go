@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"api/internal/platform/exporter"
+
+	middleware "api/cmd/rest/middlewares"
+)
+
+/*
+ExportItems lida com GET /items/export, que baixa o catálogo inteiro (ou a parte que atende aos filtros).
+
+Parâmetros de query:
+  - format: csv (padrão), ndjson, xlsx ou json (ver exporter.Formats);
+  - os filtros e a ordenação de GET /items (status, q, min_price, ..., sort, order);
+    a paginação (limit, offset, cursor) é ignorada: a exportação traz todos os itens.
+
+Os itens são lidos do cursor do banco e escritos na resposta um a um, com memória
+constante (o XLSX é montado em um arquivo temporário e enviado no fim). Se o cliente
+aceitar gzip (Accept-Encoding), a resposta é comprimida, exceto o XLSX, que já é um zip.
+
+O prazo da rota vem de `http.export_timeout` e também vale para a escrita da resposta,
+no lugar de `http.write_timeout`. Filtros ou formato inválidos respondem com o formato de
+erro comum; uma falha depois que o download começou só pode ser registrada no log, e o
+arquivo chega incompleto.
+*/
+func (h *handler) ExportItems(c *gin.Context) {
+	format := c.DefaultQuery("format", exporter.FormatCSV)
+	if !slices.Contains(exporter.Formats, format) {
+		c.Error(middleware.BadRequest(fmt.Errorf("format inválido %q (use %s)", format, strings.Join(exporter.Formats, ", "))))
+		return
+	}
+	f, err := parseListFilter(c)
+	if err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+	f.Limit, f.Offset = 0, 0
+
+	// Os headers só vão ao cliente no primeiro byte escrito; até lá, um erro ainda vira resposta de erro
+	var (
+		w  io.Writer = c.Writer
+		gz *gzip.Writer
+	)
+	if format != exporter.FormatXLSX && acceptsGzip(c) {
+		gz = gzip.NewWriter(c.Writer)
+		w = gz
+		c.Header("Content-Encoding", "gzip")
+		c.Header("Vary", "Accept-Encoding")
+	}
+	c.Header("Content-Type", exporter.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="items.%s"`, format))
+
+	// O download pode levar mais que o write_timeout do servidor: a escrita segue o prazo da rota
+	deadline, _ := c.Request.Context().Deadline()
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(deadline)
+
+	out, err := exporter.New(w, format)
+	if err == nil {
+		defer out.Abort() // Libera o XLSX se a exportação falhar antes do Close
+		err = h.core.ExportItems(c.Request.Context(), f, out.Write)
+	}
+	if err == nil {
+		err = out.Close()
+	}
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if !c.Writer.Written() {
+		for _, name := range []string{"Content-Encoding", "Vary", "Content-Type", "Content-Disposition"} {
+			c.Writer.Header().Del(name)
+		}
+		c.Error(err)
+		return
+	}
+	log.Printf("request_id=%s exportação interrompida: %v", middleware.GetRequestID(c), err)
+}
+
+// acceptsGzip indica se o header Accept-Encoding da requisição aceita gzip.
+func acceptsGzip(c *gin.Context) bool {
+	for _, enc := range strings.Split(c.GetHeader("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(enc), ";")
+		if strings.EqualFold(strings.TrimSpace(name), "gzip") && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package handler_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// export faz GET /items/export com a query e os headers informados.
+func export(router http.Handler, query string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/items/export"+query, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestExportItems(t *testing.T) {
+	router := newItemRouter()
+	for _, body := range []string{
+		`{"code": "ITEM001", "title": "Caneta", "price": 2.5, "stock": 10}`,
+		`{"code": "ITEM002", "title": "Lápis, preto", "price": 1, "stock": 0, "status": "out_of_stock"}`,
+		`{"code": "ITEM003", "title": "Borracha", "price": 0.75, "stock": 4}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body)))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("POST /items: status %d", w.Code)
+		}
+	}
+
+	// CSV (padrão), com os filtros e a ordenação de GET /items e sem paginação
+	w := export(router, "?status=active&sort=price&order=desc&limit=1", nil)
+	want := "id,code,title,description,price,stock,status\n1,ITEM001,Caneta,,2.50,10,active\n3,ITEM003,Borracha,,0.75,4,active\n"
+	if w.Code != http.StatusOK || w.Body.String() != want {
+		t.Fatalf("CSV: status %d, corpo %q", w.Code, w.Body)
+	}
+	if ct, cd := w.Header().Get("Content-Type"), w.Header().Get("Content-Disposition"); !strings.HasPrefix(ct, "text/csv") || cd != `attachment; filename="items.csv"` {
+		t.Fatalf("CSV: headers Content-Type %q, Content-Disposition %q", ct, cd)
+	}
+
+	// NDJSON comprimido quando o cliente aceita gzip
+	w = export(router, "?format=ndjson", map[string]string{"Accept-Encoding": "br, gzip"})
+	if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("NDJSON: status %d, headers %v", w.Code, w.Header())
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	data, _ := io.ReadAll(zr)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var second struct {
+		Code  string `json:"code"`
+		Title string `json:"title"`
+	}
+	if len(lines) != 3 || json.Unmarshal([]byte(lines[1]), &second) != nil || second.Title != "Lápis, preto" {
+		t.Fatalf("NDJSON: linhas %q", lines)
+	}
+
+	// XLSX, que não é comprimido de novo
+	w = export(router, "?format=xlsx&q=caneta", map[string]string{"Accept-Encoding": "gzip"})
+	if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("XLSX: status %d, headers %v", w.Code, w.Header())
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("XLSX inválido: %v", err)
+	}
+	rows, _ := f.GetRows(f.GetSheetList()[0])
+	if len(rows) != 2 || rows[1][1] != "ITEM001" || rows[1][4] != "2.5" {
+		t.Fatalf("XLSX: linhas %v", rows)
+	}
+
+	// JSON sem itens ainda é uma lista válida
+	w = export(router, "?format=json&status=discontinued", nil)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Fatalf("JSON vazio: status %d, corpo %q", w.Code, w.Body)
+	}
+}
+
+func TestExportItemsRejectsInvalidParams(t *testing.T) {
+	router := newItemRouter()
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"formato desconhecido", "?format=pdf", http.StatusBadRequest},
+		{"parâmetro que não é número", "?min_price=abc", http.StatusBadRequest},
+		{"campo de ordenação inválido", "?sort=senha", http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := export(router, tt.query, map[string]string{"Accept-Encoding": "gzip"})
+			if w.Code != tt.want || w.Header().Get("Content-Encoding") != "" || w.Header().Get("Content-Disposition") != "" {
+				t.Fatalf("status %d, headers %v; esperado %d com a resposta de erro comum", w.Code, w.Header(), tt.want)
+			}
+			var resp struct {
+				Code string `json:"code"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Code == "" {
+				t.Fatalf("corpo de erro inválido: %s", w.Body)
+			}
+		})
+	}
+}
@@ -1,13 +1,21 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"api/internal/core"
+	"api/internal/core/domainerr"
 	"api/internal/core/item"
+
+	middleware "api/cmd/rest/middlewares"
 )
 
 /*
@@ -34,97 +42,511 @@ SaveItem lida com a requisição HTTP para salvar um novo item.
 
 Passos:
  1. Tenta fazer o bind do JSON recebido no corpo da requisição para a struct `item.Item`.
-    Se falhar (ex: JSON inválido), retorna erro 400 (Bad Request).
+    Se falhar (ex: JSON inválido), registra erro 400 (Bad Request).
  2. Chama o método da camada de caso de uso `SaveItem` passando o item.
-    Se ocorrer erro ao salvar (ex: problema no banco), retorna erro 500 (Internal Server Error).
- 3. Se tudo correr bem, retorna status 200 com mensagem de sucesso.
+    Se ocorrer erro, ele é registrado com `c.Error` e o middleware de erros escolhe
+    o status (ex: 409 para item duplicado, 500 para problema no banco).
+ 3. Se tudo correr bem, retorna status 201 com o item criado no corpo, a URL dele
+    no header `Location` e a versão no header `ETag`.
 */
 func (h *handler) SaveItem(c *gin.Context) {
 	var it item.Item
 
 	// Tenta converter o JSON recebido para a struct `Item`
-	err := c.BindJSON(&it)
+	err := c.ShouldBindJSON(&it)
 	if err != nil {
-		// Se falhar, retorna erro de requisição inválida
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		// Se falhar, registra erro de requisição inválida (400)
+		c.Error(middleware.BadRequest(err))
 		return
 	}
 
 	// Chama o caso de uso para salvar o item
-	if err := h.core.SaveItem(it); err != nil {
-		// Se falhar, retorna erro interno do servidor
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	saved, err := h.core.SaveItem(c.Request.Context(), it)
+	if err != nil {
+		// O middleware de erros traduz o erro de domínio para o status HTTP
+		c.Error(err)
 		return
 	}
 
-	// Se sucesso, retorna status 200
-	c.JSON(http.StatusOK, "item salvo com sucesso")
+	// Se sucesso, retorna status 201 com o item como foi persistido
+	c.Header("Location", fmt.Sprintf("/items/%d", saved.ID))
+	c.Header("ETag", etag(saved.Version))
+	c.JSON(http.StatusCreated, saved)
 }
 
 /*
-ListItems lida com a requisição HTTP para listar todos os itens cadastrados.
+ListItems lida com a requisição HTTP para listar os itens cadastrados.
+
+Parâmetros de query aceitos (todos opcionais):
+  - status: status exato do item
+  - min_price / max_price: faixa de preço
+  - min_stock / max_stock: faixa de estoque
+  - q: texto livre buscado em title e description
+  - sort: campo de ordenação (id, code, title, price, stock, created_at, updated_at, deleted_at)
+  - order: asc (padrão) ou desc
+  - limit: itens por página (padrão 50, máximo 500)
+  - offset ou cursor: posição da página (o cursor vem de `next_cursor`)
 
 Passos:
-1. Chama o método da camada de caso de uso `ListItems`.
-2. Se ocorrer erro, retorna 500 (Internal Server Error).
-3. Se sucesso, retorna status 200 com a lista de itens (vazia ou não) no corpo da resposta.
+1. Converte os parâmetros de query em um item.ListFilter (400 se algum for inválido).
+2. Chama o método da camada de caso de uso `ListItems`.
+3. Se sucesso, retorna status 200 com o envelope `{items, next_cursor, total}`.
 */
 func (h *handler) ListItems(c *gin.Context) {
-	// Busca todos os itens
-	its, err := h.core.ListItems()
+	f, err := parseListFilter(c)
+	if err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	page, err := h.core.ListItems(c.Request.Context(), f)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Retorna a página com status 200 (mesmo se vazia)
+	c.JSON(http.StatusOK, page)
+}
+
+/*
+GetItem lida com a requisição HTTP para buscar um único item pelo ID.
+
+Passos:
+1. Extrai o parâmetro `id` da URL e converte para inteiro (400 se inválido).
+2. Chama o caso de uso `GetItem`.
+3. Se o item não existir (domainerr.ErrNotFound), o middleware de erros responde 404.
+4. Se sucesso, retorna 200 com o item no corpo e a versão no header `ETag`.
+*/
+func (h *handler) GetItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(middleware.BadRequest(errors.New("ID do item inválido")))
+		return
+	}
+
+	it, err := h.core.GetItem(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(it.Version))
+	c.JSON(http.StatusOK, it)
+}
+
+/*
+GetItemByCode lida com a requisição HTTP para buscar um único item pelo código (SKU).
+
+Retorna 404 se nenhum item tiver o código informado.
+*/
+func (h *handler) GetItemByCode(c *gin.Context) {
+	it, err := h.core.GetItemByCode(c.Request.Context(), c.Param("code"))
 	if err != nil {
-		// Se houver erro na operação, retorna 500
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	// Retorna a lista de itens com status 200 (mesmo se vazia)
-	c.JSON(http.StatusOK, its)
+	c.Header("ETag", etag(it.Version))
+	c.JSON(http.StatusOK, it)
 }
 
 /*
 UpdateItem lida com a requisição HTTP para atualizar um item existente.
 
+Todos os campos editáveis são substituídos: um campo omitido no corpo fica com o valor zero.
+Para alterar só alguns campos, use PATCH (ver PatchItem).
+
 Passos:
-1. Faz o bind do JSON recebido para um `item.Item`.
-2. Chama o caso de uso `UpdateItem` com os novos dados.
-3. Se ocorrer erro, retorna 500; caso contrário, 200 com mensagem de sucesso.
+ 1. Extrai o ID da URL e faz o bind do JSON recebido para um `item.Item`.
+    O ID vem sempre da URL; um `id` diferente no corpo é rejeitado com 400.
+ 2. Se o header `If-Match` for enviado, a versão dele substitui a do corpo.
+ 3. Chama o caso de uso `UpdateItem` com os novos dados.
+ 4. Retorna 412 se a versão estiver desatualizada, 404 se o item não existir,
+    500 para outros erros e 200 com o item atualizado (e a nova versão no `ETag`)
+    caso contrário.
 */
 func (h *handler) UpdateItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(middleware.BadRequest(errors.New("ID do item inválido")))
+		return
+	}
+
 	var it item.Item
-	err := c.BindJSON(&it)
+	err = c.ShouldBindJSON(&it)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+	if it.ID != 0 && it.ID != id {
+		c.Error(middleware.BadRequest(fmt.Errorf("o ID do corpo (%d) difere do ID da URL (%d)", it.ID, id)))
+		return
+	}
+	it.ID = id
+
+	if version, ok, err := ifMatch(c); err != nil {
+		c.Error(err)
 		return
+	} else if ok {
+		it.Version = version
 	}
 
-	if err := h.core.UpdateItem(it); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	updated, err := h.core.UpdateItem(c.Request.Context(), it)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, "item atualizado com sucesso")
+	c.Header("ETag", etag(updated.Version))
+	c.JSON(http.StatusOK, updated)
+}
+
+/*
+PatchItem lida com a requisição HTTP para alterar apenas alguns campos de um item.
+
+O formato do corpo é escolhido pelo header `Content-Type`:
+  - application/merge-patch+json (ou application/json): JSON Merge Patch (RFC 7396),
+    ex: `{"price": 9.9}` altera só o preço e `{"description": null}` limpa a descrição;
+  - application/json-patch+json: JSON Patch (RFC 6902),
+    ex: `[{"op": "test", "path": "/stock", "value": 5}, {"op": "replace", "path": "/price", "value": 9.9}]`.
+
+Passos:
+ 1. Extrai o ID da URL (400 se inválido) e lê o corpo (400 se não for JSON válido).
+ 2. Qualquer outro Content-Type é rejeitado com 415, indicando os aceitos em `Accept-Patch`.
+ 3. Lê a versão esperada do header `If-Match` (opcional).
+ 4. Chama o caso de uso `PatchItem`, que aplica o patch ao item atual, valida e grava
+    apenas os campos alterados.
+ 5. Retorna 200 com o item atualizado e a nova versão no `ETag`; os erros seguem
+    o UpdateItem (404, 409, 412, 422).
+*/
+func (h *handler) PatchItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(middleware.BadRequest(errors.New("ID do item inválido")))
+		return
+	}
+
+	var patchType string
+	switch ct := c.ContentType(); ct {
+	case item.PatchMerge, "application/json":
+		patchType = item.PatchMerge
+	case item.PatchJSON:
+		patchType = item.PatchJSON
+	default:
+		c.Header("Accept-Patch", item.PatchMerge+", "+item.PatchJSON)
+		c.Error(middleware.UnsupportedMediaType(fmt.Errorf("Content-Type %q não suportado em PATCH", ct)))
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+	if !json.Valid(body) {
+		c.Error(middleware.BadRequest(errors.New("o corpo do PATCH não é um JSON válido")))
+		return
+	}
+
+	version, _, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	patched, err := h.core.PatchItem(c.Request.Context(), id, version, item.Patch{Type: patchType, Body: body})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(patched.Version))
+	c.JSON(http.StatusOK, patched)
 }
 
 /*
 DeleteItem lida com a requisição HTTP para deletar um item pelo ID.
 
+A exclusão é lógica: o item vai para a lixeira (GET /items/trash) e pode ser
+restaurado com POST /items/:id/restore até ser expurgado pela CLI (items purge).
+
 Passos:
-1. Extrai o parâmetro `id` da URL e converte para inteiro.
-2. Chama o caso de uso `DeleteItem` passando o ID.
-3. Se ocorrer erro, retorna 500; caso contrário, 200 com mensagem de sucesso.
+ 1. Extrai o parâmetro `id` da URL e converte para inteiro.
+ 2. Lê a versão esperada do header `If-Match` (opcional).
+ 3. Chama o caso de uso `DeleteItem` passando o ID e a versão.
+ 4. Retorna 412 se a versão estiver desatualizada, 404 se o item não existir,
+    500 para outros erros e 200 com mensagem de sucesso caso contrário.
 */
 func (h *handler) DeleteItem(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do item inválido"})
+		c.Error(middleware.BadRequest(errors.New("ID do item inválido")))
+		return
+	}
+
+	version, _, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.core.DeleteItem(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.core.DeleteItem(c.Request.Context(), id, version); err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, "item deletado com sucesso")
 }
+
+/*
+ListTrash lida com a requisição HTTP para listar os itens da lixeira.
+
+Aceita os mesmos parâmetros de query de ListItems; `sort=deleted_at` ordena pela data de exclusão.
+Retorna 200 com o envelope `{items, next_cursor, total}`, com `deleted_at` preenchido em cada item.
+*/
+func (h *handler) ListTrash(c *gin.Context) {
+	f, err := parseListFilter(c)
+	if err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	page, err := h.core.ListTrash(c.Request.Context(), f)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+/*
+RestoreItem lida com a requisição HTTP para tirar um item da lixeira.
+
+Passos:
+ 1. Extrai o parâmetro `id` da URL e converte para inteiro (400 se inválido).
+ 2. Lê a versão esperada do header `If-Match` (opcional).
+ 3. Chama o caso de uso `RestoreItem`.
+ 4. Retorna 404 se o item não estiver na lixeira, 409 se o código já estiver em uso
+    e 200 com o item restaurado e a nova versão no header `ETag` caso contrário.
+*/
+func (h *handler) RestoreItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(middleware.BadRequest(errors.New("ID do item inválido")))
+		return
+	}
+
+	version, _, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	it, err := h.core.RestoreItem(c.Request.Context(), id, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", etag(it.Version))
+	c.JSON(http.StatusOK, it)
+}
+
+/*
+adjustStockRequest é o corpo esperado em POST /items/:id/stock/adjust.
+*/
+type adjustStockRequest struct {
+	Type          item.MovementType `json:"type"`           // receipt, sale, adjustment, return ou transfer
+	Delta         int               `json:"delta"`          // Variação do estoque (ex: 10, -3)
+	Reason        string            `json:"reason"`         // Motivo da movimentação
+	Actor         string            `json:"actor"`          // Quem realizou a movimentação
+	Reference     string            `json:"reference"`      // Documento de origem (pedido, nota fiscal, ...)
+	AllowNegative bool              `json:"allow_negative"` // Permite que o saldo fique negativo
+}
+
+/*
+AdjustStock lida com a requisição HTTP para movimentar o estoque de um item.
+
+Passos:
+ 1. Extrai o `id` da URL e faz o bind do JSON para adjustStockRequest (400 se inválido).
+ 2. Chama o caso de uso `AdjustStock`, que aplica o delta de forma atômica.
+ 3. Retorna 404 se o item não existir, 422 se a movimentação for inválida,
+    409 se deixar o estoque negativo e 201 com a movimentação registrada em caso de sucesso.
+*/
+func (h *handler) AdjustStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(middleware.BadRequest(errors.New("ID do item inválido")))
+		return
+	}
+
+	var req adjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	m, err := h.core.AdjustStock(c.Request.Context(), id, item.StockMovement{
+		Type:      req.Type,
+		Delta:     req.Delta,
+		Reason:    req.Reason,
+		Actor:     req.Actor,
+		Reference: req.Reference,
+	}, req.AllowNegative)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, m)
+}
+
+/*
+ListMovements lida com a requisição HTTP para listar o histórico de estoque de um item.
+
+Aceita os mesmos parâmetros de paginação de GET /items (limit, offset, cursor)
+e retorna o envelope `{items, next_cursor, total}`, do mais recente para o mais antigo.
+*/
+func (h *handler) ListMovements(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(middleware.BadRequest(errors.New("ID do item inválido")))
+		return
+	}
+
+	f, err := parseListFilter(c)
+	if err != nil {
+		c.Error(middleware.BadRequest(err))
+		return
+	}
+
+	page, err := h.core.ListMovements(c.Request.Context(), id, item.ListFilter{Limit: f.Limit, Offset: f.Offset})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// etag formata a versão do item como ETag forte (ex: "3").
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+/*
+ifMatch lê a versão esperada do header `If-Match`.
+
+Aceita uma única ETag forte, como a devolvida no ETag (`"3"`, com ou sem aspas).
+`*` (ou header ausente) significa "qualquer versão" e retorna 0.
+Como o If-Match exige comparação forte (RFC 9110), uma ETag fraca (`W/"3"`) nunca
+corresponde e resulta em 412; uma lista de ETags (`"3", "4"`) não é aceita e resulta em 400.
+
+Retorna a versão, se o header foi enviado e um erro já classificado para o middleware de erros.
+*/
+func ifMatch(c *gin.Context) (int, bool, error) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if v == "" || v == "*" {
+		return 0, false, nil
+	}
+	if strings.Contains(v, ",") {
+		return 0, false, middleware.BadRequest(fmt.Errorf("header If-Match aceita uma única ETag: %q", v))
+	}
+	if strings.HasPrefix(v, "W/") {
+		return 0, false, domainerr.PreconditionFailedf("ETag fraca não é aceita no If-Match: %q", v)
+	}
+
+	version, err := strconv.Atoi(strings.Trim(v, `"`))
+	if err != nil || version <= 0 {
+		return 0, false, middleware.BadRequest(fmt.Errorf("header If-Match inválido: %q", v))
+	}
+	return version, true, nil
+}
+
+/*
+parseListFilter converte os parâmetros de query de GET /items em um item.ListFilter.
+
+Retorna erro (com o nome do parâmetro) quando algum valor não puder ser convertido,
+quando `limit` não for positivo, `offset` for negativo ou o `cursor` não tiver vindo de
+`next_cursor`; o handler responde 400. Um `limit` acima de item.MaxLimit é reduzido a item.MaxLimit.
+*/
+func parseListFilter(c *gin.Context) (item.ListFilter, error) {
+	f := item.ListFilter{
+		Status:   c.Query("status"),
+		Search:   c.Query("q"),
+		SortBy:   c.Query("sort"),
+		SortDesc: c.Query("order") == "desc",
+		Limit:    item.DefaultLimit,
+	}
+
+	if order := c.Query("order"); order != "" && order != "asc" && order != "desc" {
+		return f, fmt.Errorf("parâmetro order inválido: %q (use asc ou desc)", order)
+	}
+
+	var err error
+	if f.MinPrice, err = queryFloat(c, "min_price"); err != nil {
+		return f, err
+	}
+	if f.MaxPrice, err = queryFloat(c, "max_price"); err != nil {
+		return f, err
+	}
+	if f.MinStock, err = queryInt(c, "min_stock"); err != nil {
+		return f, err
+	}
+	if f.MaxStock, err = queryInt(c, "max_stock"); err != nil {
+		return f, err
+	}
+
+	if limit, err := queryInt(c, "limit"); err != nil {
+		return f, err
+	} else if limit != nil {
+		if *limit <= 0 {
+			return f, fmt.Errorf("parâmetro limit inválido: %d (use de 1 a %d)", *limit, item.MaxLimit)
+		}
+		f.Limit = min(*limit, item.MaxLimit)
+	}
+	if offset, err := queryInt(c, "offset"); err != nil {
+		return f, err
+	} else if offset != nil {
+		if *offset < 0 {
+			return f, fmt.Errorf("parâmetro offset inválido: %d (não pode ser negativo)", *offset)
+		}
+		f.Offset = *offset
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		// O erro de DecodeCursor é de validação (422); aqui é um parâmetro malformado (400)
+		if f.Offset, err = item.DecodeCursor(cursor); err != nil {
+			return f, fmt.Errorf("parâmetro cursor inválido: %q", cursor)
+		}
+	}
+
+	return f, nil
+}
+
+// queryInt lê um parâmetro de query inteiro opcional (nil se ausente).
+func queryInt(c *gin.Context, name string) (*int, error) {
+	v, ok := c.GetQuery(name)
+	if !ok || v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro %s inválido: %q", name, v)
+	}
+	return &n, nil
+}
+
+// queryFloat lê um parâmetro de query decimal opcional (nil se ausente).
+func queryFloat(c *gin.Context, name string) (*float64, error) {
+	v, ok := c.GetQuery(name)
+	if !ok || v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro %s inválido: %q", name, v)
+	}
+	return &n, nil
+}
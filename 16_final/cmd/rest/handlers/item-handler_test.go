@@ -0,0 +1,305 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"api/internal/core"
+	"api/internal/core/item"
+
+	handler "api/cmd/rest/handlers"
+	middleware "api/cmd/rest/middlewares"
+)
+
+// newItemRouter monta as rotas de item sobre um repositório em memória vazio.
+func newItemRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := handler.NewHandler(core.NewItemUsecase(item.NewMapRepository()))
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.POST("/items", h.SaveItem)
+	router.GET("/items/:id", h.GetItem)
+	router.PUT("/items/:id", h.UpdateItem)
+	router.PATCH("/items/:id", h.PatchItem)
+	router.DELETE("/items/:id", h.DeleteItem)
+	router.GET("/items", h.ListItems)
+	router.GET("/items/trash", h.ListTrash)
+	router.GET("/items/export", h.ExportItems)
+	router.POST("/items/:id/restore", h.RestoreItem)
+	router.POST("/items/batch", h.SaveItems)
+	router.PATCH("/items/batch", h.PatchItems)
+	router.DELETE("/items/batch", h.DeleteItems)
+	return router
+}
+
+func TestSaveItemReturnsCreatedItem(t *testing.T) {
+	router := newItemRouter()
+
+	// O ID enviado pelo cliente é ignorado
+	body := `{"id": 99, "code": "ITEM001", "title": "Caneta", "price": 2.5, "stock": 10}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body)))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, esperado 201 (corpo: %s)", w.Code, w.Body)
+	}
+	var created item.Item
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("corpo inválido: %v", err)
+	}
+	if created.ID != 1 || created.Code != "ITEM001" || created.Version != 1 || created.Status != item.StatusActive {
+		t.Fatalf("item criado = %+v", created)
+	}
+	if loc := w.Header().Get("Location"); loc != "/items/1" {
+		t.Fatalf("Location = %q, esperado /items/1", loc)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Fatalf("ETag = %q, esperado \"1\"", etag)
+	}
+
+	// A URL do Location devolve o mesmo item
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/items/%d", created.ID), nil))
+	var fetched item.Item
+	if err := json.Unmarshal(w.Body.Bytes(), &fetched); err != nil || fetched != created {
+		t.Fatalf("GET = %+v, %v; esperado %+v", fetched, err, created)
+	}
+
+	// PUT devolve o item atualizado com a nova versão
+	body = `{"id": 1, "code": "ITEM001", "title": "Caneta azul", "status": "active", "version": 1}`
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/items/1", strings.NewReader(body)))
+
+	var updated item.Item
+	if err := json.Unmarshal(w.Body.Bytes(), &updated); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("PUT: status %d, corpo %s", w.Code, w.Body)
+	}
+	if updated.Title != "Caneta azul" || updated.Version != 2 || w.Header().Get("ETag") != `"2"` {
+		t.Fatalf("item atualizado = %+v, ETag %q", updated, w.Header().Get("ETag"))
+	}
+}
+
+func TestPatchItem(t *testing.T) {
+	router := newItemRouter()
+	body := `{"code": "ITEM001", "title": "Caneta", "description": "tinta azul", "price": 2.5, "stock": 10}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body)))
+
+	tests := []struct {
+		name        string
+		contentType string
+		ifMatch     string
+		body        string
+		wantStatus  int
+		wantPrice   float64
+		wantStock   int
+	}{
+		{"merge patch altera só o estoque", "application/merge-patch+json", "", `{"stock": 4}`, http.StatusOK, 2.5, 4},
+		{"application/json é tratado como merge patch", "application/json", `"2"`, `{"price": 3}`, http.StatusOK, 3, 4},
+		{"json patch", "application/json-patch+json", "", `[{"op": "replace", "path": "/stock", "value": 7}]`, http.StatusOK, 3, 7},
+		{"If-Match desatualizado", "application/merge-patch+json", `"1"`, `{"stock": 1}`, http.StatusPreconditionFailed, 3, 7},
+		{"If-Match com ETag fraca", "application/merge-patch+json", `W/"4"`, `{"stock": 1}`, http.StatusPreconditionFailed, 3, 7},
+		{"If-Match com lista de ETags", "application/merge-patch+json", `"4", "5"`, `{"stock": 1}`, http.StatusBadRequest, 3, 7},
+		{"resultado inválido", "application/merge-patch+json", "", `{"price": -1}`, http.StatusUnprocessableEntity, 3, 7},
+		{"campo desconhecido", "application/merge-patch+json", "", `{"color": "azul"}`, http.StatusUnprocessableEntity, 3, 7},
+		{"JSON malformado", "application/merge-patch+json", "", `{"stock":`, http.StatusBadRequest, 3, 7},
+		{"Content-Type não suportado", "text/plain", "", `stock=1`, http.StatusUnsupportedMediaType, 3, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/items/1", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, esperado %d (corpo: %s)", w.Code, tt.wantStatus, w.Body)
+			}
+
+			// Os campos não enviados nunca são zerados
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+			var it item.Item
+			if err := json.Unmarshal(w.Body.Bytes(), &it); err != nil {
+				t.Fatalf("GET: %v", err)
+			}
+			if it.Price != tt.wantPrice || it.Stock != tt.wantStock || it.Title != "Caneta" || it.Description != "tinta azul" {
+				t.Fatalf("item depois do PATCH = %+v", it)
+			}
+		})
+	}
+}
+
+func TestUpdateItemTakesIDFromPath(t *testing.T) {
+	router := newItemRouter()
+	for _, code := range []string{"ITEM001", "ITEM002"} {
+		body := fmt.Sprintf(`{"code": %q, "title": "Caneta"}`, code)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body)))
+	}
+
+	// Corpo sem ID: vale o da URL
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/items/2", strings.NewReader(`{"code": "ITEM002", "title": "Lápis", "status": "active"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT sem ID no corpo: status %d (corpo: %s)", w.Code, w.Body)
+	}
+
+	// ID do corpo diferente do da URL: rejeitado, nada é alterado
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/items/2", strings.NewReader(`{"id": 1, "code": "ITEM001", "title": "Borracha", "status": "active"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("PUT com IDs divergentes: status %d, esperado 400", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	var first item.Item
+	if err := json.Unmarshal(w.Body.Bytes(), &first); err != nil || first.Title != "Caneta" {
+		t.Fatalf("item 1 = %+v, %v; esperado inalterado", first, err)
+	}
+}
+
+func TestTrashAndRestore(t *testing.T) {
+	router := newItemRouter()
+	for _, code := range []string{"ITEM001", "ITEM002"} {
+		body := fmt.Sprintf(`{"code": %q, "title": "Caneta"}`, code)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body)))
+	}
+
+	// list devolve os IDs da página de path
+	list := func(path string) []int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var page item.Page
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d, corpo %s", path, w.Code, w.Body)
+		}
+		ids := []int{}
+		for _, it := range page.Items {
+			ids = append(ids, it.ID)
+		}
+		return ids
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/items/1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("DELETE: status %d (corpo: %s)", w.Code, w.Body)
+	}
+
+	if ids := list("/items"); fmt.Sprint(ids) != "[2]" {
+		t.Fatalf("GET /items = %v, esperado só o item 2", ids)
+	}
+	if ids := list("/items/trash"); fmt.Sprint(ids) != "[1]" {
+		t.Fatalf("GET /items/trash = %v, esperado só o item 1", ids)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("GET de item na lixeira: status %d, esperado 404", w.Code)
+	}
+
+	// Restaurar com versão antiga falha; com a atual (2, após a exclusão) funciona
+	req := httptest.NewRequest(http.MethodPost, "/items/1/restore", nil)
+	req.Header.Set("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("restore com If-Match antigo: status %d, esperado 412", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/items/1/restore", nil)
+	req.Header.Set("If-Match", `"2"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var restored item.Item
+	if err := json.Unmarshal(w.Body.Bytes(), &restored); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("restore: status %d, corpo %s", w.Code, w.Body)
+	}
+	if restored.ID != 1 || restored.DeletedAt != nil || w.Header().Get("ETag") != `"3"` {
+		t.Fatalf("item restaurado = %+v, ETag %q", restored, w.Header().Get("ETag"))
+	}
+
+	// Item fora da lixeira não pode ser restaurado
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items/1/restore", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("restore de item ativo: status %d, esperado 404", w.Code)
+	}
+	if ids := list("/items/trash"); len(ids) != 0 {
+		t.Fatalf("GET /items/trash = %v, esperado vazio", ids)
+	}
+}
+
+func TestListItemsRejectsInvalidPagination(t *testing.T) {
+	router := newItemRouter()
+	body := `{"code": "ITEM001", "title": "Caneta"}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body)))
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"?limit=1", http.StatusOK},
+		{"?limit=100000", http.StatusOK}, // Reduzido a item.MaxLimit
+		{"?limit=0", http.StatusBadRequest},
+		{"?limit=-5", http.StatusBadRequest},
+		{"?limit=abc", http.StatusBadRequest},
+		{"?offset=-1", http.StatusBadRequest},
+		{"?cursor=nao-e-um-cursor", http.StatusBadRequest},
+		{"?cursor=" + item.EncodeCursor(1), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			for _, path := range []string{"/items", "/items/trash"} {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+tt.query, nil))
+				if w.Code != tt.want {
+					t.Fatalf("GET %s%s: status %d, esperado %d (corpo: %s)", path, tt.query, w.Code, tt.want, w.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestDuplicateCodeIsConflict(t *testing.T) {
+	router := newItemRouter()
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if method == http.MethodPatch {
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	send(http.MethodPost, "/items", `{"code": "ITEM001", "title": "Caneta"}`)
+	send(http.MethodPost, "/items", `{"code": "ITEM002", "title": "Lápis"}`)
+
+	tests := []struct {
+		name, method, path, body string
+	}{
+		{"criar com código em uso", http.MethodPost, "/items", `{"code": "ITEM001", "title": "Borracha"}`},
+		{"trocar para um código em uso", http.MethodPut, "/items/2", `{"code": "ITEM001", "title": "Lápis", "status": "active"}`},
+		{"patch para um código em uso", http.MethodPatch, "/items/2", `{"code": "ITEM001"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(tt.method, tt.path, tt.body)
+			var resp middleware.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusConflict || resp.Code != "already_exists" {
+				t.Fatalf("status %d, corpo %s; esperado 409 already_exists", w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -1,50 +1,118 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 
-	handler "api/cmd/rest/handlers"          // Pacote responsável por lidar com requisições HTTP
-	core "api/internal/core"                 // Camada de lógica de negócio
-	item "api/internal/core/item"            // Pacote com o modelo e repositórios de Item
-	mysqlsetup "api/internal/platform/mysql" // Configuração do cliente MySQL
-
-	_ "github.com/go-sql-driver/mysql" // Driver MySQL para o pacote database/sql
+	handler "api/cmd/rest/handlers"         // Pacote responsável por lidar com requisições HTTP
+	middleware "api/cmd/rest/middlewares"   // Middlewares HTTP (request ID, tratamento de erros)
+	core "api/internal/core"                // Camada de lógica de negócio
+	"api/internal/core/auth"                // Principal e validação de credenciais
+	backend "api/internal/platform/backend" // Seleção do repositório (mysql, memory ou sqlite)
+	"api/internal/platform/jwt"             // Validação de tokens JWT (HS256/RS256)
+	"api/internal/platform/migrations"      // Migrações versionadas do schema
+	"api/pkg/config"                        // Configuração tipada (arquivo + variáveis de ambiente)
 )
 
 func main() {
 	/*
-		Configura a conexão com o banco de dados MySQL.
-		A função NewMySQLSetup encapsula toda a lógica de conexão.
-		Se der erro, o programa encerra com uma mensagem.
+		Carrega a configuração: valores padrão, depois o arquivo informado em
+		--config (ou CONFIG_FILE) e, por último, as variáveis de ambiente.
 	*/
-	mysqlClient, err := mysqlsetup.NewMySQLSetup()
+	configFlag := flag.String("config", "", "arquivo de configuração .yaml ou .toml (opcional)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFlag)
 	if err != nil {
-		log.Fatalf("Não foi possível configurar o MySQL: %v", err)
+		log.Fatalf("Não foi possível carregar a configuração: %v", err)
 	}
-	// Fecha a conexão com o banco ao encerrar o programa
-	defer mysqlClient.Close()
 
 	/*
-		Inicializa o repositório de itens usando o banco MySQL.
-		Esse repositório implementa a interface necessária para a camada de caso de uso.
+		Inicializa o repositório de itens escolhido em `repository` (mysql, memory ou sqlite).
+		O pacote backend encapsula a conexão com o banco; se der erro, o programa encerra.
 	*/
-	repo := item.NewMySqlRepository(mysqlClient.DB())
+	store, err := backend.New(cfg)
+	if err != nil {
+		log.Fatalf("Não foi possível configurar o repositório %s: %v", cfg.Repository, err)
+	}
+	// Fecha a conexão com o banco ao encerrar o programa
+	defer store.Close()
+	repo := store.Items
 
 	/*
-		Alternativamente, poderíamos usar um repositório em memória (útil para testes locais),
-		sem necessidade de banco de dados.
-
-		Exemplo:
-		repo := item.NewMapRepository()
+		Verificações de prontidão (/readyz): com banco, confere se ele responde
+		e se não há migrações pendentes; o backend em memória está sempre pronto.
 	*/
+	checks := map[string]handler.ReadinessCheck{}
+	var dbStats interface{ DBStats(*gin.Context) }
+	if store.DB != nil {
+		dbStats = handler.NewDBStatsHandler(store.DB.Stats)
+
+		migrator, err := migrations.New(store.DB, store.Name)
+		if err != nil {
+			log.Fatalf("Não foi possível carregar as migrações: %v", err)
+		}
+
+		/*
+			Com `migrate_on_start` (MIGRATE_ON_START=true), aplica as migrações pendentes
+			antes de atender requisições. No MySQL a aplicação acontece sob uma trava
+			consultiva, então várias réplicas podem subir ao mesmo tempo com segurança.
+		*/
+		if cfg.MigrateOnStart {
+			applied, err := migrator.Up(context.Background())
+			if err != nil {
+				log.Fatalf("Não foi possível aplicar as migrações: %v", err)
+			}
+			for _, m := range applied {
+				log.Printf("Migração aplicada: %04d_%s", m.Version, m.Name)
+			}
+		}
+
+		checks["database"] = store.Ping
+		checks["migrations"] = func(ctx context.Context) error {
+			pending, err := migrator.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d migrações pendentes", pending)
+			}
+			return nil
+		}
+	}
+	health := handler.NewHealthHandler(checks)
 
 	/*
 		Cria o caso de uso da aplicação, que contém a lógica de negócio.
 		Recebe o repositório como dependência (injeção de dependência).
 	*/
 	usecase := core.NewItemUsecase(repo)
+	imports := handler.NewImportHandler(core.NewImportUsecase(repo)) // Importação de planilhas (CSV/XLSX)
+
+	/*
+		Autenticação (auth.enabled): chaves de API guardadas no banco (gerenciadas com
+		`cli keys`) e, se houver chaves JWT configuradas em auth.jwt, tokens de portador.
+	*/
+	verifier, err := jwt.New(cfg.Auth.JWT)
+	if err != nil {
+		log.Fatalf("Não foi possível carregar as chaves JWT: %v", err)
+	}
+	var tokens auth.TokenVerifier // Continua nil (JWT desabilitado) se nenhuma chave foi configurada
+	if verifier != nil {
+		tokens = verifier
+	}
+	authn := core.NewAuthUsecase(store.APIKeys, tokens)
+	if cfg.Auth.Enabled && tokens == nil && store.DB == nil {
+		log.Fatalf("auth.enabled exige chaves JWT (auth.jwt) no repositório %s, que não guarda chaves de API entre processos", store.Name)
+	}
 
 	/*
 		Cria o handler responsável por expor os endpoints HTTP,
@@ -56,15 +124,87 @@ func main() {
 		Configura o roteador HTTP usando o framework Gin.
 		Define as rotas disponíveis para o cliente.
 	*/
+	if cfg.LogLevel != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
 	router := gin.Default()
-	router.POST("/items", handler.SaveItem)         // Rota para salvar um item
-	router.GET("/items", handler.ListItems)         // Rota para listar todos os itens
-	router.PUT("/items/:id", handler.UpdateItem)    // Rota para atualizar o item
-	router.DELETE("/items/:id", handler.DeleteItem) // Rota para deletar o item
-
-	// Inicia o servidor web na porta 8080
-	log.Println("Servidor iniciado em http://localhost:8080")
-	if err := router.Run(":8080"); err != nil {
-		log.Fatal(err)
+	router.Use(middleware.RequestID())                  // Gera/propaga o X-Request-ID
+	router.Use(middleware.ErrorHandler())               // Traduz erros de domínio para respostas HTTP padronizadas
+	router.Use(middleware.Timeout(cfg.HTTP.TimeoutFor)) // Prazo por rota (http.request_timeout / http.route_timeouts)
+	router.GET("/healthz", health.Healthz)              // Liveness: o processo está de pé
+	router.GET("/readyz", health.Readyz)                // Readiness: banco e migrações prontos
+
+	// Demais rotas: exigem chave de API ou token JWT, exceto com auth.enabled=false
+	api := router.Group("/")
+	if cfg.Auth.Enabled {
+		api.Use(middleware.Authenticate(authn)) // Valida a credencial e guarda o principal no contexto
+	} else {
+		log.Println("Atenção: autenticação desabilitada (auth.enabled=false); todas as rotas estão abertas")
+	}
+	api.POST("/items", handler.SaveItem)                     // Rota para salvar um item
+	api.GET("/items", handler.ListItems)                     // Rota para listar todos os itens
+	api.GET("/items/trash", handler.ListTrash)               // Rota para listar os itens da lixeira
+	api.GET("/items/export", handler.ExportItems)            // Rota para baixar o catálogo (csv, ndjson, xlsx ou json)
+	api.POST("/items/batch", handler.SaveItems)              // Rota para criar vários itens de uma vez
+	api.PATCH("/items/batch", handler.PatchItems)            // Rota para alterar vários itens de uma vez
+	api.DELETE("/items/batch", handler.DeleteItems)          // Rota para mover vários itens para a lixeira
+	api.GET("/items/:id", handler.GetItem)                   // Rota para buscar um item pelo ID
+	api.GET("/items/code/:code", handler.GetItemByCode)      // Rota para buscar um item pelo código (SKU)
+	api.PUT("/items/:id", handler.UpdateItem)                // Rota para atualizar o item
+	api.PATCH("/items/:id", handler.PatchItem)               // Rota para alterar só alguns campos do item
+	api.DELETE("/items/:id", handler.DeleteItem)             // Rota para mover o item para a lixeira
+	api.POST("/items/:id/restore", handler.RestoreItem)      // Rota para tirar o item da lixeira
+	api.POST("/items/:id/stock/adjust", handler.AdjustStock) // Rota para movimentar o estoque do item
+	api.GET("/items/:id/movements", handler.ListMovements)   // Rota para listar o histórico de estoque
+	api.POST("/imports", imports.StartImport)                // Rota para importar itens de uma planilha
+	api.GET("/imports/:id", imports.GetImport)               // Rota para acompanhar uma importação
+	api.GET("/imports/:id/errors", imports.ImportErrors)     // Rota para baixar o relatório de erros da importação
+	if dbStats != nil {
+		api.GET("/metrics/db", dbStats.DBStats) // Estatísticas do pool de conexões do banco
+	}
+
+	// Servidor web no endereço e com os timeouts configurados
+	server := &http.Server{
+		Addr:         cfg.HTTP.Addr,
+		Handler:      router,
+		ReadTimeout:  cfg.HTTP.ReadTimeout.Duration,
+		WriteTimeout: cfg.HTTP.WriteTimeout.Duration,
+	}
+
+	/*
+		Inicia o servidor em segundo plano e aguarda SIGINT/SIGTERM (Ctrl+C ou `docker stop`).
+		Ao receber o sinal:
+		 1. o /readyz passa a responder 503, tirando a instância do balanceamento;
+		 2. server.Shutdown para de aceitar conexões e espera as requisições em andamento
+		    terminarem, por até `http.drain_timeout`;
+		 3. main retorna normalmente, executando o `defer store.Close()`.
+	*/
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Servidor iniciado em %s (repositório %s)", cfg.HTTP.Addr, cfg.Repository)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Servidor encerrado com erro: %v", err)
+		}
+		return
+	case <-ctx.Done():
+	}
+
+	log.Printf("Sinal recebido, aguardando até %s pelas requisições em andamento", cfg.HTTP.DrainTimeout.Duration)
+	health.SetDraining()
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.DrainTimeout.Duration)
+	defer cancel()
+	if err := server.Shutdown(drainCtx); err != nil {
+		log.Printf("Desligamento forçado: %v", err)
+		return
 	}
+	log.Println("Servidor encerrado")
 }
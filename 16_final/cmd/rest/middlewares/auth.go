@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"api/internal/core/auth"
+	"api/internal/core/domainerr"
+)
+
+/*
+Chaves usadas na autenticação.
+
+- APIKeyHeader: header alternativo ao Authorization para enviar a chave de API;
+- principalKey: chave onde o principal autenticado fica guardado no gin.Context.
+*/
+const (
+	APIKeyHeader = "X-API-Key"
+	principalKey = "principal"
+)
+
+/*
+Authenticator valida uma credencial e devolve o principal que ela identifica.
+É satisfeito por core.AuthUsecasePort.
+*/
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (auth.Principal, error)
+}
+
+/*
+Authenticate exige uma credencial válida antes de chamar o handler.
+
+A credencial é lida de `Authorization: Bearer <chave ou token>` ou de `X-API-Key: <chave>`.
+Sem credencial, ou com uma credencial inválida, a requisição para com 401 unauthorized
+(com o header WWW-Authenticate), no formato de erro comum do ErrorHandler.
+
+O principal fica disponível para:
+  - os handlers, com GetPrincipal;
+  - os casos de uso, em auth.FromContext(ctx) (o contexto da requisição é substituído);
+  - a auditoria: toda requisição que altera dados (métodos diferentes de GET e HEAD)
+    gera uma linha de log com o principal, a rota e o status da resposta.
+*/
+func Authenticate(a Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		credential := credentialFrom(c)
+
+		var (
+			p   auth.Principal
+			err error
+		)
+		if credential == "" {
+			err = domainerr.Unauthenticatedf("informe uma chave de API (X-API-Key) ou um token (Authorization: Bearer)")
+		} else {
+			p, err = a.Authenticate(c.Request.Context(), credential)
+		}
+		if err != nil {
+			if errors.Is(err, domainerr.ErrUnauthenticated) {
+				c.Header("WWW-Authenticate", `Bearer realm="inventory"`)
+			}
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Set(principalKey, p)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		c.Next()
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			// Um erro só vira resposta depois, no ErrorHandler: o status registrado é o que ele vai usar
+			status := c.Writer.Status()
+			if len(c.Errors) > 0 && !c.Writer.Written() {
+				status, _ = ToResponse(c.Errors.Last().Err)
+			}
+			log.Printf("audit request_id=%s principal=%s name=%q %s %s status=%d",
+				GetRequestID(c), p, p.Name, c.Request.Method, c.Request.URL.Path, status)
+		}
+	}
+}
+
+// GetPrincipal retorna o principal autenticado na requisição (false se a rota não exige autenticação).
+func GetPrincipal(c *gin.Context) (auth.Principal, bool) {
+	v, ok := c.Get(principalKey)
+	if !ok {
+		return auth.Principal{}, false
+	}
+	p, ok := v.(auth.Principal)
+	return p, ok
+}
+
+// credentialFrom extrai a credencial do header Authorization (esquema Bearer) ou de X-API-Key.
+func credentialFrom(c *gin.Context) string {
+	if scheme, value, ok := strings.Cut(c.GetHeader("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(c.GetHeader(APIKeyHeader))
+}
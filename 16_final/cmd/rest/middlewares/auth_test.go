@@ -0,0 +1,195 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	handler "api/cmd/rest/handlers"
+	middleware "api/cmd/rest/middlewares"
+	"api/internal/core"
+	"api/internal/core/auth"
+	"api/internal/core/item"
+	"api/internal/platform/jwt"
+	"api/pkg/config"
+)
+
+const jwtSecret = "segredo-de-teste-com-pelo-menos-32-bytes"
+
+// newAuthRouter monta o roteador com autenticação por chave de API e JWT HS256, como em cmd/rest/main.go.
+func newAuthRouter(t *testing.T) (*gin.Engine, core.AuthUsecasePort) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	verifier, err := jwt.New(config.JWTConfig{HS256Secret: jwtSecret})
+	if err != nil {
+		t.Fatalf("jwt.New: %v", err)
+	}
+	authn := core.NewAuthUsecase(auth.NewMapRepository(), verifier)
+	h := handler.NewHandler(core.NewItemUsecase(item.NewMapRepository()))
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.ErrorHandler())
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	api := router.Group("/", middleware.Authenticate(authn))
+	api.POST("/items", h.SaveItem)
+	api.POST("/items/:id/stock/adjust", h.AdjustStock)
+	api.PATCH("/items/:id", h.PatchItem)
+	api.DELETE("/items/:id", h.DeleteItem)
+	api.GET("/whoami", func(c *gin.Context) {
+		p, _ := middleware.GetPrincipal(c)
+		fromCtx, _ := auth.FromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"gin": p.String(), "ctx": fromCtx.String()})
+	})
+	return router, authn
+}
+
+// hs256Token assina um JWT HS256 com o segredo dos testes.
+func hs256Token(claims map[string]any) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	hb, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	cb, _ := json.Marshal(claims)
+	signed := enc(hb) + "." + enc(cb)
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte(signed))
+	return signed + "." + enc(mac.Sum(nil))
+}
+
+func serve(router http.Handler, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthenticate(t *testing.T) {
+	router, authn := newAuthRouter(t)
+	ctx := context.Background()
+	k, key, err := authn.CreateAPIKey(ctx, "integração ERP", []string{auth.RoleAdmin})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	token := hs256Token(map[string]any{"sub": "maria", "roles": []string{"warehouse"}, "exp": time.Now().Add(time.Hour).Unix()})
+
+	// Rotas fora do grupo continuam abertas
+	if rec := serve(router, http.MethodGet, "/healthz", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("GET /healthz = %d", rec.Code)
+	}
+
+	ok := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"chave em X-API-Key", map[string]string{"X-API-Key": key}, "api_key:1"},
+		{"chave como Bearer", map[string]string{"Authorization": "Bearer " + key}, "api_key:1"},
+		{"JWT", map[string]string{"Authorization": "bearer " + token}, "jwt:maria"},
+	}
+	for _, tt := range ok {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodGet, "/whoami", "", tt.headers)
+			var got map[string]string
+			if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &got) != nil || got["gin"] != tt.want || got["ctx"] != tt.want {
+				t.Fatalf("status %d, corpo %s; esperado principal %s", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+
+	// O histórico de estoque registra o principal, não o actor enviado pelo cliente
+	serve(router, http.MethodPost, "/items", `{"code": "ITEM001", "title": "Caneta", "price": 2.5, "stock": 10}`, map[string]string{"X-API-Key": key})
+	rec := serve(router, http.MethodPost, "/items/1/stock/adjust", `{"type": "sale", "delta": -1, "actor": "outra-pessoa"}`, map[string]string{"Authorization": "Bearer " + token})
+	var m item.StockMovement
+	if rec.Code != http.StatusCreated || json.Unmarshal(rec.Body.Bytes(), &m) != nil || m.Actor != "jwt:maria" {
+		t.Fatalf("ajuste de estoque: status %d, corpo %s", rec.Code, rec.Body)
+	}
+
+	if err := authn.RevokeAPIKey(ctx, k.ID); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	denied := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{"sem credencial", nil},
+		{"chave revogada", map[string]string{"X-API-Key": key}},
+		{"chave inexistente", map[string]string{"X-API-Key": "inv_000000000000_" + strings.Repeat("0", 64)}},
+		{"JWT expirado", map[string]string{"Authorization": "Bearer " + hs256Token(map[string]any{"sub": "maria", "exp": time.Now().Add(-time.Hour).Unix()})}},
+		{"esquema Basic", map[string]string{"Authorization": "Basic dXNlcjpzZW5oYQ=="}},
+	}
+	for _, tt := range denied {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodPost, "/items", `{"code": "ITEM002", "title": "Lápis"}`, tt.headers)
+			var resp middleware.ErrorResponse
+			if rec.Code != http.StatusUnauthorized || json.Unmarshal(rec.Body.Bytes(), &resp) != nil || resp.Code != "unauthorized" {
+				t.Fatalf("status %d, corpo %s; esperado 401 unauthorized", rec.Code, rec.Body)
+			}
+			if rec.Header().Get("WWW-Authenticate") == "" {
+				t.Fatal("resposta 401 sem o header WWW-Authenticate")
+			}
+		})
+	}
+}
+
+// TestAuthorize confere, de ponta a ponta, que a falta de uma permissão vira 403 com a permissão no corpo.
+func TestAuthorize(t *testing.T) {
+	router, authn := newAuthRouter(t)
+	keys := map[string]string{}
+	for _, role := range auth.Roles() {
+		_, key, err := authn.CreateAPIKey(context.Background(), role, []string{role})
+		if err != nil {
+			t.Fatalf("CreateAPIKey(%s): %v", role, err)
+		}
+		keys[role] = key
+	}
+	if rec := serve(router, http.MethodPost, "/items", `{"code": "ITEM001", "title": "Caneta", "price": 2.5, "stock": 10}`, map[string]string{"X-API-Key": keys[auth.RoleManager]}); rec.Code != http.StatusCreated {
+		t.Fatalf("manager cria item: status %d, corpo %s", rec.Code, rec.Body)
+	}
+
+	tests := []struct {
+		name, role, method, path, body string
+		headers                        map[string]string
+		wantStatus                     int
+		wantPermission                 string
+	}{
+		{"warehouse não cria item", auth.RoleWarehouse, http.MethodPost, "/items", `{"code": "ITEM002", "title": "Lápis"}`, nil, http.StatusForbidden, "items:write"},
+		{"warehouse movimenta estoque", auth.RoleWarehouse, http.MethodPost, "/items/1/stock/adjust", `{"type": "sale", "delta": -1}`, nil, http.StatusCreated, ""},
+		{"catalog não cria item com preço", auth.RoleCatalog, http.MethodPost, "/items", `{"code": "ITEM002", "title": "Lápis", "price": 1}`, nil, http.StatusForbidden, "items:price"},
+		{"catalog não altera o preço", auth.RoleCatalog, http.MethodPatch, "/items/1", `{"price": 3}`, map[string]string{"Content-Type": item.PatchMerge}, http.StatusForbidden, "items:price"},
+		{"catalog altera o título", auth.RoleCatalog, http.MethodPatch, "/items/1", `{"title": "Caneta azul"}`, map[string]string{"Content-Type": item.PatchMerge}, http.StatusOK, ""},
+		{"viewer não movimenta estoque", auth.RoleViewer, http.MethodPost, "/items/1/stock/adjust", `{"type": "sale", "delta": -1}`, nil, http.StatusForbidden, "stock:adjust"},
+		{"manager não exclui", auth.RoleManager, http.MethodDelete, "/items/1", "", nil, http.StatusForbidden, "items:delete"},
+		{"admin exclui", auth.RoleAdmin, http.MethodDelete, "/items/1", "", nil, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"X-API-Key": keys[tt.role]}
+			for k, v := range tt.headers {
+				headers[k] = v
+			}
+			rec := serve(router, tt.method, tt.path, tt.body, headers)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, corpo %s; esperado %d", rec.Code, rec.Body, tt.wantStatus)
+			}
+			if tt.wantPermission == "" {
+				return
+			}
+			var resp middleware.ErrorResponse
+			if json.Unmarshal(rec.Body.Bytes(), &resp) != nil || resp.Code != "forbidden" || resp.Permission != tt.wantPermission {
+				t.Fatalf("corpo %s; esperado forbidden com a permissão %s", rec.Body, tt.wantPermission)
+			}
+		})
+	}
+}
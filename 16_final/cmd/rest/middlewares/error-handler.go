@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"api/internal/core/domainerr"
+)
+
+/*
+ErrBadRequest representa uma requisição malformada (JSON inválido, parâmetro
+que não pôde ser convertido, etc.), detectada pelo próprio handler antes de
+chegar ao caso de uso.
+*/
+var ErrBadRequest = errors.New("bad request")
+
+// BadRequest encadeia err com ErrBadRequest para que seja respondido com 400.
+func BadRequest(err error) error {
+	return fmt.Errorf("%w: %w", ErrBadRequest, err)
+}
+
+/*
+ErrUnsupportedMediaType representa um corpo em formato (Content-Type) que a rota
+não aceita, como um PATCH que não é merge patch nem JSON Patch.
+*/
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// UnsupportedMediaType encadeia err com ErrUnsupportedMediaType para que seja respondido com 415.
+func UnsupportedMediaType(err error) error {
+	return fmt.Errorf("%w: %w", ErrUnsupportedMediaType, err)
+}
+
+/*
+StatusClientClosedRequest é o status (não padronizado, popularizado pelo nginx) usado
+quando o cliente desconecta antes da resposta. Ele aparece apenas nos logs de acesso,
+já que não há mais ninguém para recebê-lo.
+*/
+const StatusClientClosedRequest = 499
+
+/*
+ErrorResponse é o corpo JSON, estável, de todas as respostas de erro da API.
+
+Exemplo:
+
+	{
+	  "code": "validation_failed",
+	  "message": "validation failed: price: não pode ser negativo",
+	  "fields": [{"field": "price", "message": "não pode ser negativo"}],
+	  "request_id": "5f0c..."
+	}
+*/
+type ErrorResponse struct {
+	Code       string                 `json:"code"`                 // Código estável, pensado para ser tratado por clientes
+	Message    string                 `json:"message"`              // Mensagem legível
+	Fields     []domainerr.FieldError `json:"fields,omitempty"`     // Violações por campo (apenas em validation_failed)
+	Permission string                 `json:"permission,omitempty"` // Permissão que falta (apenas em forbidden)
+	RequestID  string                 `json:"request_id"`           // ID da requisição (header X-Request-ID)
+}
+
+/*
+ErrorHandler é o middleware único que transforma erros em respostas HTTP.
+
+Os handlers apenas registram o erro com `c.Error(err)` e retornam; depois que a
+cadeia termina, este middleware pega o último erro e responde com:
+
+- domainerr.ErrNotFound           → 404 not_found
+- domainerr.ErrAlreadyExists      → 409 already_exists
+- domainerr.ErrConflict           → 409 conflict
+- domainerr.ErrPreconditionFailed → 412 precondition_failed
+- domainerr.ErrValidation         → 422 validation_failed (com `fields`)
+- domainerr.ErrUnauthenticated    → 401 unauthorized (ver Authenticate)
+- domainerr.ErrForbidden          → 403 forbidden (com `permission`)
+- ErrBadRequest                   → 400 bad_request
+- ErrUnsupportedMediaType         → 415 unsupported_media_type
+- context.DeadlineExceeded        → 504 timeout (prazo da rota esgotado, ver Timeout)
+- context.Canceled                → 499 client_closed_request (o cliente desconectou)
+- qualquer outro erro             → 500 internal_error (detalhes só no log)
+*/
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status, resp := ToResponse(err)
+		resp.RequestID = GetRequestID(c)
+
+		if status == http.StatusInternalServerError {
+			log.Printf("request_id=%s erro interno: %v", resp.RequestID, err)
+		}
+		c.JSON(status, resp)
+	}
+}
+
+/*
+ToResponse traduz o erro para o status HTTP e o corpo da resposta, sem o request_id.
+
+Além do ErrorHandler, é usado pelas rotas em lote para descrever o erro de cada linha
+no mesmo formato das respostas de erro.
+*/
+func ToResponse(err error) (int, ErrorResponse) {
+	switch {
+	case errors.Is(err, domainerr.ErrNotFound):
+		return http.StatusNotFound, ErrorResponse{Code: "not_found", Message: err.Error()}
+	case errors.Is(err, domainerr.ErrAlreadyExists):
+		return http.StatusConflict, ErrorResponse{Code: "already_exists", Message: err.Error()}
+	case errors.Is(err, domainerr.ErrConflict):
+		return http.StatusConflict, ErrorResponse{Code: "conflict", Message: err.Error()}
+	case errors.Is(err, domainerr.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed, ErrorResponse{Code: "precondition_failed", Message: err.Error()}
+	case errors.Is(err, domainerr.ErrValidation):
+		resp := ErrorResponse{Code: "validation_failed", Message: err.Error()}
+		var ve *domainerr.ValidationError
+		if errors.As(err, &ve) {
+			resp.Fields = ve.Fields
+		}
+		return http.StatusUnprocessableEntity, resp
+	case errors.Is(err, domainerr.ErrUnauthenticated):
+		return http.StatusUnauthorized, ErrorResponse{Code: "unauthorized", Message: err.Error()}
+	case errors.Is(err, domainerr.ErrForbidden):
+		resp := ErrorResponse{Code: "forbidden", Message: err.Error()}
+		var pe *domainerr.PermissionError
+		if errors.As(err, &pe) {
+			resp.Permission = pe.Permission
+		}
+		return http.StatusForbidden, resp
+	case errors.Is(err, ErrBadRequest):
+		return http.StatusBadRequest, ErrorResponse{Code: "bad_request", Message: err.Error()}
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType, ErrorResponse{Code: "unsupported_media_type", Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, ErrorResponse{Code: "timeout", Message: "tempo limite da requisição esgotado"}
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest, ErrorResponse{Code: "client_closed_request", Message: "requisição cancelada pelo cliente"}
+	default:
+		return http.StatusInternalServerError, ErrorResponse{Code: "internal_error", Message: "erro interno do servidor"}
+	}
+}
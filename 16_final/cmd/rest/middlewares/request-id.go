@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+Chaves usadas para propagar o identificador da requisição.
+
+- RequestIDHeader: header HTTP lido da requisição e devolvido na resposta;
+- requestIDKey: chave onde o ID fica guardado no gin.Context.
+*/
+const (
+	RequestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+)
+
+/*
+RequestID garante que toda requisição tenha um identificador único.
+
+Se o cliente (ou um proxy) já enviou o header X-Request-ID, ele é reaproveitado;
+caso contrário, um novo ID aleatório é gerado. O ID é devolvido no header da
+resposta e incluído no corpo dos erros, facilitando a correlação com os logs.
+*/
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID retorna o ID da requisição atual (vazio se o middleware não estiver registrado).
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// newRequestID gera 16 bytes aleatórios em hexadecimal.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
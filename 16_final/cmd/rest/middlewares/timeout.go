@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+Timeout aplica um prazo (deadline) ao contexto de cada requisição.
+
+O prazo é escolhido por rota: timeoutFor recebe "MÉTODO /caminho" com o caminho
+registrado no roteador (ex: "GET /items/:id") e devolve a duração; zero desliga o prazo.
+
+O contexto com prazo substitui c.Request.Context(), que os handlers repassam ao caso
+de uso e ao repositório. Quando o prazo acaba, a query em andamento é interrompida e o
+erro context.DeadlineExceeded vira 504 no ErrorHandler. Se o cliente desconectar antes,
+o próprio net/http cancela o contexto (context.Canceled).
+*/
+func Timeout(timeoutFor func(route string) time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d := timeoutFor(c.Request.Method + " " + c.FullPath())
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
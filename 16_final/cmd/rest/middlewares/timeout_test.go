@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	handler "api/cmd/rest/handlers"
+	middleware "api/cmd/rest/middlewares"
+	"api/internal/core"
+	"api/internal/core/item"
+)
+
+// newTestRouter monta o roteador com os middlewares da API sobre um repositório em memória.
+func newTestRouter(timeouts map[string]time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := handler.NewHandler(core.NewItemUsecase(item.NewMapRepository()))
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.Timeout(func(route string) time.Duration { return timeouts[route] }))
+	router.GET("/items", h.ListItems)
+
+	// Rota lenta: só termina quando o contexto da requisição acaba
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.Error(c.Request.Context().Err())
+	})
+	return router
+}
+
+func TestTimeoutPerRoute(t *testing.T) {
+	router := newTestRouter(map[string]time.Duration{"GET /slow": 20 * time.Millisecond})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("GET /slow = %d, esperado 504; corpo: %s", rec.Code, rec.Body)
+	}
+
+	// Rotas sem prazo configurado seguem normalmente
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /items = %d, esperado 200; corpo: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestClientCancellationReachesRepository(t *testing.T) {
+	router := newTestRouter(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // o cliente desistiu antes da resposta
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil).WithContext(ctx))
+	if rec.Code != middleware.StatusClientClosedRequest {
+		t.Fatalf("GET /items cancelado = %d, esperado %d; corpo: %s", rec.Code, middleware.StatusClientClosedRequest, rec.Body)
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/internal/core/auth" // Principal, chaves de API e o repositório de chaves
+	"api/internal/core/domainerr"
+)
+
+/*
+AuthUsecase representa o caso de uso de autenticação.
+
+Aceita dois tipos de credencial:
+  - chaves de API (formato "inv_<prefixo>_<segredo>"), guardadas como hash no repositório;
+  - tokens JWT, validados pelo TokenVerifier configurado (nil = JWT desabilitado).
+*/
+type AuthUsecase struct {
+	keys   auth.APIKeyRepositoryPort // Repositório de chaves de API
+	tokens auth.TokenVerifier        // Validação de tokens JWT (nil se nenhuma chave JWT foi configurada)
+}
+
+/*
+NewAuthUsecase cria o caso de uso de autenticação.
+
+Parâmetros:
+- keys: repositório das chaves de API (ex: auth.NewSQLRepository);
+- tokens: validador de JWT (ex: jwt.New), ou nil para aceitar apenas chaves de API.
+*/
+func NewAuthUsecase(keys auth.APIKeyRepositoryPort, tokens auth.TokenVerifier) AuthUsecasePort {
+	return &AuthUsecase{
+		keys:   keys,
+		tokens: tokens,
+	}
+}
+
+/*
+Authenticate identifica o dono da credencial.
+
+Passos:
+ 1. Se a credencial tiver o formato de uma chave de API, busca a chave pelo prefixo e
+    compara o hash em tempo constante; chaves revogadas são recusadas;
+ 2. Caso contrário, trata a credencial como um token JWT.
+
+Retorna domainerr.ErrUnauthenticated para qualquer credencial inválida, e um erro
+comum (500) apenas se o repositório falhar.
+*/
+func (u *AuthUsecase) Authenticate(ctx context.Context, credential string) (auth.Principal, error) {
+	if prefix, ok := auth.ParseAPIKey(credential); ok {
+		k, err := u.keys.FindAPIKeyByPrefix(ctx, prefix)
+		if errors.Is(err, domainerr.ErrNotFound) {
+			return auth.Principal{}, domainerr.Unauthenticatedf("chave de API inválida")
+		}
+		if err != nil {
+			return auth.Principal{}, fmt.Errorf("error authenticating api key: %w", err)
+		}
+		if subtle.ConstantTimeCompare([]byte(auth.HashAPIKey(credential)), []byte(k.Hash)) != 1 {
+			return auth.Principal{}, domainerr.Unauthenticatedf("chave de API inválida")
+		}
+		if k.Revoked() {
+			return auth.Principal{}, domainerr.Unauthenticatedf("chave de API revogada")
+		}
+		return auth.Principal{Type: auth.PrincipalAPIKey, ID: strconv.Itoa(k.ID), Name: k.Name, Roles: k.Roles}, nil
+	}
+
+	if u.tokens == nil {
+		return auth.Principal{}, domainerr.Unauthenticatedf("credencial inválida: autenticação por JWT não está configurada")
+	}
+	p, err := u.tokens.VerifyToken(credential)
+	if err != nil {
+		return auth.Principal{}, domainerr.Unauthenticatedf("token inválido: %v", err)
+	}
+	return p, nil
+}
+
+/*
+CreateAPIKey gera uma chave de API aleatória, com os papéis informados, e grava o seu hash.
+
+Regras:
+- O nome é obrigatório e tem no máximo 255 caracteres.
+- Ao menos um papel, e apenas papéis conhecidos (ver auth.ValidateRoles); repetições são ignoradas.
+
+Retorna a chave gravada, a chave completa (mostrada uma única vez) e erro encadeado com contexto.
+*/
+func (u *AuthUsecase) CreateAPIKey(ctx context.Context, name string, roles []string) (auth.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return auth.APIKey{}, "", fmt.Errorf("invalid api key: %w", domainerr.Validation("name", "é obrigatório"))
+	case len(name) > 255:
+		return auth.APIKey{}, "", fmt.Errorf("invalid api key: %w", domainerr.Validation("name", "deve ter no máximo 255 caracteres"))
+	}
+
+	roles = slices.Clone(roles)
+	slices.Sort(roles)
+	roles = slices.Compact(roles)
+	if err := auth.ValidateRoles(roles); err != nil {
+		return auth.APIKey{}, "", fmt.Errorf("invalid api key: %w", err)
+	}
+
+	k, secret, err := auth.NewAPIKey(name, roles)
+	if err != nil {
+		return auth.APIKey{}, "", fmt.Errorf("error generating api key: %w", err)
+	}
+	if err := u.keys.SaveAPIKey(ctx, &k); err != nil {
+		return auth.APIKey{}, "", fmt.Errorf("error saving api key: %w", err)
+	}
+	return k, secret, nil
+}
+
+// ListAPIKeys lista as chaves cadastradas, inclusive as revogadas.
+func (u *AuthUsecase) ListAPIKeys(ctx context.Context) ([]auth.APIKey, error) {
+	keys, err := u.keys.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revoga a chave (domainerr.ErrNotFound se ela não existir ou já estiver revogada).
+func (u *AuthUsecase) RevokeAPIKey(ctx context.Context, id int) error {
+	if err := u.keys.RevokeAPIKey(ctx, id, time.Now()); err != nil {
+		return fmt.Errorf("error revoking api key: %w", err)
+	}
+	return nil
+}
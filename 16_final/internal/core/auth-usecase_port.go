@@ -0,0 +1,29 @@
+package core
+
+import (
+	"context"
+
+	"api/internal/core/auth"
+)
+
+/*
+AuthUsecasePort define a interface da camada de aplicação para autenticação.
+
+A API REST usa Authenticate em todas as rotas protegidas; a CLI usa os demais
+métodos para gerenciar as chaves de API (`cli keys create|list|revoke`).
+*/
+type AuthUsecasePort interface {
+	// Authenticate valida uma chave de API ou um token JWT e retorna o principal que ela identifica.
+	// Credenciais inválidas, expiradas ou revogadas retornam domainerr.ErrUnauthenticated.
+	Authenticate(ctx context.Context, credential string) (auth.Principal, error)
+
+	// CreateAPIKey gera e grava uma chave de API com os papéis informados (ver auth.Roles),
+	// retornando a chave completa, que não pode ser recuperada depois.
+	CreateAPIKey(ctx context.Context, name string, roles []string) (auth.APIKey, string, error)
+
+	// ListAPIKeys lista as chaves cadastradas (sem o segredo), inclusive as revogadas.
+	ListAPIKeys(ctx context.Context) ([]auth.APIKey, error)
+
+	// RevokeAPIKey revoga a chave, que deixa de autenticar imediatamente.
+	RevokeAPIKey(ctx context.Context, id int) error
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"api/internal/core/auth"
+	"api/internal/core/domainerr"
+)
+
+// fakeVerifier aceita apenas o token "valido", como o sujeito "maria".
+type fakeVerifier struct{}
+
+func (fakeVerifier) VerifyToken(token string) (auth.Principal, error) {
+	if token != "valido" {
+		return auth.Principal{}, errors.New("assinatura inválida")
+	}
+	return auth.Principal{Type: auth.PrincipalJWT, ID: "maria"}, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	u := NewAuthUsecase(auth.NewMapRepository(), fakeVerifier{})
+
+	k, key, err := u.CreateAPIKey(ctx, "  integração ERP ", []string{auth.RoleWarehouse, auth.RoleCatalog, auth.RoleWarehouse})
+	if err != nil || k.ID != 1 || k.Name != "integração ERP" || !strings.HasPrefix(key, "inv_"+k.Prefix+"_") ||
+		!slices.Equal(k.Roles, []string{auth.RoleCatalog, auth.RoleWarehouse}) {
+		t.Fatalf("CreateAPIKey = %+v, %q, %v", k, key, err)
+	}
+	if k.Hash == "" || strings.Contains(k.Hash, key) {
+		t.Fatalf("a chave não pode ser guardada em texto: %+v", k)
+	}
+
+	p, err := u.Authenticate(ctx, key)
+	want := auth.Principal{Type: auth.PrincipalAPIKey, ID: "1", Name: "integração ERP", Roles: []string{auth.RoleCatalog, auth.RoleWarehouse}}
+	if err != nil || !reflect.DeepEqual(p, want) {
+		t.Fatalf("Authenticate(chave) = %+v, %v", p, err)
+	}
+	if p, err := u.Authenticate(ctx, "valido"); err != nil || p.String() != "jwt:maria" {
+		t.Fatalf("Authenticate(token) = %+v, %v", p, err)
+	}
+
+	// Mesmo prefixo com outro segredo, token inválido e, depois, a chave revogada
+	forged := key[:len(key)-1] + "x"
+	if key[len(key)-1] == 'x' {
+		forged = key[:len(key)-1] + "y"
+	}
+	if err := u.RevokeAPIKey(ctx, k.ID); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	for _, credential := range []string{forged, "invalido", key} {
+		if _, err := u.Authenticate(ctx, credential); !errors.Is(err, domainerr.ErrUnauthenticated) {
+			t.Fatalf("Authenticate(%q) = %v, esperado ErrUnauthenticated", credential, err)
+		}
+	}
+	if err := u.RevokeAPIKey(ctx, k.ID); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("revogar de novo = %v, esperado ErrNotFound", err)
+	}
+
+	// Sem TokenVerifier, apenas chaves de API são aceitas
+	if _, err := NewAuthUsecase(auth.NewMapRepository(), nil).Authenticate(ctx, "valido"); !errors.Is(err, domainerr.ErrUnauthenticated) {
+		t.Fatalf("JWT sem verifier = %v", err)
+	}
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	u := NewAuthUsecase(auth.NewMapRepository(), nil)
+	for _, name := range []string{"", "   ", strings.Repeat("a", 256)} {
+		if _, _, err := u.CreateAPIKey(context.Background(), name, []string{auth.RoleViewer}); !errors.Is(err, domainerr.ErrValidation) {
+			t.Fatalf("CreateAPIKey(%d caracteres) = %v, esperado ErrValidation", len(name), err)
+		}
+	}
+	for _, roles := range [][]string{nil, {"root"}, {auth.RoleViewer, "Admin"}} {
+		if _, _, err := u.CreateAPIKey(context.Background(), "ERP", roles); !errors.Is(err, domainerr.ErrValidation) {
+			t.Fatalf("CreateAPIKey(roles %q) = %v, esperado ErrValidation", roles, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+/*
+Tipos de credencial aceitos em Principal.Type.
+*/
+const (
+	PrincipalAPIKey = "api_key" // Chave de API gerada pela CLI (`cli keys create`)
+	PrincipalJWT    = "jwt"     // Token JWT emitido por um provedor de identidade externo
+	PrincipalCLI    = "cli"     // Operador da CLI (usuário do sistema operacional)
+)
+
+/*
+Principal identifica quem fez a requisição, depois que a credencial foi validada.
+
+É guardado no contexto da requisição (WithPrincipal), de onde os casos de uso e os
+logs de auditoria o leem com FromContext.
+*/
+type Principal struct {
+	Type  string   `json:"type"`  // PrincipalAPIKey, PrincipalJWT ou PrincipalCLI
+	ID    string   `json:"id"`    // ID da chave de API, claim `sub` do token ou usuário da CLI
+	Name  string   `json:"name"`  // Nome da chave ou claim `name` do token (pode ser vazio)
+	Roles []string `json:"roles"` // Papéis (ver Roles), que definem as permissões (ver Can)
+}
+
+// String identifica o principal nos logs e no histórico, ex: "api_key:3" ou "jwt:maria@empresa.com".
+func (p Principal) String() string {
+	return p.Type + ":" + p.ID
+}
+
+// principalKey é a chave (não exportada, para evitar colisões) do Principal no context.Context.
+type principalKey struct{}
+
+// WithPrincipal devolve uma cópia de ctx carregando o principal autenticado.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+/*
+FromContext retorna o principal guardado em ctx por WithPrincipal.
+
+O segundo retorno é false quando não há principal: autenticação desabilitada
+(`auth.enabled: false`) ou chamadas internas, como a configuração inicial dos testes.
+*/
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+/*
+APIKey representa uma chave de API cadastrada.
+
+A chave em si (ex: "inv_3f9a0c1d2e4b_8c1f...") só é mostrada uma vez, na criação: o banco
+guarda apenas o prefixo, usado para localizá-la, e o hash SHA-256 da chave completa.
+*/
+type APIKey struct {
+	ID        int        `json:"id"`                   // Identificador único da chave
+	Name      string     `json:"name"`                 // Nome descritivo (ex: "integração ERP")
+	Prefix    string     `json:"prefix"`               // Parte pública da chave, única, exibida na listagem
+	Hash      string     `json:"-"`                    // SHA-256 (hexadecimal) da chave completa
+	CreatedAt time.Time  `json:"created_at"`           // Data de criação
+	Roles     []string   `json:"roles"`                // Papéis concedidos a quem usa a chave (ver Roles)
+	RevokedAt *time.Time `json:"revoked_at,omitempty"` // Data da revogação (nil = chave ativa)
+}
+
+// Revoked indica se a chave foi revogada.
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+/*
+Formato das chaves de API: "inv_" + prefixo (12 caracteres hexadecimais) + "_" + segredo (64 caracteres).
+
+O segredo tem 256 bits aleatórios, então um hash rápido (SHA-256) já é suficiente:
+não há senha fraca para proteger contra força bruta, como aconteceria com bcrypt.
+*/
+const (
+	apiKeyScheme      = "inv_"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+/*
+NewAPIKey gera uma chave de API aleatória com o nome e os papéis informados.
+
+Retorna:
+- a APIKey pronta para ser gravada (com Prefix e Hash, sem ID);
+- a chave completa, que deve ser entregue ao usuário e não é guardada em lugar nenhum;
+- um erro, se o gerador aleatório falhar.
+*/
+func NewAPIKey(name string, roles []string) (APIKey, string, error) {
+	buf := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return APIKey{}, "", err
+	}
+	prefix := hex.EncodeToString(buf[:apiKeyPrefixBytes])
+	key := apiKeyScheme + prefix + "_" + hex.EncodeToString(buf[apiKeyPrefixBytes:])
+	return APIKey{Name: name, Prefix: prefix, Hash: HashAPIKey(key), Roles: roles}, key, nil
+}
+
+/*
+ParseAPIKey extrai o prefixo de uma chave no formato gerado por NewAPIKey.
+
+O segundo retorno é false se a credencial não tiver esse formato (ex: um token JWT).
+*/
+func ParseAPIKey(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, apiKeyScheme)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*apiKeyPrefixBytes || len(secret) != 2*apiKeySecretBytes {
+		return "", false
+	}
+	return prefix, true
+}
+
+// HashAPIKey retorna o SHA-256 da chave em hexadecimal, como guardado em APIKey.Hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+/*
+APIKeyRepositoryPort define o contrato dos repositórios de chaves de API.
+
+Segue as mesmas regras de item.ItemRepositoryPort: os erros de domínio vêm de
+domainerr e todos os métodos respeitam o cancelamento do contexto.
+*/
+type APIKeyRepositoryPort interface {
+	// SaveAPIKey grava uma nova chave, preenchendo k.ID e k.CreatedAt.
+	SaveAPIKey(ctx context.Context, k *APIKey) error
+
+	// FindAPIKeyByPrefix busca a chave pelo prefixo (inclusive as revogadas).
+	// Retorna domainerr.ErrNotFound caso a chave não exista.
+	FindAPIKeyByPrefix(ctx context.Context, prefix string) (APIKey, error)
+
+	// ListAPIKeys lista todas as chaves, inclusive as revogadas, em ordem de ID.
+	ListAPIKeys(ctx context.Context) ([]APIKey, error)
+
+	// RevokeAPIKey marca a chave como revogada em at.
+	// Retorna domainerr.ErrNotFound se a chave não existir ou já estiver revogada.
+	RevokeAPIKey(ctx context.Context, id int, at time.Time) error
+}
+
+/*
+TokenVerifier valida tokens de portador (JWT) e devolve o principal que eles identificam.
+
+A implementação fica em internal/platform/jwt; qualquer erro é tratado como credencial inválida.
+*/
+type TokenVerifier interface {
+	VerifyToken(token string) (Principal, error)
+}
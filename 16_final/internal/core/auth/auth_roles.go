@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"api/internal/core/domainerr" // Erros de domínio (PermissionError, ...)
+)
+
+/*
+Permission é uma ação sobre o catálogo que pode ser concedida a um papel (role).
+*/
+type Permission string
+
+/*
+Permissões verificadas pelos casos de uso de itens e de importação.
+*/
+const (
+	PermItemsRead   Permission = "items:read"   // Buscar, listar e exportar itens, histórico de estoque e importações
+	PermItemsWrite  Permission = "items:write"  // Criar e alterar itens (código, título, descrição, status)
+	PermItemsPrice  Permission = "items:price"  // Definir ou alterar o preço
+	PermStockAdjust Permission = "stock:adjust" // Movimentar o estoque (ou alterá-lo diretamente em uma escrita)
+	PermItemsDelete Permission = "items:delete" // Mover para a lixeira, restaurar e expurgar
+)
+
+/*
+Papéis (roles) conhecidos e as permissões de cada um.
+
+  - viewer: apenas consulta;
+  - warehouse: equipe do armazém, movimenta o estoque mas não altera o cadastro nem o preço;
+  - catalog: cadastro de itens, sem preço e sem estoque;
+  - manager: cadastro completo, com preço e estoque, mas sem excluir;
+  - admin: todas as permissões.
+*/
+const (
+	RoleViewer    = "viewer"
+	RoleWarehouse = "warehouse"
+	RoleCatalog   = "catalog"
+	RoleManager   = "manager"
+	RoleAdmin     = "admin"
+)
+
+// rolePermissions é a política: as permissões concedidas por cada papel.
+var rolePermissions = map[string][]Permission{
+	RoleViewer:    {PermItemsRead},
+	RoleWarehouse: {PermItemsRead, PermStockAdjust},
+	RoleCatalog:   {PermItemsRead, PermItemsWrite},
+	RoleManager:   {PermItemsRead, PermItemsWrite, PermItemsPrice, PermStockAdjust},
+	RoleAdmin:     {PermItemsRead, PermItemsWrite, PermItemsPrice, PermStockAdjust, PermItemsDelete},
+}
+
+// Roles lista os papéis conhecidos, do mais restrito ao mais amplo.
+func Roles() []string {
+	return []string{RoleViewer, RoleWarehouse, RoleCatalog, RoleManager, RoleAdmin}
+}
+
+/*
+ValidateRoles confere uma lista de papéis informada por um operador (ex: `cli keys create --roles`).
+
+Retorna domainerr.ValidationError no campo "roles" se a lista estiver vazia
+ou tiver um papel desconhecido.
+*/
+func ValidateRoles(roles []string) error {
+	if len(roles) == 0 {
+		return domainerr.Validation("roles", fmt.Sprintf("informe ao menos um papel (%s)", strings.Join(Roles(), ", ")))
+	}
+	for _, r := range roles {
+		if _, ok := rolePermissions[r]; !ok {
+			return domainerr.Validation("roles", fmt.Sprintf("papel desconhecido %q (use %s)", r, strings.Join(Roles(), ", ")))
+		}
+	}
+	return nil
+}
+
+/*
+Can indica se algum dos papéis do principal concede perm.
+Papéis desconhecidos (ex: vindos de um token JWT) não concedem nada.
+*/
+func (p Principal) Can(perm Permission) bool {
+	for _, r := range p.Roles {
+		if slices.Contains(rolePermissions[r], perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin indica se o principal tem o papel RoleAdmin, que enxerga recursos de outros principais.
+func (p Principal) IsAdmin() bool {
+	return slices.Contains(p.Roles, RoleAdmin)
+}
+
+/*
+Require confere se o principal do contexto tem todas as permissões informadas.
+
+Sem principal no contexto (autenticação desabilitada ou chamadas internas) não há
+restrição. A primeira permissão que faltar é devolvida como *domainerr.PermissionError,
+identificado por `errors.Is(err, domainerr.ErrForbidden)`.
+*/
+func Require(ctx context.Context, perms ...Permission) error {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, perm := range perms {
+		if !p.Can(perm) {
+			return &domainerr.PermissionError{Permission: string(perm), Principal: p.String()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"api/internal/core/domainerr" // Erros de domínio (NotFound, ...)
+)
+
+/*
+MapRepository é o repositório de chaves de API em memória.
+
+Usado pelo backend `memory` e nos testes. As chaves só existem enquanto o processo
+estiver de pé, então não podem ser criadas pela CLI (outro processo): nesse backend,
+a API REST autentica apenas por JWT.
+*/
+type MapRepository struct {
+	mu   sync.RWMutex // Protege keys
+	keys []APIKey     // Chaves em ordem de ID (ID = posição + 1)
+}
+
+// NewMapRepository cria um repositório de chaves vazio.
+func NewMapRepository() APIKeyRepositoryPort {
+	return &MapRepository{}
+}
+
+// SaveAPIKey grava a chave com o próximo ID; prefixos repetidos são rejeitados, como no banco.
+func (r *MapRepository) SaveAPIKey(ctx context.Context, k *APIKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.keys {
+		if other.Prefix == k.Prefix {
+			return domainerr.AlreadyExistsf("já existe uma chave de API com o prefixo %q", k.Prefix)
+		}
+	}
+	k.ID = len(r.keys) + 1
+	k.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	r.keys = append(r.keys, copyKey(*k))
+	return nil
+}
+
+// FindAPIKeyByPrefix busca a chave pelo prefixo.
+func (r *MapRepository) FindAPIKeyByPrefix(ctx context.Context, prefix string) (APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return APIKey{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.keys {
+		if k.Prefix == prefix {
+			return copyKey(k), nil
+		}
+	}
+	return APIKey{}, domainerr.NotFoundf("chave de API com prefixo %q não encontrada", prefix)
+}
+
+// ListAPIKeys lista todas as chaves em ordem de ID.
+func (r *MapRepository) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]APIKey, 0, len(r.keys))
+	for _, k := range r.keys {
+		keys = append(keys, copyKey(k))
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey marca a chave como revogada, se ela existir e ainda estiver ativa.
+func (r *MapRepository) RevokeAPIKey(ctx context.Context, id int, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > len(r.keys) || r.keys[id-1].Revoked() {
+		return domainerr.NotFoundf("chave de API com ID %d não existe ou já foi revogada", id)
+	}
+	at = at.UTC().Truncate(time.Second)
+	r.keys[id-1].RevokedAt = &at
+	return nil
+}
+
+// copyKey copia RevokedAt e Roles, para que quem recebe a chave não altere o estado interno.
+func copyKey(k APIKey) APIKey {
+	k.Roles = slices.Clone(k.Roles)
+	if k.RevokedAt != nil {
+		at := *k.RevokedAt
+		k.RevokedAt = &at
+	}
+	return k
+}
@@ -0,0 +1,90 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"api/internal/core/auth"
+	"api/internal/core/domainerr"
+	"api/internal/platform/migrations"
+	sqlitesetup "api/internal/platform/sqlite"
+)
+
+// TestAPIKeyRepositories roda o mesmo roteiro contra o repositório em memória e o SQLite.
+func TestAPIKeyRepositories(t *testing.T) {
+	repos := map[string]func(t *testing.T) auth.APIKeyRepositoryPort{
+		"memory": func(t *testing.T) auth.APIKeyRepositoryPort {
+			return auth.NewMapRepository()
+		},
+		"sqlite": func(t *testing.T) auth.APIKeyRepositoryPort {
+			db, err := sqlitesetup.NewSQLiteSetup(":memory:")
+			if err != nil {
+				t.Fatalf("falha ao abrir o SQLite: %v", err)
+			}
+			t.Cleanup(func() { db.Close() })
+			m, err := migrations.New(db, "sqlite")
+			if err != nil {
+				t.Fatalf("falha ao carregar as migrações: %v", err)
+			}
+			if _, err := m.Up(context.Background()); err != nil {
+				t.Fatalf("falha ao aplicar as migrações: %v", err)
+			}
+			return auth.NewSQLRepository(db)
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+
+			var saved []auth.APIKey
+			for _, keyName := range []string{"ERP", "loja"} {
+				k, _, err := auth.NewAPIKey(keyName, []string{auth.RoleCatalog, auth.RoleWarehouse})
+				if err != nil {
+					t.Fatalf("NewAPIKey: %v", err)
+				}
+				if err := repo.SaveAPIKey(ctx, &k); err != nil || k.ID == 0 || k.CreatedAt.IsZero() {
+					t.Fatalf("SaveAPIKey = %+v, %v", k, err)
+				}
+				saved = append(saved, k)
+			}
+
+			got, err := repo.FindAPIKeyByPrefix(ctx, saved[1].Prefix)
+			if err != nil || got.ID != saved[1].ID || got.Hash != saved[1].Hash || got.Revoked() || !slices.Equal(got.Roles, saved[1].Roles) {
+				t.Fatalf("FindAPIKeyByPrefix = %+v, %v", got, err)
+			}
+			if _, err := repo.FindAPIKeyByPrefix(ctx, "000000000000"); !errors.Is(err, domainerr.ErrNotFound) {
+				t.Fatalf("prefixo inexistente: %v", err)
+			}
+
+			at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+			if err := repo.RevokeAPIKey(ctx, saved[0].ID, at); err != nil {
+				t.Fatalf("RevokeAPIKey: %v", err)
+			}
+			if err := repo.RevokeAPIKey(ctx, saved[0].ID, at); !errors.Is(err, domainerr.ErrNotFound) {
+				t.Fatalf("revogar de novo: %v", err)
+			}
+			if err := repo.RevokeAPIKey(ctx, 99, at); !errors.Is(err, domainerr.ErrNotFound) {
+				t.Fatalf("revogar inexistente: %v", err)
+			}
+
+			keys, err := repo.ListAPIKeys(ctx)
+			if err != nil || len(keys) != 2 || keys[0].Name != "ERP" || keys[1].Name != "loja" {
+				t.Fatalf("ListAPIKeys = %+v, %v", keys, err)
+			}
+			if !keys[0].Revoked() || !keys[0].RevokedAt.Equal(at) || keys[1].Revoked() {
+				t.Fatalf("revogação não aparece na listagem: %+v", keys)
+			}
+
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+			if _, err := repo.ListAPIKeys(canceled); !errors.Is(err, context.Canceled) {
+				t.Fatalf("contexto cancelado: %v", err)
+			}
+		})
+	}
+}
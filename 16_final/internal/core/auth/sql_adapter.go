@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"api/internal/core/domainerr" // Erros de domínio (NotFound, ...)
+)
+
+/*
+sqlRepository implementa APIKeyRepositoryPort sobre a tabela `api_keys`.
+
+As queries são as mesmas no MySQL e no SQLite, então um único adaptador atende
+os dois bancos (a tabela vem das migrações de internal/platform/migrations).
+*/
+type sqlRepository struct {
+	db *sql.DB // Conexão ativa com o banco de dados
+}
+
+/*
+NewSQLRepository retorna o repositório de chaves de API sobre db (MySQL ou SQLite).
+
+Retorna:
+- A interface APIKeyRepositoryPort, com a implementação SQL concreta.
+*/
+func NewSQLRepository(db *sql.DB) APIKeyRepositoryPort {
+	return &sqlRepository{db: db}
+}
+
+// apiKeyColumns é a lista de colunas lidas de `api_keys`, na ordem esperada por scanAPIKey.
+const apiKeyColumns = `id, name, prefix, hash, roles, created_at, revoked_at`
+
+/*
+SaveAPIKey insere a chave em `api_keys`, com created_at gravado pela aplicação
+(em UTC, sem frações de segundo, como nas demais tabelas).
+
+Os papéis são gravados em uma única coluna, separados por vírgula (ex: "warehouse,catalog").
+*/
+func (r *sqlRepository) SaveAPIKey(ctx context.Context, k *APIKey) error {
+	k.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO api_keys (name, prefix, hash, roles, created_at) VALUES (?, ?, ?, ?, ?)`,
+		k.Name, k.Prefix, k.Hash, strings.Join(k.Roles, ","), k.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	k.ID = int(id)
+	return nil
+}
+
+// FindAPIKeyByPrefix busca a chave pelo prefixo (coluna única).
+func (r *sqlRepository) FindAPIKeyByPrefix(ctx context.Context, prefix string) (APIKey, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE prefix=?`, prefix)
+	k, err := scanAPIKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return APIKey{}, domainerr.NotFoundf("chave de API com prefixo %q não encontrada", prefix)
+	}
+	return k, err
+}
+
+// ListAPIKeys lista todas as chaves em ordem de ID.
+func (r *sqlRepository) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey preenche revoked_at, apenas se a chave ainda estiver ativa.
+func (r *sqlRepository) RevokeAPIKey(ctx context.Context, id int, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at=? WHERE id=? AND revoked_at IS NULL`,
+		at.UTC().Truncate(time.Second), id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domainerr.NotFoundf("chave de API com ID %d não existe ou já foi revogada", id)
+	}
+	return nil
+}
+
+// scanner é satisfeito tanto por *sql.Row quanto por *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAPIKey lê uma linha de `api_keys` (na ordem de apiKeyColumns).
+func scanAPIKey(row scanner) (APIKey, error) {
+	var (
+		k         APIKey
+		roles     string
+		revokedAt sql.NullTime
+	)
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.Hash, &roles, &k.CreatedAt, &revokedAt)
+	if roles != "" {
+		k.Roles = strings.Split(roles, ",")
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return k, err
+}
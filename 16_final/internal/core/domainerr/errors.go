@@ -0,0 +1,126 @@
+package domainerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+Erros de domínio compartilhados por repositórios, casos de uso e camadas de entrega.
+
+Os repositórios devolvem estes erros (encadeados com `%w`), os casos de uso apenas
+acrescentam contexto, e cada camada de entrega (REST, gRPC, CLI) decide como
+apresentá-los usando `errors.Is` / `errors.As`:
+
+- ErrNotFound:           o recurso não existe                  → HTTP 404
+- ErrAlreadyExists:      já existe um recurso com a mesma chave → HTTP 409
+- ErrConflict:           a operação conflita com o estado atual → HTTP 409
+- ErrValidation:         os dados não respeitam as regras      → HTTP 422
+- ErrPreconditionFailed: a versão esperada não é mais a atual  → HTTP 412
+- ErrUnauthenticated:    credencial ausente, inválida ou revogada → HTTP 401
+- ErrForbidden:          falta uma permissão ao principal      → HTTP 403
+*/
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrConflict           = errors.New("conflict")
+	ErrValidation         = errors.New("validation failed")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrForbidden          = errors.New("forbidden")
+)
+
+/*
+FieldError descreve a violação de uma regra em um campo específico.
+*/
+type FieldError struct {
+	Field   string `json:"field"`   // Nome do campo (igual à tag json, ex: "price")
+	Message string `json:"message"` // Descrição da violação
+}
+
+/*
+ValidationError agrupa todas as violações encontradas em uma entrada.
+
+Ele é identificado por `errors.Is(err, ErrValidation)` e os campos podem ser
+recuperados com `errors.As(err, &ve)`.
+*/
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error lista as violações no formato "campo: mensagem; campo: mensagem".
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return ErrValidation.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Is faz com que `errors.Is(err, ErrValidation)` reconheça um ValidationError.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+/*
+Validation cria um ValidationError com uma única violação.
+
+Exemplo:
+
+	return domainerr.Validation("id", "ID do item não pode ser 0")
+*/
+func Validation(field, message string) error {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+/*
+PermissionError indica que o principal autenticado não tem a permissão exigida pela operação.
+
+Como o ValidationError, é identificado por `errors.Is(err, ErrForbidden)`, e a permissão
+que falta pode ser recuperada com `errors.As(err, &pe)`.
+*/
+type PermissionError struct {
+	Permission string // Permissão que falta (ex: "items:price")
+	Principal  string // Quem tentou a operação (ex: "api_key:3")
+}
+
+// Error descreve a permissão que falta, ex: "forbidden: api_key:3 não tem a permissão items:price".
+func (e *PermissionError) Error() string {
+	return ErrForbidden.Error() + ": " + e.Principal + " não tem a permissão " + e.Permission
+}
+
+// Is faz com que `errors.Is(err, ErrForbidden)` reconheça um PermissionError.
+func (e *PermissionError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// NotFoundf cria um erro formatado encadeado com ErrNotFound.
+func NotFoundf(format string, args ...any) error {
+	return wrapf(ErrNotFound, format, args...)
+}
+
+// AlreadyExistsf cria um erro formatado encadeado com ErrAlreadyExists.
+func AlreadyExistsf(format string, args ...any) error {
+	return wrapf(ErrAlreadyExists, format, args...)
+}
+
+// Conflictf cria um erro formatado encadeado com ErrConflict.
+func Conflictf(format string, args ...any) error {
+	return wrapf(ErrConflict, format, args...)
+}
+
+// PreconditionFailedf cria um erro formatado encadeado com ErrPreconditionFailed.
+func PreconditionFailedf(format string, args ...any) error {
+	return wrapf(ErrPreconditionFailed, format, args...)
+}
+
+// Unauthenticatedf cria um erro formatado encadeado com ErrUnauthenticated.
+func Unauthenticatedf(format string, args ...any) error {
+	return wrapf(ErrUnauthenticated, format, args...)
+}
+
+// wrapf formata a mensagem e encadeia o erro sentinela com `%w`.
+func wrapf(sentinel error, format string, args ...any) error {
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), sentinel)
+}
@@ -0,0 +1,643 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"api/internal/core/auth"
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+/*
+Limites da importação de planilhas.
+
+  - importChunkSize: linhas processadas por vez (uma busca por código e um lote de gravação);
+  - importSyncRows: arquivos com mais linhas que isso são processados em segundo plano;
+  - importJobTTL: por quanto tempo uma importação terminada continua disponível para consulta.
+*/
+const (
+	importChunkSize = item.MaxBatchSize
+	importSyncRows  = item.MaxBatchSize
+	importJobTTL    = 24 * time.Hour
+)
+
+/*
+ImportUsecase representa o caso de uso de importação de planilhas de itens.
+
+Cada linha é um upsert pelo código (SKU): códigos novos criam itens e códigos
+existentes atualizam o item, com as mesmas regras de validação de ItemUsecase.
+O acompanhamento das importações (jobs) fica em memória, então não sobrevive a
+um reinício da API e não é compartilhado entre réplicas.
+*/
+type ImportUsecase struct {
+	repo item.ItemRepositoryPort    // Abstração do repositório (MySQL, memória, etc.)
+	mu   sync.Mutex                 // Protege jobs e os campos de cada job
+	jobs map[string]*item.ImportJob // Importações por ID
+}
+
+/*
+NewImportUsecase cria o caso de uso de importação, injetando o repositório de itens.
+
+Retorna:
+- ImportUsecasePort (interface da aplicação)
+*/
+func NewImportUsecase(repo item.ItemRepositoryPort) ImportUsecasePort {
+	return &ImportUsecase{
+		repo: repo,
+		jobs: make(map[string]*item.ImportJob),
+	}
+}
+
+// importLine é uma linha de dados da planilha, com o número que ela tem no arquivo.
+type importLine struct {
+	number int      // Número da linha (o cabeçalho é a linha 1)
+	record []string // Células da linha
+}
+
+// importTally acumula o resultado de um bloco de linhas.
+type importTally struct {
+	created, updated, unchanged, failed int
+	errors                              []item.ImportRowError
+}
+
+/*
+StartImport importa as linhas de uma planilha (ver item.ImportRequest).
+
+Passos:
+ 1. Resolve as colunas: o mapeamento informado e, para as demais, colunas com o nome
+    de um campo do item. Um mapeamento inválido ou a falta da coluna `code` é
+    domainerr.ValidationError no campo "mapping", e nada é importado.
+    Exige items:write, mais items:price se houver coluna `price` e stock:adjust se houver
+    coluna `stock` (a permissão é conferida para o arquivo inteiro, antes de qualquer linha).
+ 2. Cria o job; arquivos com mais de importSyncRows linhas (ou com req.Async) são
+    processados em segundo plano, desvinculados do cancelamento da requisição.
+ 3. Processa as linhas em blocos (ver importChunk), atualizando os contadores do job.
+
+Retorna:
+  - O job como está ao final da chamada (terminado, ou pendente se em segundo plano), ou
+  - Erro encadeado com contexto se o arquivo for inválido ou se uma importação
+    síncrona for interrompida por uma falha geral (o job fica como item.ImportFailed).
+*/
+func (u *ImportUsecase) StartImport(ctx context.Context, req item.ImportRequest) (item.ImportJob, error) {
+	if err := auth.Require(ctx, auth.PermItemsWrite); err != nil {
+		return item.ImportJob{}, fmt.Errorf("permission denied: %w", err)
+	}
+	if len(req.Rows) == 0 {
+		return item.ImportJob{}, fmt.Errorf("invalid import: %w", domainerr.Validation("file", "o arquivo está vazio"))
+	}
+	cols, err := importColumns(req.Rows[0], req.Mapping)
+	if err != nil {
+		return item.ImportJob{}, fmt.Errorf("invalid import: %w", err)
+	}
+	if err := requireImportPerms(ctx, cols); err != nil {
+		return item.ImportJob{}, fmt.Errorf("permission denied: %w", err)
+	}
+	lines := dataLines(req.Rows[1:])
+	if len(lines) == 0 {
+		return item.ImportJob{}, fmt.Errorf("invalid import: %w", domainerr.Validation("file", "o arquivo não tem linhas de dados"))
+	}
+
+	id, err := newImportID()
+	if err != nil {
+		return item.ImportJob{}, fmt.Errorf("error creating import job: %w", err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	job := &item.ImportJob{
+		ID:        id,
+		Status:    item.ImportPending,
+		Filename:  req.Filename,
+		Owner:     importOwner(ctx),
+		DryRun:    req.DryRun,
+		Total:     len(lines),
+		CreatedAt: now,
+	}
+
+	u.mu.Lock()
+	u.pruneJobs(now)
+	u.jobs[id] = job
+	u.mu.Unlock()
+
+	if req.Async || len(lines) > importSyncRows {
+		go u.run(context.WithoutCancel(ctx), job, cols, lines)
+		return u.snapshot(job), nil
+	}
+	if err := u.run(ctx, job, cols, lines); err != nil {
+		return item.ImportJob{}, fmt.Errorf("error importing items: %w", err)
+	}
+	return u.snapshot(job), nil
+}
+
+// requireImportPerms exige items:price e stock:adjust se a planilha tiver as colunas `price` e `stock`.
+func requireImportPerms(ctx context.Context, cols map[string]int) error {
+	var perms []auth.Permission
+	if _, ok := cols["price"]; ok {
+		perms = append(perms, auth.PermItemsPrice)
+	}
+	if _, ok := cols["stock"]; ok {
+		perms = append(perms, auth.PermStockAdjust)
+	}
+	return auth.Require(ctx, perms...)
+}
+
+/*
+GetImport retorna uma cópia do estado atual da importação, com o relatório de erros.
+
+Só quem iniciou a importação (ou um admin) a enxerga, já que o relatório repete dados
+das linhas enviadas; para os demais principais, o job é tratado como inexistente.
+
+Retorna domainerr.ErrNotFound se o job não existir, já tiver expirado (importJobTTL)
+ou pertencer a outro principal.
+*/
+func (u *ImportUsecase) GetImport(ctx context.Context, id string) (item.ImportJob, error) {
+	if err := auth.Require(ctx, auth.PermItemsRead); err != nil {
+		return item.ImportJob{}, fmt.Errorf("permission denied: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return item.ImportJob{}, err
+	}
+	u.mu.Lock()
+	job, ok := u.jobs[id]
+	u.mu.Unlock()
+	if ok && !canSeeImport(ctx, job.Owner) {
+		ok = false
+	}
+	if !ok {
+		return item.ImportJob{}, fmt.Errorf("error getting import: %w", domainerr.NotFoundf("importação %q não existe", id))
+	}
+	return u.snapshot(job), nil
+}
+
+// importOwner identifica o principal do contexto como dono de uma nova importação.
+func importOwner(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.String()
+	}
+	return ""
+}
+
+/*
+canSeeImport indica se o principal do contexto pode ver a importação de owner.
+
+Sem principal no contexto (autenticação desabilitada ou chamadas internas) não há
+restrição, como em auth.Require.
+*/
+func canSeeImport(ctx context.Context, owner string) bool {
+	p, ok := auth.FromContext(ctx)
+	return !ok || p.IsAdmin() || p.String() == owner
+}
+
+/*
+run processa as linhas do job em blocos de importChunkSize.
+
+Uma falha geral (banco, contexto) interrompe a importação: os blocos anteriores
+continuam gravados e o job termina como item.ImportFailed, com o erro em Err.
+*/
+func (u *ImportUsecase) run(ctx context.Context, job *item.ImportJob, cols map[string]int, lines []importLine) error {
+	u.update(job, func(j *item.ImportJob) { j.Status = item.ImportRunning })
+
+	seen := make(map[string]int, len(lines)) // Código → linha em que apareceu pela primeira vez
+	for start := 0; start < len(lines); start += importChunkSize {
+		chunk := lines[start:min(start+importChunkSize, len(lines))]
+		t, err := u.importChunk(ctx, chunk, cols, seen, job.DryRun)
+		if err != nil {
+			u.finish(job, err)
+			return err
+		}
+		u.update(job, func(j *item.ImportJob) {
+			j.Processed += len(chunk)
+			j.Created += t.created
+			j.Updated += t.updated
+			j.Unchanged += t.unchanged
+			j.Failed += t.failed
+			j.Errors = append(j.Errors, t.errors...)
+		})
+	}
+	u.finish(job, nil)
+	return nil
+}
+
+/*
+importChunk valida e grava um bloco de linhas.
+
+Passos:
+ 1. Converte as células de cada linha; valores que não são números (price, stock)
+    e códigos repetidos no arquivo rejeitam a linha.
+ 2. Busca, em uma única consulta, os itens ativos com os códigos do bloco.
+ 3. Código novo: monta o item (status padrão item.StatusActive) e o valida como SaveItem.
+    Código existente: aplica as células preenchidas sobre o item atual (células vazias
+    mantêm o valor atual) e o valida como PatchItem; linhas sem alteração não são gravadas.
+ 4. Fora do dry-run, grava as criações e as alterações em lotes best-effort
+    (ver ItemRepositoryPort.SaveItems e PatchItems); erros de uma linha vão para o relatório.
+
+Retorna o resultado do bloco, ou erro se o repositório falhar como um todo.
+*/
+func (u *ImportUsecase) importChunk(ctx context.Context, chunk []importLine, cols map[string]int, seen map[string]int, dryRun bool) (importTally, error) {
+	var t importTally
+	fail := func(number int, code string, err error) {
+		t.failed++
+		t.errors = append(t.errors, rowErrors(number, code, err)...)
+	}
+
+	parsed := make([]importLine, 0, len(chunk))
+	codes := make([]string, 0, len(chunk))
+	for _, l := range chunk {
+		it, err := applyCells(item.Item{}, l.record, cols)
+		if err != nil {
+			fail(l.number, it.Code, err)
+			continue
+		}
+		if first, dup := seen[it.Code]; dup && it.Code != "" {
+			fail(l.number, it.Code, domainerr.Validation("code", fmt.Sprintf("repete o código da linha %d", first)))
+			continue
+		}
+		seen[it.Code] = l.number
+		parsed = append(parsed, l)
+		codes = append(codes, it.Code)
+	}
+
+	found, err := u.repo.FindByCodes(ctx, codes)
+	if err != nil {
+		return t, fmt.Errorf("error getting items: %w", err)
+	}
+	current := make(map[string]item.Item, len(found))
+	for _, it := range found {
+		current[it.Code] = it
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var (
+		creates     []*item.Item
+		changes     []item.ItemChange
+		createLines []int // createLines[k] é a linha de creates[k]
+		changeLines []int // changeLines[k] é a linha de changes[k]
+	)
+	for k, l := range parsed {
+		cur, exists := current[codes[k]]
+		if !exists {
+			it, _ := applyCells(item.Item{}, l.record, cols)
+			if it.Status == "" {
+				it.Status = item.StatusActive
+			}
+			if err := validateRules(item.Item{}, it); err != nil {
+				fail(l.number, it.Code, err)
+				continue
+			}
+			it.CreatedAt, it.UpdatedAt = now, now
+			creates = append(creates, &it)
+			createLines = append(createLines, l.number)
+			continue
+		}
+
+		patched, _ := applyCells(cur, l.record, cols)
+		fields := item.ChangedFields(cur, patched)
+		if len(fields) == 0 {
+			t.unchanged++
+			continue
+		}
+		if err := validateRules(cur, patched); err != nil {
+			fail(l.number, cur.Code, err)
+			continue
+		}
+		patched.UpdatedAt = now
+		changes = append(changes, item.ItemChange{Item: &patched, Fields: fields})
+		changeLines = append(changeLines, l.number)
+	}
+
+	// No dry-run, as linhas válidas apenas contam como criadas ou atualizadas
+	if dryRun {
+		if creates, err = u.dropReservedCodes(ctx, creates, createLines, fail); err != nil {
+			return t, err
+		}
+		t.created += len(creates)
+		t.updated += len(changes)
+		creates, changes = nil, nil
+	}
+
+	if len(creates) > 0 {
+		errs, err := u.repo.SaveItems(ctx, creates, false)
+		if err != nil {
+			return t, fmt.Errorf("error saving items: %w", err)
+		}
+		for k, number := range createLines {
+			if errs[k] != nil {
+				fail(number, creates[k].Code, errs[k])
+			} else {
+				t.created++
+			}
+		}
+	}
+	if len(changes) > 0 {
+		errs, err := u.repo.PatchItems(ctx, changes, false)
+		if err != nil {
+			return t, fmt.Errorf("error patching items: %w", err)
+		}
+		for k, number := range changeLines {
+			if errs[k] != nil {
+				fail(number, changes[k].Item.Code, errs[k])
+			} else {
+				t.updated++
+			}
+		}
+	}
+
+	// O relatório segue a ordem da planilha, independentemente da etapa em que a linha falhou
+	sort.SliceStable(t.errors, func(i, j int) bool { return t.errors[i].Row < t.errors[j].Row })
+	return t, nil
+}
+
+// update altera o job sob a trava, para que GetImport veja um estado consistente.
+func (u *ImportUsecase) update(job *item.ImportJob, fn func(*item.ImportJob)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	fn(job)
+}
+
+// finish marca o fim do job: item.ImportDone, ou item.ImportFailed com o erro em Err.
+func (u *ImportUsecase) finish(job *item.ImportJob, err error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	u.update(job, func(j *item.ImportJob) {
+		j.Status, j.Err, j.FinishedAt = item.ImportDone, err, &now
+		if err != nil {
+			j.Status = item.ImportFailed
+		}
+	})
+}
+
+// snapshot copia o job sob a trava (inclusive o relatório de erros, que cresce enquanto ele roda).
+func (u *ImportUsecase) snapshot(job *item.ImportJob) item.ImportJob {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	j := *job
+	j.Errors = slices.Clone(job.Errors)
+	return j
+}
+
+// pruneJobs remove as importações terminadas há mais de importJobTTL. Deve ser chamada com a trava.
+func (u *ImportUsecase) pruneJobs(now time.Time) {
+	for id, j := range u.jobs {
+		if j.FinishedAt != nil && now.Sub(*j.FinishedAt) > importJobTTL {
+			delete(u.jobs, id)
+		}
+	}
+}
+
+// newImportID gera um ID aleatório (hexadecimal) para a importação.
+func newImportID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+/*
+importColumns resolve o índice da coluna de cada campo do item (nomes de item.PatchFields).
+
+Regras:
+  - Os nomes de coluna são comparados sem diferenciar maiúsculas e sem espaços nas pontas.
+  - Cada entrada de mapping (coluna → campo) deve apontar para uma coluna do cabeçalho
+    e para um campo válido, e dois mapeamentos não podem levar ao mesmo campo.
+  - Colunas fora do mapeamento cujo nome é o de um campo são usadas diretamente
+    (ex: o CSV gerado por `items export`); as demais, como `id`, são ignoradas.
+  - Alguma coluna precisa corresponder a `code`, a chave do upsert.
+
+Retorna as violações em um domainerr.ValidationError no campo "mapping".
+*/
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	var fields []domainerr.FieldError
+	add := func(message string) {
+		fields = append(fields, domainerr.FieldError{Field: "mapping", Message: message})
+	}
+
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, dup := byName[normalizeColumn(name)]; !dup {
+			byName[normalizeColumn(name)] = i
+		}
+	}
+
+	columns := make([]string, 0, len(mapping))
+	for column := range mapping {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	cols := make(map[string]int, len(item.PatchFields))
+	mapped := make(map[int]bool, len(mapping))
+	for _, column := range columns {
+		field := normalizeColumn(mapping[column])
+		i, ok := byName[normalizeColumn(column)]
+		switch _, dup := cols[field]; {
+		case !slices.Contains(item.PatchFields, field):
+			add(fmt.Sprintf("campo %q da coluna %q não existe (use %s)", mapping[column], column, strings.Join(item.PatchFields, ", ")))
+		case !ok:
+			add(fmt.Sprintf("a coluna %q não existe no arquivo", column))
+		case dup:
+			add(fmt.Sprintf("mais de uma coluna mapeada para o campo %q", field))
+		default:
+			cols[field], mapped[i] = i, true
+		}
+	}
+
+	for i, name := range header {
+		field := normalizeColumn(name)
+		if _, done := cols[field]; done || mapped[i] || !slices.Contains(item.PatchFields, field) {
+			continue
+		}
+		cols[field] = i
+	}
+
+	if _, ok := cols["code"]; !ok && len(fields) == 0 {
+		add("nenhuma coluna corresponde ao campo code; informe o mapeamento (ex: {\"SKU\": \"code\"})")
+	}
+	if len(fields) > 0 {
+		return nil, &domainerr.ValidationError{Fields: fields}
+	}
+	return cols, nil
+}
+
+// normalizeColumn padroniza um nome de coluna ou de campo para comparação.
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// dataLines numera as linhas de dados (a primeira é a linha 2 do arquivo) e descarta as linhas em branco.
+func dataLines(rows [][]string) []importLine {
+	lines := make([]importLine, 0, len(rows))
+	for i, record := range rows {
+		if slices.ContainsFunc(record, func(cell string) bool { return strings.TrimSpace(cell) != "" }) {
+			lines = append(lines, importLine{number: i + 2, record: record})
+		}
+	}
+	return lines
+}
+
+/*
+applyCells copia as células preenchidas da linha para os campos de base.
+
+Células vazias (ou ausentes) mantêm o valor de base. price aceita tanto "1234.56"
+quanto o formato brasileiro "1.234,56". Valores que não são números são acumulados
+em um domainerr.ValidationError; o item volta com os campos que puderam ser lidos.
+*/
+func applyCells(base item.Item, record []string, cols map[string]int) (item.Item, error) {
+	it := base
+	var fields []domainerr.FieldError
+	for _, field := range item.PatchFields {
+		i, ok := cols[field]
+		if !ok || i >= len(record) {
+			continue
+		}
+		v := strings.TrimSpace(record[i])
+		if v == "" {
+			continue
+		}
+
+		switch field {
+		case "code":
+			it.Code = v
+		case "title":
+			it.Title = v
+		case "description":
+			it.Description = v
+		case "status":
+			it.Status = v
+		case "price":
+			price, err := parseDecimal(v)
+			if errors.Is(err, errAmbiguousDecimal) {
+				fields = append(fields, domainerr.FieldError{Field: field, Message: fmt.Sprintf("%q: %v", v, err)})
+				continue
+			}
+			if err != nil {
+				fields = append(fields, domainerr.FieldError{Field: field, Message: fmt.Sprintf("%q não é um número", v)})
+				continue
+			}
+			it.Price = price
+		case "stock":
+			stock, err := strconv.Atoi(v)
+			if err != nil {
+				fields = append(fields, domainerr.FieldError{Field: field, Message: fmt.Sprintf("%q não é um número inteiro", v)})
+				continue
+			}
+			it.Stock = stock
+		}
+	}
+	if len(fields) > 0 {
+		return it, &domainerr.ValidationError{Fields: fields}
+	}
+	return it, nil
+}
+
+/*
+dropReservedCodes tira de creates as linhas cujo código está reservado por um item na lixeira,
+registrando-as em fail com domainerr.ErrAlreadyExists, como a gravação faria.
+
+É usada no dry-run, que não grava e por isso não recebe esse erro do repositório;
+lines[k] é o número da linha de creates[k]. Retorna as linhas que seriam criadas.
+*/
+func (u *ImportUsecase) dropReservedCodes(ctx context.Context, creates []*item.Item, lines []int, fail func(int, string, error)) ([]*item.Item, error) {
+	if len(creates) == 0 {
+		return creates, nil
+	}
+	codes := make([]string, len(creates))
+	for k, it := range creates {
+		codes[k] = it.Code
+	}
+	trashed, err := u.repo.FindTrashedByCodes(ctx, codes)
+	if err != nil {
+		return nil, fmt.Errorf("error getting trashed items: %w", err)
+	}
+	reserved := make(map[string]bool, len(trashed))
+	for _, it := range trashed {
+		reserved[it.Code] = true
+	}
+
+	kept := creates[:0]
+	for k, it := range creates {
+		if reserved[it.Code] {
+			fail(lines[k], it.Code, domainerr.AlreadyExistsf("já existe um item na lixeira com o código %q", it.Code))
+			continue
+		}
+		kept = append(kept, it)
+	}
+	return kept, nil
+}
+
+/*
+errAmbiguousDecimal indica um número cujos separadores não permitem saber qual é o decimal
+(ex: "1.234.5" ou "1,23,456"), em vez de importar um preço errado.
+*/
+var errAmbiguousDecimal = errors.New("separadores de milhar e decimal ambíguos")
+
+/*
+parseDecimal converte um número no formato brasileiro ("1.234,56") ou americano ("1,234.56").
+
+Regras:
+  - com vírgula e ponto, o separador que aparece por último é o decimal e o outro é o de milhar;
+  - com apenas um deles, uma única ocorrência é o separador decimal ("1,5" e "1.5" valem 1,5),
+    exceto se seguida de exatamente 3 dígitos ("1,500" ou "1.500"), que pode ser milhar em
+    qualquer dos formatos e é recusada com errAmbiguousDecimal;
+    várias ocorrências são separadores de milhar ("1.234.567");
+  - os grupos depois de um separador de milhar precisam ter 3 dígitos; caso contrário o
+    formato é ambíguo e o valor é recusado com errAmbiguousDecimal.
+*/
+func parseDecimal(v string) (float64, error) {
+	last := strings.LastIndexAny(v, ",.")
+	if last < 0 {
+		return strconv.ParseFloat(v, 64)
+	}
+
+	dec, thousands := v[last:last+1], "."
+	if dec == "." {
+		thousands = ","
+	}
+	intPart, frac := v[:last], v[last+1:]
+	if strings.Count(v, dec) > 1 {
+		// Só há separadores de milhar (ex: "1.234.567"), desde que o outro separador não apareça
+		if strings.Contains(v, thousands) {
+			return 0, errAmbiguousDecimal
+		}
+		intPart, frac, thousands = v, "", dec
+	} else if !strings.Contains(v, thousands) && len(frac) == 3 {
+		// "1,500" é 1500 no formato americano e 1,5 no brasileiro
+		return 0, errAmbiguousDecimal
+	}
+	if strings.Contains(intPart, thousands) {
+		groups := strings.Split(intPart, thousands)
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return 0, errAmbiguousDecimal
+			}
+		}
+		if groups[0] == "" || groups[0] == "-" || groups[0] == "+" {
+			return 0, errAmbiguousDecimal
+		}
+		intPart = strings.Join(groups, "")
+	}
+	if frac != "" {
+		intPart += "." + frac
+	}
+	return strconv.ParseFloat(intPart, 64)
+}
+
+// rowErrors monta as linhas do relatório para o erro de uma linha: uma por campo se for de validação.
+func rowErrors(number int, code string, err error) []item.ImportRowError {
+	var ve *domainerr.ValidationError
+	if !errors.As(err, &ve) {
+		return []item.ImportRowError{{Row: number, Code: code, Message: err.Error()}}
+	}
+	out := make([]item.ImportRowError, len(ve.Fields))
+	for i, f := range ve.Fields {
+		out[i] = item.ImportRowError{Row: number, Code: code, Field: f.Field, Message: f.Message}
+	}
+	return out
+}
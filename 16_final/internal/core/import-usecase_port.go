@@ -0,0 +1,23 @@
+package core
+
+import (
+	"context"
+
+	"api/internal/core/item"
+)
+
+/*
+ImportUsecasePort define a interface da camada de aplicação para a importação de planilhas de itens.
+
+Assim como ItemUsecasePort, desacopla a camada de entrega (REST, CLI) da lógica de
+importação: o handler lê o arquivo (CSV ou XLSX) e entrega as linhas prontas.
+*/
+type ImportUsecasePort interface {
+	// StartImport valida o cabeçalho e o mapeamento de colunas e importa as linhas com upsert pelo código.
+	// Arquivos pequenos são processados na hora (o job volta terminado); os demais, em segundo plano.
+	StartImport(ctx context.Context, req item.ImportRequest) (item.ImportJob, error)
+
+	// GetImport retorna o estado atual da importação, com o relatório de erros por linha
+	// (domainerr.ErrNotFound se o job não existir, já tiver expirado ou for de outro principal que não um admin).
+	GetImport(ctx context.Context, id string) (item.ImportJob, error)
+}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"api/internal/core/auth"
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+// importRows é a planilha usada nos testes de importação, com cabeçalho próprio (mapeado).
+var importRows = [][]string{
+	{"SKU", "Nome", "Preço", "stock", "id"},
+	{"ITEM-001", "", "12,50", "", "99"},    // 2: atualiza o preço do item existente (sem título: mantém o atual)
+	{"ITEM-002", "Lápis", "1.5", "30", ""}, // 3: cria
+	{"ITEM-003", "Borracha", "", "x", ""},  // 4: estoque inválido
+	{"", "", "", "", ""},                   // 5: em branco, ignorada
+	{"ITEM-002", "Outro", "", "", ""},      // 6: código repetido
+	{"ITEM-004", "", "-1", "", ""},         // 7: título obrigatório e preço negativo
+	{"ITEM-001", "Caneta", "", "", ""},     // 8: código repetido (mesmo sem alterar nada)
+}
+
+var importMapping = map[string]string{"SKU": "code", "Nome": "title", "Preço": "price"}
+
+// wantImportErrors é o relatório esperado para importRows.
+var wantImportErrors = []item.ImportRowError{
+	{Row: 4, Code: "ITEM-003", Field: "stock", Message: `"x" não é um número inteiro`},
+	{Row: 6, Code: "ITEM-002", Field: "code", Message: "repete o código da linha 3"},
+	{Row: 7, Code: "ITEM-004", Field: "title", Message: "é obrigatório"},
+	{Row: 7, Code: "ITEM-004", Field: "price", Message: "não pode ser negativo"},
+	{Row: 8, Code: "ITEM-001", Field: "code", Message: "repete o código da linha 2"},
+}
+
+// TestStartImport cobre o upsert pelo código, o dry-run e o relatório de erros por linha.
+func TestStartImport(t *testing.T) {
+	ctx := context.Background()
+	repo := item.NewMapRepository()
+	items := NewItemUsecase(repo)
+	existing, err := items.SaveItem(ctx, item.Item{Code: "ITEM-001", Title: "Caneta", Price: 2, Stock: 5})
+	if err != nil {
+		t.Fatalf("SaveItem: %v", err)
+	}
+	u := NewImportUsecase(repo)
+
+	for _, dryRun := range []bool{true, false} {
+		job, err := u.StartImport(ctx, item.ImportRequest{Rows: importRows, Mapping: importMapping, DryRun: dryRun})
+		if err != nil {
+			t.Fatalf("StartImport (dry_run=%v): %v", dryRun, err)
+		}
+		if job.Status != item.ImportDone || job.Total != 6 || job.Processed != 6 ||
+			job.Created != 1 || job.Updated != 1 || job.Unchanged != 0 || job.Failed != 4 {
+			t.Fatalf("job (dry_run=%v) = %+v", dryRun, job)
+		}
+		if !reflect.DeepEqual(job.Errors, wantImportErrors) {
+			t.Fatalf("relatório (dry_run=%v) = %+v", dryRun, job.Errors)
+		}
+
+		got, _ := items.GetItem(ctx, existing.ID)
+		_, errNew := items.GetItemByCode(ctx, "ITEM-002")
+		if dryRun && (got.Price != 2 || !errors.Is(errNew, domainerr.ErrNotFound)) {
+			t.Fatalf("o dry-run gravou: %+v, %v", got, errNew)
+		}
+		if !dryRun && (got.Price != 12.5 || got.Title != "Caneta" || got.Stock != 5 || errNew != nil) {
+			t.Fatalf("importação não gravou: %+v, %v", got, errNew)
+		}
+	}
+
+	// Reimportar o mesmo arquivo não altera nada
+	job, err := u.StartImport(ctx, item.ImportRequest{Rows: importRows[:4], Mapping: importMapping})
+	if err != nil || job.Unchanged != 2 || job.Created+job.Updated != 0 || job.Failed != 1 {
+		t.Fatalf("reimportação = %+v, %v", job, err)
+	}
+
+	if got, err := u.GetImport(ctx, job.ID); err != nil || got.ID != job.ID || len(got.Errors) != 1 {
+		t.Fatalf("GetImport = %+v, %v", got, err)
+	}
+	if _, err := u.GetImport(ctx, "nao-existe"); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("GetImport de job inexistente: %v", err)
+	}
+}
+
+// TestStartImportDryRunTrashedCode garante que o dry-run prevê a recusa de um código reservado por item na lixeira.
+func TestStartImportDryRunTrashedCode(t *testing.T) {
+	ctx := context.Background()
+	repo := item.NewMapRepository()
+	items := NewItemUsecase(repo)
+	trashed, err := items.SaveItem(ctx, item.Item{Code: "ITEM-001", Title: "Caneta"})
+	if err != nil {
+		t.Fatalf("SaveItem: %v", err)
+	}
+	if err := items.DeleteItem(ctx, trashed.ID, 0); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	u := NewImportUsecase(repo)
+
+	rows := [][]string{{"code", "title"}, {"ITEM-001", "Caneta"}, {"ITEM-002", "Lápis"}}
+	for _, dryRun := range []bool{true, false} {
+		job, err := u.StartImport(ctx, item.ImportRequest{Rows: rows, DryRun: dryRun})
+		if err != nil || job.Created != 1 || job.Failed != 1 || len(job.Errors) != 1 || job.Errors[0].Row != 2 {
+			t.Fatalf("StartImport (dry_run=%v) = %+v, %v", dryRun, job, err)
+		}
+	}
+}
+
+// TestGetImportOwner garante que só quem iniciou a importação, ou um admin, vê o job e o relatório.
+func TestGetImportOwner(t *testing.T) {
+	principal := func(id, role string) context.Context {
+		return auth.WithPrincipal(context.Background(), auth.Principal{Type: auth.PrincipalAPIKey, ID: id, Roles: []string{role}})
+	}
+	u := NewImportUsecase(item.NewMapRepository())
+	job, err := u.StartImport(principal("1", auth.RoleManager), item.ImportRequest{Rows: importRows, Mapping: importMapping})
+	if err != nil {
+		t.Fatalf("StartImport: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"dono", principal("1", auth.RoleViewer), nil},
+		{"outro principal", principal("2", auth.RoleManager), domainerr.ErrNotFound},
+		{"admin", principal("3", auth.RoleAdmin), nil},
+		{"sem autenticação", context.Background(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := u.GetImport(tt.ctx, job.ID)
+			if !errors.Is(err, tt.wantErr) || (err == nil && got.ID != job.ID) {
+				t.Fatalf("GetImport = %+v, %v; esperado erro %v", got, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestStartImportAsync garante que a importação em segundo plano termina e pode ser acompanhada.
+func TestStartImportAsync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := NewImportUsecase(item.NewMapRepository())
+
+	job, err := u.StartImport(ctx, item.ImportRequest{Rows: importRows, Mapping: importMapping, Async: true})
+	if err != nil {
+		t.Fatalf("StartImport: %v", err)
+	}
+	cancel() // A importação não depende da requisição que a iniciou
+	if job.Finished() {
+		t.Fatalf("job assíncrono voltou terminado: %+v", job)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !job.Finished() {
+		if time.Now().After(deadline) {
+			t.Fatalf("a importação não terminou: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = u.GetImport(context.Background(), job.ID); err != nil {
+			t.Fatalf("GetImport: %v", err)
+		}
+	}
+	if job.Status != item.ImportDone || job.Created != 1 || job.Failed != 5 || job.FinishedAt == nil {
+		t.Fatalf("job = %+v", job)
+	}
+}
+
+// TestImportColumns cobre a resolução do cabeçalho e do mapeamento de colunas.
+func TestImportColumns(t *testing.T) {
+	header := []string{" Code ", "SKU", "Título", "price"}
+
+	cols, err := importColumns(header, nil)
+	if err != nil || !reflect.DeepEqual(cols, map[string]int{"code": 0, "price": 3}) {
+		t.Fatalf("sem mapeamento: %v, %v", cols, err)
+	}
+	cols, err = importColumns(header, map[string]string{"sku": "code", "Título": "Title"})
+	if err != nil || !reflect.DeepEqual(cols, map[string]int{"code": 1, "title": 2, "price": 3}) {
+		t.Fatalf("com mapeamento: %v, %v", cols, err)
+	}
+
+	tests := []struct {
+		name    string
+		header  []string
+		mapping map[string]string
+	}{
+		{"campo inexistente", header, map[string]string{"SKU": "sku"}},
+		{"coluna inexistente", header, map[string]string{"EAN": "code"}},
+		{"campo mapeado duas vezes", header, map[string]string{"SKU": "title", "Título": "title"}},
+		{"sem coluna de código", []string{"title", "price"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importColumns(tt.header, tt.mapping)
+			var ve *domainerr.ValidationError
+			if !errors.As(err, &ve) || ve.Fields[0].Field != "mapping" {
+				t.Fatalf("esperado erro de validação em mapping, obtido %v", err)
+			}
+		})
+	}
+}
+
+// TestParseDecimal cobre os formatos brasileiro e americano e os casos ambíguos, recusados.
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"1.234,56", 1234.56, false},
+		{"1,234.56", 1234.56, false},
+		{"1234,5", 1234.5, false},
+		{"1,5", 1.5, false},
+		{"2.5", 2.5, false},
+		{"12", 12, false},
+		{"1.234.567", 1234567, false},
+		{"1,234,567.5", 1234567.5, false},
+		{"-1.234,5", -1234.5, false},
+		{"1,500", 0, true},
+		{"1.500", 0, true},
+		{"1.234.5", 0, true},
+		{"1,23,456.7", 0, true},
+		{"1.2,34.5", 0, true},
+		{",5.1", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseDecimal(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("parseDecimal(%q) = %v, %v; esperado %v (erro: %v)", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"context"
+
+	"api/internal/core/auth"
+	"api/internal/core/item"
+)
+
+/*
+A autorização das operações sobre itens fica nos casos de uso, e não nos handlers,
+para que a mesma política valha para a REST, o gRPC e a CLI. Cada entrega só precisa
+colocar o principal no contexto (auth.WithPrincipal); sem principal não há restrição.
+
+Permissões exigidas (ver auth.Permission):
+  - leituras (busca, listagem, exportação, lixeira, histórico, importações): items:read;
+  - criação e alteração: items:write, mais items:price se o preço for definido ou
+    alterado, e stock:adjust se o estoque for definido ou alterado;
+  - movimentação de estoque: stock:adjust;
+  - exclusão, restauração e expurgo: items:delete.
+*/
+
+/*
+requireChangePerms exige as permissões dos campos sensíveis que mudam de cur para next:
+items:price para o preço e stock:adjust para o estoque (em uma criação, cur é o item vazio).
+*/
+func requireChangePerms(ctx context.Context, cur, next item.Item) error {
+	var perms []auth.Permission
+	if next.Price != cur.Price {
+		perms = append(perms, auth.PermItemsPrice)
+	}
+	if next.Stock != cur.Stock {
+		perms = append(perms, auth.PermStockAdjust)
+	}
+	return auth.Require(ctx, perms...)
+}
+
+/*
+authorizeUpdate exige items:write e, se o preço ou o estoque de it forem diferentes dos
+gravados, items:price e stock:adjust. O item atual só é lido do repositório quando o
+principal não tem as duas permissões, então o caso comum não custa uma consulta a mais.
+
+Quando o item atual é lido e it não traz versão, it.Version passa a ser a versão conferida:
+assim a gravação falha (domainerr.ErrPreconditionFailed) se o item mudar entre a conferência
+e a gravação, em vez de devolver um preço ou estoque alterado nesse meio-tempo.
+*/
+func (u *ItemUsecase) authorizeUpdate(ctx context.Context, it *item.Item) error {
+	if err := auth.Require(ctx, auth.PermItemsWrite); err != nil {
+		return err
+	}
+	if auth.Require(ctx, auth.PermItemsPrice, auth.PermStockAdjust) == nil {
+		return nil
+	}
+	cur, err := u.repo.FindByID(ctx, it.ID)
+	if err != nil {
+		return err
+	}
+	if it.Version == 0 {
+		it.Version = cur.Version
+	}
+	return requireChangePerms(ctx, cur, *it)
+}
@@ -0,0 +1,209 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"api/internal/core/auth"
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+// TestItemPolicyMatrix confere cada operação com cada papel: permitida, ou negada com a permissão que falta.
+func TestItemPolicyMatrix(t *testing.T) {
+	type usecases struct {
+		items   ItemUsecasePort
+		imports ImportUsecasePort
+		seed    item.Item // Item ativo (ID 1): preço 2.5, estoque 10
+		trashed item.Item // Item na lixeira (ID 2)
+	}
+	merge := func(body string) item.Patch { return item.Patch{Type: item.PatchMerge, Body: []byte(body)} }
+
+	tests := []struct {
+		name    string
+		op      func(ctx context.Context, u usecases) error
+		perms   []auth.Permission // Permissões exigidas, na ordem em que são conferidas
+		allowed []string          // Papéis que podem executar a operação
+	}{
+		{"buscar", func(ctx context.Context, u usecases) error {
+			_, err := u.items.GetItem(ctx, u.seed.ID)
+			return err
+		}, []auth.Permission{auth.PermItemsRead}, auth.Roles()},
+		{"listar a lixeira", func(ctx context.Context, u usecases) error {
+			_, err := u.items.ListTrash(ctx, item.ListFilter{})
+			return err
+		}, []auth.Permission{auth.PermItemsRead}, auth.Roles()},
+		{"criar sem preço e estoque", func(ctx context.Context, u usecases) error {
+			_, err := u.items.SaveItem(ctx, item.Item{Code: "ITEM-100", Title: "Lápis"})
+			return err
+		}, []auth.Permission{auth.PermItemsWrite}, []string{auth.RoleCatalog, auth.RoleManager, auth.RoleAdmin}},
+		{"criar com preço", func(ctx context.Context, u usecases) error {
+			_, err := u.items.SaveItem(ctx, item.Item{Code: "ITEM-100", Title: "Lápis", Price: 1})
+			return err
+		}, []auth.Permission{auth.PermItemsWrite, auth.PermItemsPrice}, []string{auth.RoleManager, auth.RoleAdmin}},
+		{"substituir mantendo preço e estoque", func(ctx context.Context, u usecases) error {
+			it := u.seed
+			it.Title = "Caneta azul"
+			_, err := u.items.UpdateItem(ctx, it)
+			return err
+		}, []auth.Permission{auth.PermItemsWrite}, []string{auth.RoleCatalog, auth.RoleManager, auth.RoleAdmin}},
+		{"substituir alterando o preço", func(ctx context.Context, u usecases) error {
+			it := u.seed
+			it.Price = 3
+			_, err := u.items.UpdateItem(ctx, it)
+			return err
+		}, []auth.Permission{auth.PermItemsWrite, auth.PermItemsPrice}, []string{auth.RoleManager, auth.RoleAdmin}},
+		{"patch no título", func(ctx context.Context, u usecases) error {
+			_, err := u.items.PatchItem(ctx, u.seed.ID, 0, merge(`{"title": "Caneta azul"}`))
+			return err
+		}, []auth.Permission{auth.PermItemsWrite}, []string{auth.RoleCatalog, auth.RoleManager, auth.RoleAdmin}},
+		{"patch no estoque", func(ctx context.Context, u usecases) error {
+			_, err := u.items.PatchItem(ctx, u.seed.ID, 0, merge(`{"stock": 5}`))
+			return err
+		}, []auth.Permission{auth.PermItemsWrite, auth.PermStockAdjust}, []string{auth.RoleManager, auth.RoleAdmin}},
+		{"patch em lote no preço", func(ctx context.Context, u usecases) error {
+			results, err := u.items.PatchItems(ctx, []item.BatchPatch{{ID: u.seed.ID, Patch: merge(`{"price": 3}`)}}, true)
+			if err != nil {
+				return err
+			}
+			return results[0].Err
+		}, []auth.Permission{auth.PermItemsWrite, auth.PermItemsPrice}, []string{auth.RoleManager, auth.RoleAdmin}},
+		{"movimentar o estoque", func(ctx context.Context, u usecases) error {
+			_, err := u.items.AdjustStock(ctx, u.seed.ID, item.StockMovement{Type: item.MovementSale, Delta: -1}, false)
+			return err
+		}, []auth.Permission{auth.PermStockAdjust}, []string{auth.RoleWarehouse, auth.RoleManager, auth.RoleAdmin}},
+		{"importar com preço", func(ctx context.Context, u usecases) error {
+			_, err := u.imports.StartImport(ctx, item.ImportRequest{Rows: [][]string{{"code", "title", "price"}, {"ITEM-100", "Lápis", "1"}}})
+			return err
+		}, []auth.Permission{auth.PermItemsWrite, auth.PermItemsPrice}, []string{auth.RoleManager, auth.RoleAdmin}},
+		{"excluir", func(ctx context.Context, u usecases) error {
+			return u.items.DeleteItem(ctx, u.seed.ID, 0)
+		}, []auth.Permission{auth.PermItemsDelete}, []string{auth.RoleAdmin}},
+		{"excluir em lote", func(ctx context.Context, u usecases) error {
+			_, err := u.items.DeleteItems(ctx, []item.ItemRef{{ID: u.seed.ID}}, true)
+			return err
+		}, []auth.Permission{auth.PermItemsDelete}, []string{auth.RoleAdmin}},
+		{"restaurar", func(ctx context.Context, u usecases) error {
+			_, err := u.items.RestoreItem(ctx, u.trashed.ID, 0)
+			return err
+		}, []auth.Permission{auth.PermItemsDelete}, []string{auth.RoleAdmin}},
+		{"expurgar", func(ctx context.Context, u usecases) error {
+			_, err := u.items.PurgeDeleted(ctx, 0)
+			return err
+		}, []auth.Permission{auth.PermItemsDelete}, []string{auth.RoleAdmin}},
+	}
+
+	setup := func(t *testing.T) usecases {
+		t.Helper()
+		ctx := context.Background() // Sem principal: sem restrições
+		repo := item.NewMapRepository()
+		u := usecases{items: NewItemUsecase(repo), imports: NewImportUsecase(repo)}
+		var err error
+		if u.seed, err = u.items.SaveItem(ctx, item.Item{Code: "ITEM-001", Title: "Caneta", Price: 2.5, Stock: 10}); err != nil {
+			t.Fatalf("SaveItem: %v", err)
+		}
+		if u.trashed, err = u.items.SaveItem(ctx, item.Item{Code: "ITEM-002", Title: "Borracha"}); err != nil {
+			t.Fatalf("SaveItem: %v", err)
+		}
+		if err := u.items.DeleteItem(ctx, u.trashed.ID, 0); err != nil {
+			t.Fatalf("DeleteItem: %v", err)
+		}
+		return u
+	}
+
+	for _, tt := range tests {
+		for _, role := range append(auth.Roles(), "") {
+			name := role
+			if role == "" {
+				name = "sem principal"
+			}
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				ctx := context.Background()
+				if role != "" {
+					ctx = auth.WithPrincipal(ctx, auth.Principal{Type: auth.PrincipalAPIKey, ID: "7", Roles: []string{role}})
+				}
+				err := tt.op(ctx, setup(t))
+
+				if role == "" || slices.Contains(tt.allowed, role) {
+					if err != nil {
+						t.Fatalf("esperado permitido, obtido %v", err)
+					}
+					return
+				}
+				// A permissão reportada é a primeira exigida que o papel não tem
+				var missing auth.Permission
+				for _, perm := range tt.perms {
+					if !(auth.Principal{Roles: []string{role}}).Can(perm) {
+						missing = perm
+						break
+					}
+				}
+				var pe *domainerr.PermissionError
+				if !errors.Is(err, domainerr.ErrForbidden) || !errors.As(err, &pe) || pe.Permission != string(missing) || pe.Principal != "api_key:7" {
+					t.Fatalf("esperado forbidden por %q, obtido %v", missing, err)
+				}
+			})
+		}
+	}
+}
+
+// TestRequireUnknownRole garante que papéis desconhecidos (ex: vindos de um JWT) não concedem nada.
+func TestRequireUnknownRole(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Type: auth.PrincipalJWT, ID: "maria", Roles: []string{"superuser"}})
+	if err := auth.Require(ctx, auth.PermItemsRead); !errors.Is(err, domainerr.ErrForbidden) {
+		t.Fatalf("Require = %v, esperado ErrForbidden", err)
+	}
+	if err := auth.Require(ctx); err != nil {
+		t.Fatalf("Require sem permissões = %v", err)
+	}
+}
+
+// racingRepo executa race logo depois do primeiro FindByID, simulando uma alteração
+// concorrente entre a conferência de permissões e a gravação.
+type racingRepo struct {
+	item.ItemRepositoryPort
+	race func()
+}
+
+func (r *racingRepo) FindByID(ctx context.Context, id int) (item.Item, error) {
+	it, err := r.ItemRepositoryPort.FindByID(ctx, id)
+	if r.race != nil {
+		race := r.race
+		r.race = nil
+		race()
+	}
+	return it, err
+}
+
+// TestUpdateWithoutPricePermissionPinsVersion garante que um PUT sem items:price não
+// desfaz um preço alterado entre a conferência e a gravação.
+func TestUpdateWithoutPricePermissionPinsVersion(t *testing.T) {
+	bg := context.Background()
+	repo := &racingRepo{ItemRepositoryPort: item.NewMapRepository()}
+	u := NewItemUsecase(repo)
+	seed, err := u.SaveItem(bg, item.Item{Code: "ITEM-001", Title: "Caneta", Price: 2.5})
+	if err != nil {
+		t.Fatalf("SaveItem: %v", err)
+	}
+	repo.race = func() {
+		repriced := seed
+		repriced.Price = 3
+		if err := repo.ItemRepositoryPort.UpdateItem(bg, &repriced); err != nil {
+			t.Fatalf("UpdateItem concorrente: %v", err)
+		}
+	}
+
+	// O catálogo (sem items:price) reenvia o item com o preço antigo e sem versão
+	ctx := auth.WithPrincipal(bg, auth.Principal{Type: auth.PrincipalAPIKey, ID: "7", Roles: []string{auth.RoleCatalog}})
+	stale := seed
+	stale.Version = 0
+	stale.Title = "Caneta azul"
+	if _, err := u.UpdateItem(ctx, stale); !errors.Is(err, domainerr.ErrPreconditionFailed) {
+		t.Fatalf("UpdateItem = %v, esperado ErrPreconditionFailed", err)
+	}
+	if got, _ := repo.FindByID(bg, seed.ID); got.Price != 3 || got.Title != "Caneta" {
+		t.Fatalf("item gravado = %+v; esperado o preço concorrente (3) preservado", got)
+	}
+}
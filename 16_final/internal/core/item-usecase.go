@@ -1,11 +1,14 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"api/internal/core/auth" // Principal autenticado (permissões e actor das movimentações)
+	"api/internal/core/domainerr"
 	"api/internal/core/item" // Pacote que contém a entidade Item e a interface do repositório
-	// Pacote onde vivem os erros globais, como config.ErrNotFound
 )
 
 /*
@@ -36,74 +39,376 @@ func NewItemUsecase(repo item.ItemRepositoryPort) ItemUsecasePort {
 /*
 SaveItem salva um novo item, repassando a chamada para o repositório.
 
-Pode aplicar validações de negócio antes de salvar (se necessário).
+Antes de salvar, aplica as regras de negócio (ver validateItem).
+Se o status não for informado, o item é criado como item.StatusActive.
+O ID é sempre gerado pelo repositório: qualquer ID informado pelo cliente é ignorado.
+
+Exige items:write, mais items:price se o preço não for zero e stock:adjust se o estoque
+não for zero (ver item-authorization.go).
 
 Retorna:
+- O item persistido (com ID, versão e timestamps), ou
+- domainerr.PermissionError se faltar uma permissão, ou
+- domainerr.ValidationError com todas as violações encontradas, ou
 - Erro encadeado com contexto, caso ocorra problema no repositório.
 */
-func (u *ItemUsecase) SaveItem(it item.Item) error {
-	// Inicializa os timestamps
-	now := time.Now()
+func (u *ItemUsecase) SaveItem(ctx context.Context, it item.Item) (item.Item, error) {
+	if err := auth.Require(ctx, auth.PermItemsWrite); err != nil {
+		return item.Item{}, fmt.Errorf("permission denied: %w", err)
+	}
+	if err := requireChangePerms(ctx, item.Item{}, it); err != nil {
+		return item.Item{}, fmt.Errorf("permission denied: %w", err)
+	}
+
+	it.ID = 0
+	if it.Status == "" {
+		it.Status = item.StatusActive
+	}
+	if err := u.validateItem(ctx, item.Item{}, it); err != nil {
+		return item.Item{}, fmt.Errorf("invalid item: %w", err)
+	}
+
+	// Inicializa os timestamps (em segundos, a precisão das colunas DATETIME)
+	now := time.Now().UTC().Truncate(time.Second)
 	it.CreatedAt = now
 	it.UpdatedAt = now
 
-	if err := u.repo.SaveItem(&it); err != nil {
-		return fmt.Errorf("error saving item: %w", err)
+	if err := u.repo.SaveItem(ctx, &it); err != nil {
+		return item.Item{}, fmt.Errorf("error saving item: %w", err)
 	}
-	return nil
+	return it, nil
 }
 
 /*
-ListItems lista todos os itens disponíveis no repositório.
+ListItems lista os itens que atendem ao filtro informado.
+
+Regras:
+  - Exige items:read, como as demais leituras (ver item-authorization.go).
+  - O filtro é validado antes de chegar ao repositório (campo de ordenação,
+    faixas de preço/estoque, paginação); violações retornam domainerr.ValidationError.
+  - Não encontrar itens não é erro: a página volta com `items` vazio e `total` 0.
+
+Retorna:
+- Página de itens e erro (caso ocorra)
+*/
+func (u *ItemUsecase) ListItems(ctx context.Context, f item.ListFilter) (item.Page, error) {
+	if err := auth.Require(ctx, auth.PermItemsRead); err != nil {
+		return item.Page{}, fmt.Errorf("permission denied: %w", err)
+	}
+	if err := f.Validate(); err != nil {
+		return item.Page{}, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	page, err := u.repo.ListItems(ctx, f)
+	if err != nil {
+		return item.Page{}, fmt.Errorf("error in repository: %w", err)
+	}
+	return page, nil
+}
+
+/*
+ExportItems percorre os itens que atendem ao filtro, chamando fn para cada um, sem
+carregar a listagem inteira em memória (ver ItemRepositoryPort.StreamItems).
+
+O filtro é validado como em ListItems antes de qualquer chamada a fn, então um filtro
+inválido é reportado antes de a exportação começar.
+
+Retorna:
+- nil se todos os itens foram entregues a fn, ou
+- Erro encadeado com contexto (filtro inválido, falha do repositório ou erro de fn).
+*/
+func (u *ItemUsecase) ExportItems(ctx context.Context, f item.ListFilter, fn func(item.Item) error) error {
+	if err := auth.Require(ctx, auth.PermItemsRead); err != nil {
+		return fmt.Errorf("permission denied: %w", err)
+	}
+	if err := f.Validate(); err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+
+	if err := u.repo.StreamItems(ctx, f, fn); err != nil {
+		return fmt.Errorf("error exporting items: %w", err)
+	}
+	return nil
+}
 
-Regra adicional:
-- Se nenhum item for encontrado, retorna config.ErrNotFound.
+/*
+GetItem busca um único item pelo ID.
 
 Retorna:
-- Mapa de itens e erro (caso ocorra)
+  - O item e erro encadeado com contexto; domainerr.ErrNotFound é preservado
+    (via %w) para que os handlers possam responder 404.
 */
-func (u *ItemUsecase) ListItems() (item.MapRepo, error) {
-	its, err := u.repo.ListItems()
+func (u *ItemUsecase) GetItem(ctx context.Context, id int) (item.Item, error) {
+	if err := auth.Require(ctx, auth.PermItemsRead); err != nil {
+		return item.Item{}, fmt.Errorf("permission denied: %w", err)
+	}
+	it, err := u.repo.FindByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("error in repository: %w", err)
+		return item.Item{}, fmt.Errorf("error getting item: %w", err)
 	}
-	if len(its) == 0 {
-		// return nil, config.ErrNotFound
-		// Quero que retorne a lista mesmo se estiver vazia (não é erro não ter itens)
+	return it, nil
+}
 
+/*
+GetItemByCode busca um único item pelo código (SKU).
+
+Retorna:
+- O item e erro encadeado com contexto (domainerr.ErrNotFound se não existir).
+*/
+func (u *ItemUsecase) GetItemByCode(ctx context.Context, code string) (item.Item, error) {
+	if err := auth.Require(ctx, auth.PermItemsRead); err != nil {
+		return item.Item{}, fmt.Errorf("permission denied: %w", err)
 	}
-	return its, nil
+	it, err := u.repo.FindByCode(ctx, code)
+	if err != nil {
+		return item.Item{}, fmt.Errorf("error getting item by code: %w", err)
+	}
+	return it, nil
 }
 
 /*
 UpdateItem atualiza os dados de um item existente.
 
-Regra de negócio pode ser aplicada aqui antes de chamar o repositório.
+Se it.Version for maior que zero, a atualização só ocorre se o item ainda estiver
+nessa versão (domainerr.ErrPreconditionFailed caso contrário).
+
+As mesmas regras de negócio do SaveItem são aplicadas (ver validateItem). Exige items:write,
+mais items:price e stock:adjust se o preço ou o estoque mudarem (ver authorizeUpdate).
 
 Retorna:
+- O item atualizado, como ficou no repositório, ou
 - Erro encadeado com contexto, se houver falha.
 */
-func (u *ItemUsecase) UpdateItem(it item.Item) error {
+func (u *ItemUsecase) UpdateItem(ctx context.Context, it item.Item) (item.Item, error) {
+	if err := u.authorizeUpdate(ctx, &it); err != nil {
+		return item.Item{}, fmt.Errorf("error authorizing update: %w", err)
+	}
+	/*
+		Um estoque negativo só é aceito se já estiver gravado assim (ver itemViolations);
+		a gravação fica presa à versão lida, para não desfazer uma movimentação concorrente.
+	*/
+	var cur item.Item
+	if it.Stock < 0 {
+		var err error
+		if cur, err = u.repo.FindByID(ctx, it.ID); err != nil {
+			return item.Item{}, fmt.Errorf("error getting item: %w", err)
+		}
+		if it.Version == 0 {
+			it.Version = cur.Version
+		}
+	}
+	if err := u.validateItem(ctx, cur, it); err != nil {
+		return item.Item{}, fmt.Errorf("invalid item: %w", err)
+	}
+
 	// Atualiza o timestamp de modificação
-	it.UpdatedAt = time.Now()
+	it.UpdatedAt = time.Now().UTC().Truncate(time.Second)
 
-	if err := u.repo.UpdateItem(&it); err != nil {
-		return fmt.Errorf("error updating item: %w", err)
+	if err := u.repo.UpdateItem(ctx, &it); err != nil {
+		return item.Item{}, fmt.Errorf("error updating item: %w", err)
 	}
-	return nil
+	return it, nil
 }
 
 /*
-DeleteItem remove um item com base no ID.
+patchAttempts é quantas vezes PatchItem relê o item e reaplica o patch quando outra
+alteração acontece entre a leitura e a gravação (apenas se o cliente não fixou a versão).
+*/
+const patchAttempts = 3
+
+/*
+PatchItem aplica uma alteração parcial ao item com o ID informado.
 
-Regra de negócio pode ser aplicada aqui (ex: não deletar item com status X).
+Passos:
+ 1. Busca o item atual (domainerr.ErrNotFound se não existir).
+ 2. Aplica o patch sobre ele (ver item.Patch.Apply); campos desconhecidos ou de
+    tipo errado são erros de validação.
+ 3. Se nada mudou, retorna o item atual sem gravar (a versão não muda).
+ 4. Exige items:price e stock:adjust se o preço ou o estoque mudaram (items:write é
+    exigido antes do passo 1).
+ 5. Valida o item resultante com as mesmas regras de SaveItem/UpdateItem.
+ 6. Grava apenas os campos alterados, condicionado à versão lida no passo 1.
+
+Se version for maior que zero e o item não estiver mais nessa versão, retorna
+domainerr.ErrPreconditionFailed. Sem versão, uma alteração concorrente entre a leitura
+e a gravação faz o patch ser reaplicado sobre o item novo (até patchAttempts vezes),
+sem perder nenhuma das duas alterações.
 
 Retorna:
+- O item atualizado, como ficou no repositório, ou
 - Erro encadeado com contexto, se houver falha.
 */
-func (u *ItemUsecase) DeleteItem(id int) error {
-	if err := u.repo.DeleteItem(id); err != nil {
+func (u *ItemUsecase) PatchItem(ctx context.Context, id, version int, p item.Patch) (item.Item, error) {
+	if err := auth.Require(ctx, auth.PermItemsWrite); err != nil {
+		return item.Item{}, fmt.Errorf("permission denied: %w", err)
+	}
+	for attempt := 1; ; attempt++ {
+		cur, err := u.repo.FindByID(ctx, id)
+		if err != nil {
+			return item.Item{}, fmt.Errorf("error getting item: %w", err)
+		}
+		if version > 0 && cur.Version != version {
+			return item.Item{}, fmt.Errorf("error patching item: %w",
+				domainerr.PreconditionFailedf("item com ID %d está na versão %d, não %d", id, cur.Version, version))
+		}
+
+		patched, err := p.Apply(cur)
+		if err != nil {
+			return item.Item{}, fmt.Errorf("invalid patch: %w", err)
+		}
+		fields := item.ChangedFields(cur, patched)
+		if len(fields) == 0 {
+			return cur, nil
+		}
+		if err := requireChangePerms(ctx, cur, patched); err != nil {
+			return item.Item{}, fmt.Errorf("permission denied: %w", err)
+		}
+		if err := u.validateItem(ctx, cur, patched); err != nil {
+			return item.Item{}, fmt.Errorf("invalid item: %w", err)
+		}
+
+		patched.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+		err = u.repo.PatchItem(ctx, &patched, fields)
+		if errors.Is(err, domainerr.ErrPreconditionFailed) && version == 0 && attempt < patchAttempts {
+			continue // Outra alteração venceu a corrida: reaplica o patch sobre o item novo
+		}
+		if err != nil {
+			return item.Item{}, fmt.Errorf("error patching item: %w", err)
+		}
+		return patched, nil
+	}
+}
+
+/*
+DeleteItem move um item para a lixeira com base no ID (exclusão lógica).
+
+O item deixa de aparecer nas buscas e listagens, mas pode ser restaurado com
+RestoreItem até ser expurgado por PurgeDeleted; o código continua reservado.
+
+Se version for maior que zero, a exclusão só ocorre se o item ainda estiver nessa versão
+(domainerr.ErrPreconditionFailed caso contrário). Exige items:delete.
+
+Retorna:
+- Erro encadeado com contexto, se houver falha.
+*/
+func (u *ItemUsecase) DeleteItem(ctx context.Context, id, version int) error {
+	if err := auth.Require(ctx, auth.PermItemsDelete); err != nil {
+		return fmt.Errorf("permission denied: %w", err)
+	}
+	if err := u.repo.DeleteItem(ctx, id, version); err != nil {
 		return fmt.Errorf("error deleting item: %w", err)
 	}
 	return nil
 }
+
+/*
+ListTrash lista os itens da lixeira, com os mesmos filtros, ordenação e paginação de ListItems
+(ordenar por deleted_at mostra primeiro os excluídos há mais tempo).
+
+Retorna:
+- Página de itens e erro (caso ocorra)
+*/
+func (u *ItemUsecase) ListTrash(ctx context.Context, f item.ListFilter) (item.Page, error) {
+	f.Trash = true
+	return u.ListItems(ctx, f)
+}
+
+/*
+RestoreItem tira um item da lixeira.
+
+Se version for maior que zero, a restauração só ocorre se o item ainda estiver nessa versão.
+Exige items:delete, como a exclusão.
+
+Retorna:
+- O item restaurado, com a nova versão, ou
+- Erro encadeado com contexto; domainerr.ErrNotFound se o item não estiver na lixeira.
+*/
+func (u *ItemUsecase) RestoreItem(ctx context.Context, id, version int) (item.Item, error) {
+	if err := auth.Require(ctx, auth.PermItemsDelete); err != nil {
+		return item.Item{}, fmt.Errorf("permission denied: %w", err)
+	}
+	it, err := u.repo.RestoreItem(ctx, id, version)
+	if err != nil {
+		return item.Item{}, fmt.Errorf("error restoring item: %w", err)
+	}
+	return it, nil
+}
+
+/*
+PurgeDeleted remove definitivamente os itens que estão na lixeira há mais que retention
+(retention 0 esvazia a lixeira inteira). Exige items:delete.
+
+Retorna:
+- A quantidade de itens removidos, ou
+- domainerr.ValidationError se retention for negativa, ou erro encadeado com contexto.
+*/
+func (u *ItemUsecase) PurgeDeleted(ctx context.Context, retention time.Duration) (int, error) {
+	if err := auth.Require(ctx, auth.PermItemsDelete); err != nil {
+		return 0, fmt.Errorf("permission denied: %w", err)
+	}
+	if retention < 0 {
+		return 0, fmt.Errorf("invalid retention: %w", domainerr.Validation("retention", "não pode ser negativa"))
+	}
+
+	n, err := u.repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("error purging deleted items: %w", err)
+	}
+	return n, nil
+}
+
+/*
+AdjustStock aplica uma movimentação de estoque ao item.
+
+Regras de negócio:
+  - Exige stock:adjust.
+  - A movimentação é validada (tipo conhecido, sinal do delta coerente com o tipo).
+  - O repositório aplica o delta atomicamente e impede estoque negativo,
+    a menos que allowNegative seja true.
+  - Com um principal autenticado no contexto (auth.FromContext), o actor registrado é ele
+    (ex: "api_key:3"), e não o informado pelo cliente, para que o histórico seja confiável.
+
+Retorna:
+- A movimentação registrada (com ID e saldo resultante) e erro encadeado com contexto.
+*/
+func (u *ItemUsecase) AdjustStock(ctx context.Context, itemID int, m item.StockMovement, allowNegative bool) (item.StockMovement, error) {
+	if err := auth.Require(ctx, auth.PermStockAdjust); err != nil {
+		return item.StockMovement{}, fmt.Errorf("permission denied: %w", err)
+	}
+
+	m.ItemID = itemID
+	m.CreatedAt = time.Now().UTC().Truncate(time.Second) // Mesma precisão das colunas DATETIME
+	if p, ok := auth.FromContext(ctx); ok {
+		m.Actor = p.String()
+	}
+
+	if err := m.Validate(); err != nil {
+		return item.StockMovement{}, fmt.Errorf("invalid stock movement: %w", err)
+	}
+
+	if _, err := u.repo.AdjustStock(ctx, &m, allowNegative); err != nil {
+		return item.StockMovement{}, fmt.Errorf("error adjusting stock: %w", err)
+	}
+	return m, nil
+}
+
+/*
+ListMovements lista o histórico de estoque de um item (mais recente primeiro).
+
+Retorna:
+- Página de movimentações e erro encadeado com contexto (domainerr.ErrNotFound se o item não existir).
+*/
+func (u *ItemUsecase) ListMovements(ctx context.Context, itemID int, f item.ListFilter) (item.MovementPage, error) {
+	if err := auth.Require(ctx, auth.PermItemsRead); err != nil {
+		return item.MovementPage{}, fmt.Errorf("permission denied: %w", err)
+	}
+	if err := f.Validate(); err != nil {
+		return item.MovementPage{}, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	page, err := u.repo.ListMovements(ctx, itemID, f)
+	if err != nil {
+		return item.MovementPage{}, fmt.Errorf("error listing stock movements: %w", err)
+	}
+	return page, nil
+}
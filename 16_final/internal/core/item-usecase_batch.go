@@ -0,0 +1,260 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api/internal/core/auth"
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+/*
+SaveItems salva vários itens novos de uma vez (ex: carga do catálogo de um fornecedor).
+
+Cada linha segue as regras de SaveItem (status padrão, ID gerado pelo repositório),
+exceto a unicidade do código: em vez de uma busca por item, ela é garantida pelo
+repositório, e um código repetido (no lote ou no banco) é domainerr.ErrAlreadyExists na linha.
+
+Em atomic, se qualquer linha falhar nenhuma é gravada, e as linhas válidas recebem
+item.ErrBatchAborted; no modo best-effort, as linhas válidas são gravadas mesmo que outras falhem.
+
+A permissão items:write vale para o lote inteiro; items:price e stock:adjust são exigidas
+por linha, como em SaveItem, e a falta delas é domainerr.PermissionError na linha.
+
+Retorna:
+  - Um resultado por linha, na ordem recebida, com o item gravado ou o erro da linha, ou
+  - Erro encadeado com contexto se o lote for inválido (vazio ou acima de item.MaxBatchSize)
+    ou se o repositório falhar como um todo (nesse caso nada é gravado).
+*/
+func (u *ItemUsecase) SaveItems(ctx context.Context, its []item.Item, atomic bool) ([]item.BatchResult, error) {
+	if err := auth.Require(ctx, auth.PermItemsWrite); err != nil {
+		return nil, fmt.Errorf("permission denied: %w", err)
+	}
+	if err := validateBatchSize(len(its)); err != nil {
+		return nil, fmt.Errorf("invalid batch: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	results := make([]item.BatchResult, len(its))
+	pending := make([]*item.Item, 0, len(its))
+	rows := make([]int, 0, len(its)) // rows[k] é a linha de pending[k]
+	for i, it := range its {
+		it.ID = 0
+		if it.Status == "" {
+			it.Status = item.StatusActive
+		}
+		it.CreatedAt, it.UpdatedAt = now, now
+		results[i] = item.BatchResult{Index: i, Item: it}
+
+		if err := requireChangePerms(ctx, item.Item{}, it); err != nil {
+			results[i].Err = fmt.Errorf("permission denied: %w", err)
+			continue
+		}
+		if err := validateRules(item.Item{}, it); err != nil {
+			results[i].Err = fmt.Errorf("invalid item: %w", err)
+			continue
+		}
+		pending = append(pending, &results[i].Item)
+		rows = append(rows, i)
+	}
+	if atomic && batchFailed(results) {
+		return finishBatch(results, atomic), nil
+	}
+
+	errs, err := u.repo.SaveItems(ctx, pending, atomic)
+	if err != nil {
+		return nil, fmt.Errorf("error saving items: %w", err)
+	}
+	for k, i := range rows {
+		if errs[k] != nil {
+			results[i].Err = fmt.Errorf("error saving item: %w", errs[k])
+		}
+	}
+	return finishBatch(results, atomic), nil
+}
+
+/*
+PatchItems aplica um merge patch (ver PatchItem) a cada item do lote.
+
+Passos:
+ 1. Busca todos os itens do lote em uma única consulta (item inexistente é domainerr.ErrNotFound na linha).
+ 2. Aplica cada patch e valida o item resultante; um `version` no patch diferente do
+    atual é domainerr.ErrPreconditionFailed na linha. Linhas sem alteração não são gravadas.
+ 3. Grava os campos alterados condicionados à versão lida no passo 1 (sem reaplicar o
+    patch em caso de corrida, ao contrário de PatchItem).
+
+O código novo é validado como em SaveItems. Um mesmo ID não pode aparecer duas vezes no lote.
+As permissões, a semântica de atomic e o retorno são os de SaveItems.
+*/
+func (u *ItemUsecase) PatchItems(ctx context.Context, patches []item.BatchPatch, atomic bool) ([]item.BatchResult, error) {
+	if err := auth.Require(ctx, auth.PermItemsWrite); err != nil {
+		return nil, fmt.Errorf("permission denied: %w", err)
+	}
+	if err := validateBatchSize(len(patches)); err != nil {
+		return nil, fmt.Errorf("invalid batch: %w", err)
+	}
+
+	ids := make([]int, len(patches))
+	for i, p := range patches {
+		ids[i] = p.ID
+	}
+	found, err := u.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error getting items: %w", err)
+	}
+	current := make(map[int]item.Item, len(found))
+	for _, it := range found {
+		current[it.ID] = it
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	results := make([]item.BatchResult, len(patches))
+	changes := make([]item.ItemChange, 0, len(patches))
+	rows := make([]int, 0, len(patches)) // rows[k] é a linha de changes[k]
+	seen := make(map[int]bool, len(patches))
+	for i, p := range patches {
+		results[i].Index = i
+		if err := checkBatchID(p.ID, seen); err != nil {
+			results[i].Err = fmt.Errorf("invalid batch row: %w", err)
+			continue
+		}
+
+		cur, ok := current[p.ID]
+		if !ok {
+			results[i].Err = fmt.Errorf("error getting item: %w", domainerr.NotFoundf("item com ID %d não existe", p.ID))
+			continue
+		}
+		patched, err := p.Patch.Apply(cur)
+		if err != nil {
+			results[i].Err = fmt.Errorf("invalid patch: %w", err)
+			continue
+		}
+		fields := item.ChangedFields(cur, patched)
+		if len(fields) == 0 {
+			results[i].Item = cur
+			continue
+		}
+		if err := requireChangePerms(ctx, cur, patched); err != nil {
+			results[i].Err = fmt.Errorf("permission denied: %w", err)
+			continue
+		}
+		if err := validateRules(cur, patched); err != nil {
+			results[i].Err = fmt.Errorf("invalid item: %w", err)
+			continue
+		}
+
+		patched.UpdatedAt = now
+		results[i].Item = patched
+		changes = append(changes, item.ItemChange{Item: &results[i].Item, Fields: fields})
+		rows = append(rows, i)
+	}
+	if atomic && batchFailed(results) {
+		return finishBatch(results, atomic), nil
+	}
+
+	errs, err := u.repo.PatchItems(ctx, changes, atomic)
+	if err != nil {
+		return nil, fmt.Errorf("error patching items: %w", err)
+	}
+	for k, i := range rows {
+		if errs[k] != nil {
+			results[i].Err = fmt.Errorf("error patching item: %w", errs[k])
+		}
+	}
+	return finishBatch(results, atomic), nil
+}
+
+/*
+DeleteItems move vários itens para a lixeira (ver DeleteItem), cada um com sua versão esperada.
+
+Exige items:delete para o lote inteiro. Um mesmo ID não pode aparecer duas vezes no lote.
+A semântica de atomic e o retorno são os de SaveItems; o item de cada resultado traz apenas o ID.
+*/
+func (u *ItemUsecase) DeleteItems(ctx context.Context, refs []item.ItemRef, atomic bool) ([]item.BatchResult, error) {
+	if err := auth.Require(ctx, auth.PermItemsDelete); err != nil {
+		return nil, fmt.Errorf("permission denied: %w", err)
+	}
+	if err := validateBatchSize(len(refs)); err != nil {
+		return nil, fmt.Errorf("invalid batch: %w", err)
+	}
+
+	results := make([]item.BatchResult, len(refs))
+	pending := make([]item.ItemRef, 0, len(refs))
+	rows := make([]int, 0, len(refs)) // rows[k] é a linha de pending[k]
+	seen := make(map[int]bool, len(refs))
+	for i, ref := range refs {
+		results[i] = item.BatchResult{Index: i, Item: item.Item{ID: ref.ID}}
+		if err := checkBatchID(ref.ID, seen); err != nil {
+			results[i].Err = fmt.Errorf("invalid batch row: %w", err)
+			continue
+		}
+		pending = append(pending, ref)
+		rows = append(rows, i)
+	}
+	if atomic && batchFailed(results) {
+		return finishBatch(results, atomic), nil
+	}
+
+	errs, err := u.repo.DeleteItems(ctx, pending, atomic)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting items: %w", err)
+	}
+	for k, i := range rows {
+		if errs[k] != nil {
+			results[i].Err = fmt.Errorf("error deleting item: %w", errs[k])
+		}
+	}
+	return finishBatch(results, atomic), nil
+}
+
+// validateBatchSize garante que o lote não está vazio nem passa de item.MaxBatchSize linhas.
+func validateBatchSize(n int) error {
+	switch {
+	case n == 0:
+		return domainerr.Validation("items", "o lote está vazio")
+	case n > item.MaxBatchSize:
+		return domainerr.Validation("items", fmt.Sprintf("o lote tem %d linhas; o máximo é %d", n, item.MaxBatchSize))
+	}
+	return nil
+}
+
+// checkBatchID valida o ID de uma linha de PATCH/DELETE em lote e o registra em seen.
+func checkBatchID(id int, seen map[int]bool) error {
+	if id <= 0 {
+		return domainerr.Validation("id", "é obrigatório")
+	}
+	if seen[id] {
+		return domainerr.Validation("id", fmt.Sprintf("o item %d aparece mais de uma vez no lote", id))
+	}
+	seen[id] = true
+	return nil
+}
+
+// batchFailed indica se alguma linha do lote tem erro.
+func batchFailed(results []item.BatchResult) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+finishBatch ajusta os resultados depois da gravação: as linhas com erro não trazem item
+e, em um lote atômico que falhou, as demais linhas recebem item.ErrBatchAborted.
+*/
+func finishBatch(results []item.BatchResult, atomic bool) []item.BatchResult {
+	aborted := atomic && batchFailed(results)
+	for i := range results {
+		if results[i].Err == nil && aborted {
+			results[i].Err = item.ErrBatchAborted
+		}
+		if results[i].Err != nil {
+			results[i].Item = item.Item{}
+		}
+	}
+	return results
+}
@@ -1,6 +1,11 @@
 package core
 
-import "api/internal/core/item"
+import (
+	"context"
+	"time"
+
+	"api/internal/core/item"
+)
 
 /*
 ItemUsecasePort define a interface da camada de aplicação para a entidade Item.
@@ -14,17 +19,60 @@ Vantagens:
 - Permite usar diferentes implementações do usecase (ex: para testes);
 - Permite que os handlers dependam da abstração, não da implementação;
 - Mantém a aplicação desacoplada, coesa e testável.
+
+O context.Context recebido por cada método é repassado até o repositório,
+carregando o prazo da requisição e o cancelamento quando o cliente desiste.
 */
 type ItemUsecasePort interface {
 	// SaveItem salva um novo item, validando e repassando para o repositório.
-	SaveItem(item.Item) error
+	// Retorna o item como foi persistido, com o ID gerado pelo repositório.
+	SaveItem(ctx context.Context, it item.Item) (item.Item, error)
+
+	// ListItems retorna os itens que atendem ao filtro, ordenados e paginados.
+	ListItems(ctx context.Context, f item.ListFilter) (item.Page, error)
 
-	// ListItems retorna todos os itens cadastrados.
-	ListItems() (item.MapRepo, error)
+	// ExportItems chama fn para cada item que atende ao filtro, na ordem pedida,
+	// lendo do repositório aos poucos (para exportações do catálogo inteiro).
+	ExportItems(ctx context.Context, f item.ListFilter, fn func(item.Item) error) error
+
+	// GetItem retorna um único item pelo ID (domainerr.ErrNotFound se não existir).
+	GetItem(ctx context.Context, id int) (item.Item, error)
+
+	// GetItemByCode retorna um único item pelo código/SKU (domainerr.ErrNotFound se não existir).
+	GetItemByCode(ctx context.Context, code string) (item.Item, error)
 
 	// UpdateItem atualiza os dados de um item existente.
-	UpdateItem(item.Item) error
+	// Se Item.Version for informado, a atualização só ocorre se o item ainda estiver nessa versão.
+	// Retorna o item como ficou no repositório (nova versão, created_at original).
+	UpdateItem(ctx context.Context, it item.Item) (item.Item, error)
+
+	// PatchItem aplica uma alteração parcial (JSON Merge Patch ou JSON Patch) ao item atual,
+	// validando o resultado e gravando apenas os campos alterados.
+	// Se version for maior que zero, o patch só é aplicado se o item ainda estiver nessa versão.
+	PatchItem(ctx context.Context, id, version int, p item.Patch) (item.Item, error)
+
+	// DeleteItem move um item para a lixeira com base no seu ID e na versão esperada (0 = qualquer versão).
+	DeleteItem(ctx context.Context, id, version int) error
+
+	// ListTrash retorna os itens que estão na lixeira, com os mesmos filtros de ListItems.
+	ListTrash(ctx context.Context, f item.ListFilter) (item.Page, error)
+
+	// RestoreItem tira um item da lixeira e o retorna (versão esperada 0 = qualquer versão).
+	RestoreItem(ctx context.Context, id, version int) (item.Item, error)
+
+	// PurgeDeleted remove definitivamente os itens que estão na lixeira há mais que retention.
+	// Retorna quantos itens foram removidos.
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int, error)
+
+	// SaveItems, PatchItems e DeleteItems aplicam a operação a um lote de itens, em uma única
+	// transação (atomic: tudo ou nada) ou linha a linha (best-effort), com um resultado por linha.
+	SaveItems(ctx context.Context, its []item.Item, atomic bool) ([]item.BatchResult, error)
+	PatchItems(ctx context.Context, patches []item.BatchPatch, atomic bool) ([]item.BatchResult, error)
+	DeleteItems(ctx context.Context, refs []item.ItemRef, atomic bool) ([]item.BatchResult, error)
+
+	// AdjustStock aplica uma movimentação de estoque ao item e a registra no histórico.
+	AdjustStock(ctx context.Context, itemID int, m item.StockMovement, allowNegative bool) (item.StockMovement, error)
 
-	// DeleteItem remove um item com base no seu ID.
-	DeleteItem(int) error
+	// ListMovements retorna o histórico de estoque do item, paginado.
+	ListMovements(ctx context.Context, itemID int, f item.ListFilter) (item.MovementPage, error)
 }
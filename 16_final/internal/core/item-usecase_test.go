@@ -0,0 +1,254 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+// TestUsecaseHonorsContext garante que um contexto cancelado ou expirado interrompe
+// a operação antes de qualquer efeito no repositório.
+func TestUsecaseHonorsContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"cliente desconectou", canceled, context.Canceled},
+		{"prazo esgotado", expired, context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewItemUsecase(item.NewMapRepository())
+			it := item.Item{Code: "ITEM-001", Title: "Caneta", Stock: 10}
+
+			if _, err := u.SaveItem(tt.ctx, it); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SaveItem: esperado %v, obtido %v", tt.wantErr, err)
+			}
+			if _, err := u.GetItemByCode(context.Background(), it.Code); !errors.Is(err, domainerr.ErrNotFound) {
+				t.Fatalf("o item não deveria ter sido salvo: %v", err)
+			}
+
+			saved, err := u.SaveItem(context.Background(), it)
+			if err != nil {
+				t.Fatalf("SaveItem: %v", err)
+			}
+			if _, err := u.ListItems(tt.ctx, item.ListFilter{}); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListItems: esperado %v, obtido %v", tt.wantErr, err)
+			}
+			m := item.StockMovement{Type: item.MovementSale, Delta: -1}
+			if _, err := u.AdjustStock(tt.ctx, saved.ID, m, false); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AdjustStock: esperado %v, obtido %v", tt.wantErr, err)
+			}
+			if got, _ := u.GetItem(context.Background(), saved.ID); got.Stock != 10 {
+				t.Fatalf("o estoque não deveria ter mudado: %d", got.Stock)
+			}
+		})
+	}
+}
+
+// TestSaveItemReturnsStoredItem garante que o ID vem do repositório, mesmo se o chamador informar outro.
+func TestSaveItemReturnsStoredItem(t *testing.T) {
+	ctx := context.Background()
+	u := NewItemUsecase(item.NewMapRepository())
+
+	first, err := u.SaveItem(ctx, item.Item{ID: 50, Code: "ITEM-001", Title: "Caneta"})
+	if err != nil {
+		t.Fatalf("SaveItem: %v", err)
+	}
+	second, err := u.SaveItem(ctx, item.Item{ID: 50, Code: "ITEM-002", Title: "Lápis"})
+	if err != nil {
+		t.Fatalf("SaveItem: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("IDs gerados = %d e %d, esperado 1 e 2", first.ID, second.ID)
+	}
+	if first.Version != 1 || first.Status != item.StatusActive || first.CreatedAt.IsZero() {
+		t.Fatalf("item devolvido incompleto: %+v", first)
+	}
+
+	stored, err := u.GetItem(ctx, first.ID)
+	if err != nil || stored != first {
+		t.Fatalf("GetItem = %+v, %v; esperado o item devolvido por SaveItem %+v", stored, err, first)
+	}
+
+	first.Title = "Caneta azul"
+	updated, err := u.UpdateItem(ctx, first)
+	if err != nil {
+		t.Fatalf("UpdateItem: %v", err)
+	}
+	if updated.Version != 2 || updated.Title != "Caneta azul" || !updated.CreatedAt.Equal(first.CreatedAt) {
+		t.Fatalf("UpdateItem devolveu %+v", updated)
+	}
+}
+
+// TestPurgeDeleted garante que só os itens há mais tempo que a retenção na lixeira são removidos.
+func TestPurgeDeleted(t *testing.T) {
+	ctx := context.Background()
+	u := NewItemUsecase(item.NewMapRepository())
+
+	active, _ := u.SaveItem(ctx, item.Item{Code: "ITEM-001", Title: "Caneta"})
+	deleted, _ := u.SaveItem(ctx, item.Item{Code: "ITEM-002", Title: "Lápis"})
+	if err := u.DeleteItem(ctx, deleted.ID, 0); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+
+	if _, err := u.PurgeDeleted(ctx, -time.Hour); !errors.Is(err, domainerr.ErrValidation) {
+		t.Fatalf("retenção negativa: esperado ErrValidation, obtido %v", err)
+	}
+	if n, err := u.PurgeDeleted(ctx, time.Hour); err != nil || n != 0 {
+		t.Fatalf("PurgeDeleted(1h) = %d, %v; esperado nada removido", n, err)
+	}
+	if n, err := u.PurgeDeleted(ctx, 0); err != nil || n != 1 {
+		t.Fatalf("PurgeDeleted(0) = %d, %v; esperado 1 removido", n, err)
+	}
+
+	if _, err := u.RestoreItem(ctx, deleted.ID, 0); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("item expurgado não deveria ser restaurável: %v", err)
+	}
+	if _, err := u.GetItem(ctx, active.ID); err != nil {
+		t.Fatalf("item ativo não deveria ser afetado: %v", err)
+	}
+}
+
+// TestEditItemWithNegativeStock garante que um item com saldo negativo (AdjustStock com
+// allowNegative) continua editável, desde que a escrita não altere o estoque.
+func TestEditItemWithNegativeStock(t *testing.T) {
+	ctx := context.Background()
+	u := NewItemUsecase(item.NewMapRepository())
+
+	saved, err := u.SaveItem(ctx, item.Item{Code: "ITEM-001", Title: "Caneta", Stock: 1})
+	if err != nil {
+		t.Fatalf("SaveItem: %v", err)
+	}
+	negative, err := u.AdjustStock(ctx, saved.ID, item.StockMovement{Type: item.MovementSale, Delta: -3}, true)
+	if err != nil || negative.StockAfter != -2 {
+		t.Fatalf("AdjustStock com allowNegative = %+v, %v", negative, err)
+	}
+
+	merge := func(body string) item.Patch { return item.Patch{Type: item.PatchMerge, Body: []byte(body)} }
+	patched, err := u.PatchItem(ctx, saved.ID, 0, merge(`{"title": "Caneta azul"}`))
+	if err != nil {
+		t.Fatalf("PatchItem no título de item com estoque negativo: %v", err)
+	}
+	if patched.Title != "Caneta azul" || patched.Stock != -2 {
+		t.Fatalf("PatchItem = %+v", patched)
+	}
+
+	patched.Description = "Tinta azul"
+	if _, err := u.UpdateItem(ctx, patched); err != nil {
+		t.Fatalf("UpdateItem mantendo o estoque negativo: %v", err)
+	}
+
+	// Levar o estoque a (outro) valor negativo por escrita direta continua proibido
+	var ve *domainerr.ValidationError
+	if _, err := u.PatchItem(ctx, saved.ID, 0, merge(`{"stock": -5}`)); !errors.As(err, &ve) || ve.Fields[0].Field != "stock" {
+		t.Fatalf("PatchItem com estoque -5: esperado erro de validação em stock, obtido %v", err)
+	}
+	if _, err := u.SaveItem(ctx, item.Item{Code: "ITEM-002", Title: "Lápis", Stock: -1}); !errors.Is(err, domainerr.ErrValidation) {
+		t.Fatalf("SaveItem com estoque negativo: esperado ErrValidation, obtido %v", err)
+	}
+	if fixed, err := u.PatchItem(ctx, saved.ID, 0, merge(`{"stock": 0}`)); err != nil || fixed.Stock != 0 {
+		t.Fatalf("PatchItem zerando o estoque = %+v, %v", fixed, err)
+	}
+}
+
+// blindCodeRepo esconde os códigos em FindByCode, como se outro item fosse gravado logo depois da conferência.
+type blindCodeRepo struct {
+	item.ItemRepositoryPort
+}
+
+func (blindCodeRepo) FindByCode(context.Context, string) (item.Item, error) {
+	return item.Item{}, domainerr.NotFoundf("nenhum item com o código")
+}
+
+// TestDuplicateCodeAlreadyExists garante o mesmo erro para o código duplicado, venha ele
+// da conferência do caso de uso ou da chave única do repositório.
+func TestDuplicateCodeAlreadyExists(t *testing.T) {
+	ctx := context.Background()
+	repos := map[string]item.ItemRepositoryPort{
+		"conferência do caso de uso": item.NewMapRepository(),
+		"chave única do repositório": blindCodeRepo{item.NewMapRepository()},
+	}
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			u := NewItemUsecase(repo)
+			if _, err := u.SaveItem(ctx, item.Item{Code: "ITEM-001", Title: "Caneta"}); err != nil {
+				t.Fatalf("SaveItem: %v", err)
+			}
+			other, err := u.SaveItem(ctx, item.Item{Code: "ITEM-002", Title: "Lápis"})
+			if err != nil {
+				t.Fatalf("SaveItem: %v", err)
+			}
+
+			if _, err := u.SaveItem(ctx, item.Item{Code: "ITEM-001", Title: "Borracha"}); !errors.Is(err, domainerr.ErrAlreadyExists) {
+				t.Fatalf("SaveItem com código em uso: esperado ErrAlreadyExists, obtido %v", err)
+			}
+			other.Code = "ITEM-001"
+			if _, err := u.UpdateItem(ctx, other); !errors.Is(err, domainerr.ErrAlreadyExists) {
+				t.Fatalf("UpdateItem com código em uso: esperado ErrAlreadyExists, obtido %v", err)
+			}
+		})
+	}
+}
+
+// TestPriceFitsColumn garante que o preço é recusado com erro de campo quando não cabe em DECIMAL(10,2).
+func TestPriceFitsColumn(t *testing.T) {
+	tests := []struct {
+		price   float64
+		wantErr bool
+	}{
+		{0, false},
+		{2.5, false},
+		{19.99, false},
+		{99999999.99, false},
+		{1e8, true},
+		{1.005, true},
+		{-1, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.price), func(t *testing.T) {
+			u := NewItemUsecase(item.NewMapRepository())
+			_, err := u.SaveItem(context.Background(), item.Item{Code: "ITEM-001", Title: "Caneta", Price: tt.price})
+			var ve *domainerr.ValidationError
+			if tt.wantErr != errors.As(err, &ve) || (!tt.wantErr && err != nil) {
+				t.Fatalf("SaveItem com preço %v: erro %v", tt.price, err)
+			}
+			if tt.wantErr && ve.Fields[0].Field != "price" {
+				t.Fatalf("SaveItem com preço %v: campo %q, esperado price", tt.price, ve.Fields[0].Field)
+			}
+		})
+	}
+}
+
+// TestAdjustStockTimestamps garante que a movimentação usa o mesmo relógio (UTC, em segundos) das demais gravações.
+func TestAdjustStockTimestamps(t *testing.T) {
+	ctx := context.Background()
+	u := NewItemUsecase(item.NewMapRepository())
+	saved, err := u.SaveItem(ctx, item.Item{Code: "ITEM-001", Title: "Caneta", Stock: 5})
+	if err != nil {
+		t.Fatalf("SaveItem: %v", err)
+	}
+
+	m, err := u.AdjustStock(ctx, saved.ID, item.StockMovement{Type: item.MovementSale, Delta: -1}, false)
+	if err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+	if m.CreatedAt.Location() != time.UTC || !m.CreatedAt.Equal(m.CreatedAt.Truncate(time.Second)) {
+		t.Fatalf("created_at da movimentação = %v; esperado UTC, em segundos", m.CreatedAt)
+	}
+	if got, _ := u.GetItem(ctx, saved.ID); got.UpdatedAt != m.CreatedAt {
+		t.Fatalf("updated_at do item = %v; esperado o instante da movimentação %v", got.UpdatedAt, m.CreatedAt)
+	}
+}
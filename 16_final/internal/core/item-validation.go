@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+/*
+skuPattern define o formato aceito para o código (SKU) de um item:
+de 3 a 64 caracteres, começando com letra ou número, usando apenas
+letras maiúsculas, números, hífen ou sublinhado (ex: ITEM-001, CAN_AZ_10).
+*/
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{2,63}$`)
+
+// Tamanhos máximos, iguais aos das colunas VARCHAR(255) da tabela `items`.
+const (
+	maxTitleLength = 255
+	maxCodeLength  = 64
+)
+
+// maxPrice é o primeiro valor que não cabe na coluna DECIMAL(10,2) de `price`.
+const maxPrice = 1e8
+
+/*
+validateItem aplica as regras de negócio de um item antes de persisti-lo.
+
+cur é o item como está gravado (o item vazio em uma criação) e next é o item a gravar.
+
+Regras:
+- as de itemViolations (formato do código, título, preço, estoque e status);
+- code: único — nenhum outro item pode ter o mesmo código.
+
+As violações de itemViolations são acumuladas e devolvidas juntas em um
+domainerr.ValidationError. Só com o item válido o código é conferido: se já estiver
+em uso, o erro é domainerr.ErrAlreadyExists, o mesmo que o repositório devolve quando
+a duplicidade só aparece na gravação. Erros de acesso ao repositório são retornados como estão.
+*/
+func (u *ItemUsecase) validateItem(ctx context.Context, cur, next item.Item) error {
+	if fields := itemViolations(cur, next); len(fields) > 0 {
+		return &domainerr.ValidationError{Fields: fields}
+	}
+	return u.checkUniqueCode(ctx, next)
+}
+
+/*
+validateRules aplica apenas as regras que não dependem do repositório (itemViolations).
+
+É usada nos lotes, em que a unicidade do código é garantida pelo próprio repositório
+(domainerr.ErrAlreadyExists por linha) em vez de uma busca por item.
+*/
+func validateRules(cur, next item.Item) error {
+	if fields := itemViolations(cur, next); len(fields) > 0 {
+		return &domainerr.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+/*
+itemViolations verifica as regras de it que não dependem do repositório.
+
+Regras:
+  - code: obrigatório e no formato de SKU (skuPattern);
+  - title: obrigatório, com no máximo 255 caracteres;
+  - price: não pode ser negativo, deve ser menor que maxPrice e ter no máximo 2 casas
+    decimais, como a coluna DECIMAL(10,2), para que todos os repositórios gravem o mesmo valor;
+  - stock: não pode passar a ser negativo. Um saldo negativo já gravado (deixado por
+    AdjustStock com allowNegative) é aceito enquanto a escrita não alterar o estoque,
+    para que o item continue editável; cur é o item gravado (vazio em uma criação);
+  - status: deve ser um dos valores de item.Statuses.
+
+Retorna todas as violações encontradas, na ordem dos campos.
+*/
+func itemViolations(cur, it item.Item) []domainerr.FieldError {
+	var fields []domainerr.FieldError
+	add := func(field, message string) {
+		fields = append(fields, domainerr.FieldError{Field: field, Message: message})
+	}
+
+	switch code := it.Code; {
+	case strings.TrimSpace(code) == "":
+		add("code", "é obrigatório")
+	case len(code) > maxCodeLength || !skuPattern.MatchString(code):
+		add("code", "deve ter de 3 a 64 caracteres: letras maiúsculas, números, '-' ou '_'")
+	}
+
+	if strings.TrimSpace(it.Title) == "" {
+		add("title", "é obrigatório")
+	} else if len(it.Title) > maxTitleLength {
+		add("title", fmt.Sprintf("deve ter no máximo %d caracteres", maxTitleLength))
+	}
+
+	switch p := it.Price; {
+	case p < 0:
+		add("price", "não pode ser negativo")
+	case p >= maxPrice:
+		add("price", fmt.Sprintf("deve ser menor que %.0f", float64(maxPrice)))
+	case math.Abs(p*100-math.Round(p*100)) > 1e-6:
+		add("price", "deve ter no máximo 2 casas decimais")
+	}
+	if it.Stock < 0 && it.Stock != cur.Stock {
+		add("stock", "não pode ser negativo")
+	}
+
+	if !slices.Contains(item.Statuses, it.Status) {
+		add("status", fmt.Sprintf("deve ser um de: %s", strings.Join(item.Statuses, ", ")))
+	}
+	return fields
+}
+
+/*
+checkUniqueCode garante que nenhum outro item (com ID diferente) use o mesmo código.
+
+Retorna domainerr.ErrAlreadyExists se o código já estiver em uso,
+ou o erro do repositório caso a consulta falhe.
+*/
+func (u *ItemUsecase) checkUniqueCode(ctx context.Context, it item.Item) error {
+	other, err := u.repo.FindByCode(ctx, it.Code)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error checking code uniqueness: %w", err)
+	}
+	if other.ID != it.ID {
+		return domainerr.AlreadyExistsf("o código %q já está em uso pelo item %d", it.Code, other.ID)
+	}
+	return nil
+}
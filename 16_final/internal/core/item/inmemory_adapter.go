@@ -1,17 +1,35 @@
 package item
 
 import (
-	"fmt"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"api/internal/core/domainerr" // Erros de domínio (NotFound, AlreadyExists, Validation, ...)
 )
 
 /*
 MapRepository é uma implementação do repositório de itens em memória.
 
-- Utiliza um `map[int]Item` para armazenar os itens durante a execução do programa.
-- É útil para testes locais ou execução sem banco de dados.
+  - Utiliza um `map[int]Item` para armazenar os itens durante a execução do programa.
+  - É útil para testes locais ou execução sem banco de dados.
+  - Como as operações são instantâneas, o contexto é conferido apenas na entrada de
+    cada método: uma chamada com contexto já cancelado ou expirado não tem efeito.
+  - É seguro para uso concorrente (o Gin atende cada requisição em uma goroutine):
+    leituras usam mu.RLock e escritas mu.Lock.
+  - Nunca expõe o estado interno: os itens são guardados e devolvidos por valor,
+    as listagens montam slices novos e o único campo de referência (DeletedAt)
+    é copiado antes de sair do repositório.
+  - A exclusão é lógica, como nos bancos: o item fica no mapa com DeletedAt preenchido.
 */
 type MapRepository struct {
-	items MapRepo // MapRepo é um alias para map[int]Item
+	mu             sync.RWMutex    // Protege items, movements, lastID e lastMovementID
+	items          MapRepo         // MapRepo é um alias para map[int]Item
+	movements      []StockMovement // Histórico de estoque, em ordem de inserção
+	lastID         int             // Maior ID já usado, imitando o AUTO_INCREMENT do MySQL
+	lastMovementID int             // Maior ID de movimentação já usado (não recua com o expurgo)
 }
 
 /*
@@ -30,70 +48,467 @@ func NewMapRepository() ItemRepositoryPort {
 /*
 SaveItem salva um novo item no repositório.
 
-Regras:
-- O ID não pode ser zero.
-- Não pode existir outro item com o mesmo ID.
+O ID é gerado pelo repositório (o próximo depois de lastID, imitando o AUTO_INCREMENT
+do MySQL) e gravado em it.ID; o ID recebido é ignorado. A versão começa em 1.
 
-Retorna erro caso a operação viole alguma dessas regras.
+Retorna:
+  - domainerr.ErrAlreadyExists se outro item (mesmo na lixeira) já usar o código,
+    o equivalente à coluna `code UNIQUE` do banco
+  - O erro do contexto, se ele já tiver sido cancelado.
 */
-func (r *MapRepository) SaveItem(it *Item) error {
-	if it.ID == 0 {
-		return fmt.Errorf("ID do item não pode ser 0")
+func (r *MapRepository) SaveItem(ctx context.Context, it *Item) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
-	if _, exists := r.items[it.ID]; exists {
-		return fmt.Errorf("já existe um item com o ID %d", it.ID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saveItem(it)
+}
+
+// saveItem é o SaveItem com r.mu já travado.
+func (r *MapRepository) saveItem(it *Item) error {
+	if r.codeTaken(it.Code, 0) {
+		return errDuplicateCode(it.Code)
 	}
+	r.lastID++
+	it.ID = r.lastID
+	it.Version = 1 // Todo item nasce na versão 1
+	it.DeletedAt = nil
 	r.items[it.ID] = *it
 	return nil
 }
 
 /*
-ListItems retorna todos os itens armazenados.
+ListItems emula a listagem do MySQL sobre o mapa em memória.
 
-Retorna:
-- O mapa de itens.
-- `nil` como erro, pois essa operação não deve falhar nesta implementação.
+Passos:
+1. Seleciona os itens que atendem ao filtro (equivalente ao WHERE).
+2. Ordena segundo o campo pedido (equivalente ao ORDER BY).
+3. Recorta a página (equivalente ao LIMIT/OFFSET) e calcula o total.
+
+Só retorna erro se o contexto já estiver cancelado.
 */
-func (r *MapRepository) ListItems() (MapRepo, error) {
-	return r.items, nil
+func (r *MapRepository) ListItems(ctx context.Context, f ListFilter) (Page, error) {
+	if err := ctx.Err(); err != nil {
+		return Page{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	its := make([]Item, 0, len(r.items))
+	for _, it := range r.items {
+		if f.Match(it) {
+			if it.DeletedAt != nil {
+				deletedAt := *it.DeletedAt
+				it.DeletedAt = &deletedAt
+			}
+			its = append(its, it)
+		}
+	}
+	f.Sort(its)
+	return f.Paginate(its), nil
+}
+
+/*
+StreamItems chama fn para cada item da listagem (ver ListItems), um por vez.
+
+Os itens são copiados do mapa antes do primeiro fn, que roda sem a trava: um fn
+lento (ex: escrita na rede) não bloqueia as gravações concorrentes.
+*/
+func (r *MapRepository) StreamItems(ctx context.Context, f ListFilter, fn func(Item) error) error {
+	page, err := r.ListItems(ctx, f)
+	if err != nil {
+		return err
+	}
+	for _, it := range page.Items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(it); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+FindByID busca um item pelo ID no mapa.
+
+Retorna domainerr.ErrNotFound caso o item não exista ou esteja na lixeira.
+*/
+func (r *MapRepository) FindByID(ctx context.Context, id int) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	it, exists := r.items[id]
+	if !exists || it.Deleted() {
+		return Item{}, domainerr.NotFoundf("item com ID %d não existe", id)
+	}
+	return it, nil
+}
+
+// FindByIDs busca os itens ativos com os IDs informados; IDs inexistentes ou na lixeira são omitidos.
+func (r *MapRepository) FindByIDs(ctx context.Context, ids []int) ([]Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	its := make([]Item, 0, len(ids))
+	for _, id := range ids {
+		if it, exists := r.items[id]; exists && !it.Deleted() {
+			its = append(its, it)
+		}
+	}
+	return its, nil
+}
+
+/*
+FindByCode busca um item pelo código (SKU).
+
+Como o mapa é indexado por ID, a busca percorre todos os itens.
+Retorna domainerr.ErrNotFound caso nenhum item ativo tenha o código informado.
+*/
+func (r *MapRepository) FindByCode(ctx context.Context, code string) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, it := range r.items {
+		if it.Code == code && !it.Deleted() {
+			return it, nil
+		}
+	}
+	return Item{}, domainerr.NotFoundf("item com código %q não existe", code)
+}
+
+// FindByCodes busca os itens ativos com os códigos informados; códigos inexistentes ou na lixeira são omitidos.
+func (r *MapRepository) FindByCodes(ctx context.Context, codes []string) ([]Item, error) {
+	return r.findByCodes(ctx, codes, false)
+}
+
+// FindTrashedByCodes busca os itens na lixeira com os códigos informados; os demais códigos são omitidos.
+func (r *MapRepository) FindTrashedByCodes(ctx context.Context, codes []string) ([]Item, error) {
+	return r.findByCodes(ctx, codes, true)
+}
+
+// findByCodes busca os itens com os códigos informados que estão (trashed) ou não na lixeira.
+func (r *MapRepository) findByCodes(ctx context.Context, codes []string, trashed bool) ([]Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		wanted[code] = true
+	}
+	var its []Item
+	for _, it := range r.items {
+		if wanted[it.Code] && it.Deleted() == trashed {
+			its = append(its, it)
+		}
+	}
+	return its, nil
 }
 
 /*
 UpdateItem atualiza um item existente no repositório.
 
+Substitui todos os campos de PatchFields; ver PatchItem para as regras.
+*/
+func (r *MapRepository) UpdateItem(ctx context.Context, it *Item) error {
+	return r.PatchItem(ctx, it, PatchFields)
+}
+
+/*
+PatchItem altera apenas os campos listados em fields (além de updated_at e version).
+
 Regras:
-- O ID não pode ser zero.
-- O item deve já existir no mapa.
+  - O ID não pode ser zero e os campos devem pertencer a PatchFields.
+  - O item deve já existir no mapa, fora da lixeira.
+  - Se it.Version for maior que zero, deve ser igual à versão armazenada
+    (mesma regra de concorrência otimista do MySQL).
+  - O novo código não pode pertencer a outro item.
+  - Ao final, it recebe o item como ficou armazenado (os campos fora de fields
+    voltam aos valores atuais, como na releitura feita pelo adaptador SQL).
 
 Retorna erro caso as validações falhem.
 */
-func (r *MapRepository) UpdateItem(it *Item) error {
+func (r *MapRepository) PatchItem(ctx context.Context, it *Item, fields []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.patchItem(it, fields)
+}
+
+// patchItem é o PatchItem com r.mu já travado.
+func (r *MapRepository) patchItem(it *Item, fields []string) error {
 	if it.ID == 0 {
-		return fmt.Errorf("ID do item não pode ser 0")
+		return domainerr.Validation("id", "ID do item não pode ser 0")
 	}
-	if _, exists := r.items[it.ID]; !exists {
-		return fmt.Errorf("item com ID %d não existe", it.ID)
+	if err := validPatchFields(fields); err != nil {
+		return err
 	}
-	r.items[it.ID] = *it
+	stored, exists := r.items[it.ID]
+	if !exists || stored.Deleted() {
+		return domainerr.NotFoundf("item com ID %d não existe", it.ID)
+	}
+	if it.Version != 0 && it.Version != stored.Version {
+		return errVersionConflict(it.ID, it.Version, stored.Version)
+	}
+	if slices.Contains(fields, "code") && r.codeTaken(it.Code, it.ID) {
+		return errDuplicateCode(it.Code)
+	}
+
+	for _, f := range fields {
+		stored.setField(f, *it)
+	}
+	stored.UpdatedAt = it.UpdatedAt
+	stored.Version++
+	r.items[it.ID] = stored
+	*it = stored
 	return nil
 }
 
+// codeTaken indica se outro item (de ID diferente de id), mesmo na lixeira, já usa o código. Exige r.mu travado.
+func (r *MapRepository) codeTaken(code string, id int) bool {
+	for _, other := range r.items {
+		if other.Code == code && other.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
 /*
-DeleteItem remove um item do repositório em memória.
+DeleteItem move um item para a lixeira, preenchendo DeletedAt e incrementando a versão.
 
 Regras:
 - O ID não pode ser zero.
-- O item deve existir.
+- O item deve existir e não estar na lixeira.
+- Se version for maior que zero, deve ser igual à versão armazenada.
 
 Retorna erro caso as validações falhem.
 */
-func (r *MapRepository) DeleteItem(id int) error {
+func (r *MapRepository) DeleteItem(ctx context.Context, id, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deleteItem(id, version, time.Now().UTC().Truncate(time.Second))
+}
+
+// deleteItem é o DeleteItem com r.mu já travado, gravando now em DeletedAt e UpdatedAt.
+func (r *MapRepository) deleteItem(id, version int, now time.Time) error {
 	if id == 0 {
-		return fmt.Errorf("ID do item não pode ser 0")
+		return domainerr.Validation("id", "ID do item não pode ser 0")
 	}
-	if _, exists := r.items[id]; !exists {
-		return fmt.Errorf("item com ID %d não existe", id)
+	cur, exists := r.items[id]
+	if !exists || cur.Deleted() {
+		return domainerr.NotFoundf("item com ID %d não existe", id)
 	}
-	delete(r.items, id)
+	if version != 0 && version != cur.Version {
+		return errVersionConflict(id, version, cur.Version)
+	}
+
+	cur.DeletedAt = &now
+	cur.UpdatedAt = now
+	cur.Version++
+	r.items[id] = cur
 	return nil
 }
+
+/*
+SaveItems salva vários itens novos, com as regras de SaveItem (o código também não pode se repetir no lote).
+
+Retorna um erro por item; em atomic, se algum falhar, nenhum é gravado (e o ID e a versão voltam a zero).
+*/
+func (r *MapRepository) SaveItems(ctx context.Context, its []*Item, atomic bool) ([]error, error) {
+	errs, err := r.inBatch(ctx, len(its), atomic, func(i int) error {
+		return r.saveItem(its[i])
+	})
+	if atomic && failed(errs) {
+		unsave(its) // O lote foi desfeito: nenhum item ficou gravado
+	}
+	return errs, err
+}
+
+// PatchItems aplica PatchItem a cada alteração, na ordem; em atomic, se alguma falhar, nenhuma é gravada.
+func (r *MapRepository) PatchItems(ctx context.Context, changes []ItemChange, atomic bool) ([]error, error) {
+	return r.inBatch(ctx, len(changes), atomic, func(i int) error {
+		return r.patchItem(changes[i].Item, changes[i].Fields)
+	})
+}
+
+// DeleteItems move os itens para a lixeira, na ordem; em atomic, se algum falhar, nenhum é movido.
+func (r *MapRepository) DeleteItems(ctx context.Context, refs []ItemRef, atomic bool) ([]error, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	return r.inBatch(ctx, len(refs), atomic, func(i int) error {
+		return r.deleteItem(refs[i].ID, refs[i].Version, now)
+	})
+}
+
+/*
+inBatch aplica as n linhas de um lote com r.mu travado do início ao fim.
+
+Em atomic, guarda uma cópia do mapa antes de começar e a restaura se alguma linha
+falhar, imitando o ROLLBACK da transação do adaptador SQL. No modo best-effort
+não há o que desfazer, e o mapa não é copiado.
+*/
+func (r *MapRepository) inBatch(ctx context.Context, n int, atomic bool, apply func(i int) error) ([]error, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		items  map[int]Item
+		lastID int
+	)
+	if atomic {
+		items, lastID = maps.Clone(r.items), r.lastID
+	}
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = apply(i)
+	}
+	if atomic && failed(errs) {
+		r.items, r.lastID = items, lastID
+	}
+	return errs, nil
+}
+
+/*
+RestoreItem tira um item da lixeira (DeletedAt volta a nil), atualiza UpdatedAt e incrementa a versão.
+
+Retorna o item restaurado, domainerr.ErrNotFound se ele não estiver na lixeira ou
+domainerr.ErrPreconditionFailed se a versão informada não conferir.
+*/
+func (r *MapRepository) RestoreItem(ctx context.Context, id, version int) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, exists := r.items[id]
+	if !exists || !cur.Deleted() {
+		return Item{}, errNotInTrash(id)
+	}
+	if version != 0 && version != cur.Version {
+		return Item{}, errVersionConflict(id, version, cur.Version)
+	}
+
+	cur.DeletedAt = nil
+	cur.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	cur.Version++
+	r.items[id] = cur
+	return cur, nil
+}
+
+/*
+PurgeDeleted remove do mapa os itens que estão na lixeira desde antes de before,
+junto com as movimentações de estoque deles.
+
+Retorna a quantidade de itens removidos.
+*/
+func (r *MapRepository) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := make(map[int]bool)
+	for id, it := range r.items {
+		if it.Deleted() && it.DeletedAt.Before(before) {
+			purged[id] = true
+			delete(r.items, id)
+		}
+	}
+	if len(purged) > 0 {
+		r.movements = slices.DeleteFunc(r.movements, func(m StockMovement) bool { return purged[m.ItemID] })
+	}
+	return len(purged), nil
+}
+
+/*
+AdjustStock aplica o delta ao estoque do item e registra a movimentação.
+
+Regras:
+- O item deve existir e não estar na lixeira.
+- O saldo não pode ficar negativo, a menos que allowNegative seja true.
+
+O ID da movimentação é sequencial e nunca reaproveitado, mesmo depois que PurgeDeleted
+apaga movimentações, imitando o AUTO_INCREMENT do MySQL.
+*/
+func (r *MapRepository) AdjustStock(ctx context.Context, m *StockMovement, allowNegative bool) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	it, exists := r.items[m.ItemID]
+	if !exists || it.Deleted() {
+		return Item{}, domainerr.NotFoundf("item com ID %d não existe", m.ItemID)
+	}
+	if !allowNegative && it.Stock+m.Delta < 0 {
+		return Item{}, errInsufficientStock(it.ID, it.Stock, m.Delta)
+	}
+
+	it.Stock += m.Delta
+	it.Version++
+	it.UpdatedAt = m.CreatedAt
+	r.items[it.ID] = it
+
+	r.lastMovementID++
+	m.ID = r.lastMovementID
+	m.StockAfter = it.Stock
+	r.movements = append(r.movements, *m)
+	return it, nil
+}
+
+/*
+ListMovements retorna o histórico de estoque do item, do mais recente para o mais antigo.
+
+Retorna erro caso o item não exista ou esteja na lixeira.
+*/
+func (r *MapRepository) ListMovements(ctx context.Context, itemID int, f ListFilter) (MovementPage, error) {
+	if err := ctx.Err(); err != nil {
+		return MovementPage{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if it, exists := r.items[itemID]; !exists || it.Deleted() {
+		return MovementPage{}, domainerr.NotFoundf("item com ID %d não existe", itemID)
+	}
+
+	var ms []StockMovement
+	for i := len(r.movements) - 1; i >= 0; i-- {
+		if r.movements[i].ItemID == itemID {
+			ms = append(ms, r.movements[i])
+		}
+	}
+
+	total := len(ms)
+	start := min(f.Offset, total)
+	end := total
+	if f.Limit > 0 {
+		end = min(start+f.Limit, total)
+	}
+	return NewMovementPage(ms[start:end], f, total), nil
+}
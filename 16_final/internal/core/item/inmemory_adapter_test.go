@@ -0,0 +1,120 @@
+package item
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+/*
+Testes específicos do MapRepository. Rode com `go test -race` para que o detector
+de corridas acuse qualquer acesso ao mapa fora da trava.
+*/
+
+func TestMapRepositoryConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMapRepository()
+	const shared, workers, rounds = 5, 8, 100
+
+	// Itens compartilhados (IDs 1 a shared), ajustados por todas as goroutines
+	for id := 1; id <= shared; id++ {
+		if err := repo.SaveItem(ctx, &Item{Code: fmt.Sprintf("SHARED-%d", id), Status: StatusActive}); err != nil {
+			t.Fatalf("SaveItem(%d): %v", id, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*rounds)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				// Item próprio da goroutine: salva, atualiza e remove
+				own := &Item{Code: fmt.Sprintf("W%d-%d", w, i), Status: StatusActive}
+				if err := repo.SaveItem(ctx, own); err != nil {
+					errs <- err
+					continue
+				}
+				own.Title = "atualizado"
+				if err := repo.UpdateItem(ctx, own); err != nil {
+					errs <- err
+				}
+				if i%2 == 0 {
+					if err := repo.DeleteItem(ctx, own.ID, own.Version); err != nil {
+						errs <- err
+					}
+				}
+
+				// Item compartilhado: movimenta o estoque e lê em paralelo
+				sharedID := i%shared + 1
+				if _, err := repo.AdjustStock(ctx, &StockMovement{ItemID: sharedID, Type: MovementReceipt, Delta: 1}, false); err != nil {
+					errs <- err
+				}
+				if _, err := repo.ListItems(ctx, ListFilter{Limit: 10}); err != nil {
+					errs <- err
+				}
+				if _, err := repo.FindByCode(ctx, fmt.Sprintf("SHARED-%d", sharedID)); err != nil {
+					errs <- err
+				}
+				if _, err := repo.ListMovements(ctx, sharedID, ListFilter{Limit: 5}); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("operação concorrente falhou: %v", err)
+	}
+
+	// Nenhum ajuste pode ter se perdido: cada item compartilhado recebeu workers*rounds/shared entradas
+	for id := 1; id <= shared; id++ {
+		it, err := repo.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID(%d): %v", id, err)
+		}
+		want := workers * rounds / shared
+		if it.Stock != want || it.Version != want+1 {
+			t.Fatalf("item %d: estoque %d versão %d, esperado %d e %d", id, it.Stock, it.Version, want, want+1)
+		}
+	}
+
+	page, _ := repo.ListItems(ctx, ListFilter{})
+	if want := shared + workers*rounds/2; page.Total != want {
+		t.Fatalf("total de itens = %d, esperado %d", page.Total, want)
+	}
+}
+
+func TestMapRepositoryReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMapRepository()
+
+	saved := &Item{Code: "A", Title: "original", Status: StatusActive}
+	if err := repo.SaveItem(ctx, saved); err != nil {
+		t.Fatalf("SaveItem: %v", err)
+	}
+	if _, err := repo.AdjustStock(ctx, &StockMovement{ItemID: 1, Type: MovementReceipt, Delta: 3}, false); err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+
+	// Altera tudo o que o repositório devolveu (ou recebeu) por fora dele
+	saved.Title = "alterado pelo chamador"
+	found, _ := repo.FindByID(ctx, 1)
+	found.Title = "alterado pelo chamador"
+	page, _ := repo.ListItems(ctx, ListFilter{})
+	page.Items[0].Title = "alterado pelo chamador"
+	movements, _ := repo.ListMovements(ctx, 1, ListFilter{})
+	movements.Items[0].Delta = 100
+
+	it, _ := repo.FindByID(ctx, 1)
+	if it.Title != "original" {
+		t.Fatalf("o título armazenado mudou para %q", it.Title)
+	}
+	again, _ := repo.ListMovements(ctx, 1, ListFilter{})
+	if again.Items[0].Delta != 3 {
+		t.Fatalf("a movimentação armazenada mudou para delta %d", again.Items[0].Delta)
+	}
+}
@@ -2,6 +2,8 @@ package item
 
 import (
 	"time"
+
+	"api/internal/core/domainerr"
 )
 
 /*
@@ -9,19 +11,39 @@ Item representa um produto ou item do sistema.
 
 Cada campo possui uma tag `json` indicando como será serializado/deserializado
 quando os dados forem enviados ou recebidos via API REST.
+
+A exclusão é lógica: um item excluído continua no repositório, com DeletedAt preenchido,
+até ser restaurado ou expurgado (ver ItemRepositoryPort.RestoreItem e PurgeDeleted).
 */
 type Item struct {
-	ID          int       `json:"id"`          // Identificador único do item
-	Code        string    `json:"code"`        // Código interno ou SKU
-	Title       string    `json:"title"`       // Nome ou título do item
-	Description string    `json:"description"` // Descrição detalhada
-	Price       float64   `json:"price"`       // Preço do item
-	Stock       int       `json:"stock"`       // Quantidade disponível em estoque
-	Status      string    `json:"status"`      // Status do item (ex: ativo, inativo, esgotado)
-	CreatedAt   time.Time `json:"created_at"`  // Data de criação do item
-	UpdatedAt   time.Time `json:"updated_at"`  // Última data de atualização
+	ID          int        `json:"id"`                   // Identificador único do item
+	Code        string     `json:"code"`                 // Código interno ou SKU
+	Title       string     `json:"title"`                // Nome ou título do item
+	Description string     `json:"description"`          // Descrição detalhada
+	Price       float64    `json:"price"`                // Preço do item
+	Stock       int        `json:"stock"`                // Quantidade disponível em estoque
+	Status      string     `json:"status"`               // Status do item (ver Statuses)
+	Version     int        `json:"version"`              // Versão do registro, incrementada a cada alteração (concorrência otimista)
+	CreatedAt   time.Time  `json:"created_at"`           // Data de criação do item
+	UpdatedAt   time.Time  `json:"updated_at"`           // Última data de atualização
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // Data em que foi para a lixeira (nil = item ativo)
 }
 
+/*
+Status válidos de um item (enum fechado).
+
+Qualquer outro valor é rejeitado pela validação do caso de uso.
+*/
+const (
+	StatusActive       = "active"       // Disponível para venda
+	StatusInactive     = "inactive"     // Cadastrado, mas fora de uso temporariamente
+	StatusOutOfStock   = "out_of_stock" // Sem estoque disponível
+	StatusDiscontinued = "discontinued" // Fora de linha, não será mais reposto
+)
+
+// Statuses lista todos os status válidos, na ordem em que são apresentados ao usuário.
+var Statuses = []string{StatusActive, StatusInactive, StatusOutOfStock, StatusDiscontinued}
+
 /*
 MapRepo representa uma estrutura de dados do tipo mapa
 usada na implementação de repositório em memória.
@@ -30,3 +52,23 @@ Chave: ID do item (int)
 Valor: struct Item
 */
 type MapRepo map[int]Item
+
+// errVersionConflict monta o erro de versão desatualizada para o item.
+func errVersionConflict(id, expected, current int) error {
+	return domainerr.PreconditionFailedf("item com ID %d está na versão %d, não %d", id, current, expected)
+}
+
+// Deleted indica se o item está na lixeira.
+func (it Item) Deleted() bool {
+	return it.DeletedAt != nil
+}
+
+// errNotInTrash monta o erro de restauração de um item que não existe ou não está na lixeira.
+func errNotInTrash(id int) error {
+	return domainerr.NotFoundf("item com ID %d não está na lixeira", id)
+}
+
+// errDuplicateCode é o erro de violação da unicidade do código (SKU), comum a todos os adaptadores.
+func errDuplicateCode(code string) error {
+	return domainerr.AlreadyExistsf("já existe um item com o código %q", code)
+}
@@ -0,0 +1,102 @@
+package item
+
+import (
+	"errors"
+	"strings"
+
+	"api/internal/core/domainerr"
+)
+
+/*
+Modos de execução das operações em lote (POST, PATCH e DELETE /items/batch).
+
+  - BatchAtomic: todas as linhas são gravadas em uma única transação; se qualquer
+    uma falhar, nenhuma é gravada (as demais recebem ErrBatchAborted);
+  - BatchBestEffort: cada linha vale por si; as que falham são reportadas e as
+    demais são gravadas normalmente.
+*/
+const (
+	BatchAtomic     = "atomic"
+	BatchBestEffort = "best_effort"
+)
+
+// BatchModes lista os modos de lote aceitos.
+var BatchModes = []string{BatchAtomic, BatchBestEffort}
+
+// MaxBatchSize é a maior quantidade de linhas aceita em um lote.
+const MaxBatchSize = 1000
+
+/*
+ErrBatchAborted é o erro das linhas válidas de um lote atômico que não foram gravadas
+porque outra linha falhou. É um domainerr.ErrConflict (HTTP 409).
+*/
+var ErrBatchAborted = domainerr.Conflictf("linha não aplicada: o lote atômico foi desfeito porque outra linha falhou")
+
+/*
+ItemChange é uma linha de ItemRepositoryPort.PatchItems: o item com os novos valores
+e os campos (de PatchFields) que devem ser gravados.
+*/
+type ItemChange struct {
+	Item   *Item    // Item com ID, versão esperada e novos valores; recebe o item como ficou armazenado
+	Fields []string // Campos a gravar (nomes de PatchFields)
+}
+
+/*
+ItemRef identifica um item pelo ID e pela versão esperada (0 = qualquer versão).
+*/
+type ItemRef struct {
+	ID      int `json:"id"`      // ID do item
+	Version int `json:"version"` // Versão esperada (0 = qualquer versão)
+}
+
+/*
+BatchPatch é uma linha de PATCH /items/batch: o merge patch a aplicar sobre o item com o ID informado.
+*/
+type BatchPatch struct {
+	ID    int   // ID do item alterado
+	Patch Patch // Alteração parcial (ver Patch.Apply)
+}
+
+/*
+BatchResult é o resultado de uma linha de uma operação em lote.
+*/
+type BatchResult struct {
+	Index int   // Posição da linha no lote (a partir de 0)
+	Item  Item  // Item como ficou armazenado (no DELETE, apenas o ID)
+	Err   error // nil se a linha foi aplicada
+}
+
+// isDomainError indica se err é uma falha da própria linha (erro de domínio), e não do banco ou do contexto.
+func isDomainError(err error) bool {
+	for _, target := range []error{
+		domainerr.ErrNotFound, domainerr.ErrAlreadyExists, domainerr.ErrConflict,
+		domainerr.ErrValidation, domainerr.ErrPreconditionFailed,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// unsave desfaz o ID e a versão atribuídos aos itens de um lote cuja gravação foi desfeita.
+func unsave(its []*Item) {
+	for _, it := range its {
+		it.ID, it.Version = 0, 0
+	}
+}
+
+// failed indica se alguma linha do lote tem erro.
+func failed(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholders retorna n placeholders separados por vírgula, para cláusulas IN e VALUES.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,243 @@
+package item
+
+import (
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"api/internal/core/domainerr"
+)
+
+/*
+SortFields lista os campos aceitos para ordenação da listagem.
+
+O nome do campo é o mesmo da tag `json` do Item e da coluna na tabela `items`,
+por isso pode ser usado diretamente no ORDER BY depois de validado.
+*/
+var SortFields = []string{"id", "code", "title", "price", "stock", "created_at", "updated_at", "deleted_at"}
+
+/*
+Limites da paginação nas entregas (REST e gRPC).
+
+  - DefaultLimit: quantidade de itens por página quando o cliente não informa o limite;
+  - MaxLimit: maior limite aceito, para proteger o banco de páginas gigantes.
+*/
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+/*
+ListFilter reúne os critérios de filtragem, ordenação e paginação da listagem de itens.
+
+Campos com valor zero (ou ponteiros nil) não filtram nada, então
+ListFilter{} retorna todos os itens ativos ordenados por ID. Itens na lixeira
+só aparecem com Trash, e nesse caso apenas eles.
+*/
+type ListFilter struct {
+	Status   string   // Status exato (ex: active)
+	MinPrice *float64 // Preço mínimo (inclusivo)
+	MaxPrice *float64 // Preço máximo (inclusivo)
+	MinStock *int     // Estoque mínimo (inclusivo)
+	MaxStock *int     // Estoque máximo (inclusivo)
+	Search   string   // Texto livre procurado em title e description
+	SortBy   string   // Campo de ordenação (ver SortFields); padrão: id
+	SortDesc bool     // true para ordem decrescente
+	Limit    int      // Quantidade máxima de itens por página (0 = sem limite)
+	Offset   int      // Quantidade de itens a pular
+	Trash    bool     // true lista apenas os itens na lixeira (DeletedAt preenchido)
+}
+
+/*
+Page é o envelope retornado pela listagem paginada.
+
+- Items: itens da página atual, na ordem pedida;
+- NextCursor: cursor opaco para buscar a próxima página (vazio na última página);
+- Total: quantidade de itens que atendem ao filtro, ignorando a paginação.
+*/
+type Page struct {
+	Items      []Item `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	Total      int    `json:"total"`
+}
+
+/*
+Validate verifica se o filtro é coerente.
+
+Retorna domainerr.ValidationError com todas as violações encontradas: campo de ordenação
+desconhecido, paginação negativa ou faixas invertidas.
+*/
+func (f ListFilter) Validate() error {
+	var fields []domainerr.FieldError
+	if f.SortBy != "" && !slices.Contains(SortFields, f.SortBy) {
+		fields = append(fields, domainerr.FieldError{Field: "sort",
+			Message: fmt.Sprintf("campo de ordenação inválido %q (use %s)", f.SortBy, strings.Join(SortFields, ", "))})
+	}
+	if f.Limit < 0 {
+		fields = append(fields, domainerr.FieldError{Field: "limit", Message: "não pode ser negativo"})
+	}
+	if f.Offset < 0 {
+		fields = append(fields, domainerr.FieldError{Field: "offset", Message: "não pode ser negativo"})
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		fields = append(fields, domainerr.FieldError{Field: "min_price", Message: "maior que max_price"})
+	}
+	if f.MinStock != nil && f.MaxStock != nil && *f.MinStock > *f.MaxStock {
+		fields = append(fields, domainerr.FieldError{Field: "min_stock", Message: "maior que max_stock"})
+	}
+
+	if len(fields) > 0 {
+		return &domainerr.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// sortField retorna o campo de ordenação, usando "id" como padrão.
+func (f ListFilter) sortField() string {
+	if f.SortBy == "" {
+		return "id"
+	}
+	return f.SortBy
+}
+
+/*
+Match indica se o item atende aos critérios de filtragem (ignora ordenação e paginação).
+
+É usado pelo repositório em memória para emular o WHERE do MySQL.
+*/
+func (f ListFilter) Match(it Item) bool {
+	if it.Deleted() != f.Trash {
+		return false
+	}
+	if f.Status != "" && it.Status != f.Status {
+		return false
+	}
+	if f.MinPrice != nil && it.Price < *f.MinPrice {
+		return false
+	}
+	if f.MaxPrice != nil && it.Price > *f.MaxPrice {
+		return false
+	}
+	if f.MinStock != nil && it.Stock < *f.MinStock {
+		return false
+	}
+	if f.MaxStock != nil && it.Stock > *f.MaxStock {
+		return false
+	}
+	if f.Search != "" {
+		q := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(it.Title), q) &&
+			!strings.Contains(strings.ToLower(it.Description), q) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Sort ordena os itens segundo SortBy/SortDesc.
+
+Empates são desfeitos pelo ID (sempre crescente), mantendo a paginação estável,
+assim como o `ORDER BY <campo>, id` usado no MySQL.
+*/
+func (f ListFilter) Sort(its []Item) {
+	field, desc := f.sortField(), f.SortDesc
+	sort.SliceStable(its, func(i, j int) bool {
+		c := compare(its[i], its[j], field)
+		if c == 0 {
+			return its[i].ID < its[j].ID
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+/*
+Paginate recorta os itens já filtrados e ordenados segundo Limit/Offset
+e monta o envelope Page com o total e o próximo cursor.
+*/
+func (f ListFilter) Paginate(its []Item) Page {
+	total := len(its)
+	start := min(f.Offset, total)
+	end := total
+	if f.Limit > 0 {
+		end = min(start+f.Limit, total)
+	}
+	return NewPage(its[start:end], f, total)
+}
+
+/*
+NewPage monta o envelope de uma página já recortada, calculando o próximo cursor.
+
+Existe próxima página quando há limite e ainda restam itens depois desta página.
+*/
+func NewPage(its []Item, f ListFilter, total int) Page {
+	if its == nil {
+		its = []Item{}
+	}
+	page := Page{Items: its, Total: total}
+	if next := f.Offset + len(its); f.Limit > 0 && next < total {
+		page.NextCursor = EncodeCursor(next)
+	}
+	return page
+}
+
+/*
+EncodeCursor transforma o offset da próxima página em um cursor opaco.
+
+O cliente não deve interpretar o cursor, apenas devolvê-lo no parâmetro `cursor`.
+*/
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+// DecodeCursor faz o caminho inverso de EncodeCursor (domainerr.ValidationError se inválido).
+func DecodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		if n, err := strconv.Atoi(strings.TrimPrefix(string(raw), "offset:")); err == nil && n >= 0 {
+			return n, nil
+		}
+	}
+	return 0, domainerr.Validation("cursor", "cursor inválido")
+}
+
+// compare compara dois itens pelo campo informado (-1, 0 ou 1).
+func compare(a, b Item, field string) int {
+	switch field {
+	case "code":
+		return strings.Compare(a.Code, b.Code)
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "price":
+		return cmp.Compare(a.Price, b.Price)
+	case "stock":
+		return cmp.Compare(a.Stock, b.Stock)
+	case "created_at":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case "deleted_at":
+		// Itens ativos (nil) vêm antes, como o NULL no ORDER BY do MySQL e do SQLite
+		if a.DeletedAt == nil || b.DeletedAt == nil {
+			return cmp.Compare(boolInt(a.Deleted()), boolInt(b.Deleted()))
+		}
+		return a.DeletedAt.Compare(*b.DeletedAt)
+	default:
+		return cmp.Compare(a.ID, b.ID)
+	}
+}
+
+// boolInt converte false/true em 0/1, para comparações.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
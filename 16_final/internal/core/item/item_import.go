@@ -0,0 +1,74 @@
+package item
+
+import "time"
+
+/*
+Situações de uma importação de planilha (ver ImportJob).
+
+  - ImportPending: aguardando processamento em segundo plano;
+  - ImportRunning: linhas sendo validadas/gravadas;
+  - ImportDone: todas as linhas foram processadas (algumas podem ter falhado, ver ImportJob.Errors);
+  - ImportFailed: o processamento foi interrompido por uma falha geral (ex: banco fora do ar).
+*/
+const (
+	ImportPending = "pending"
+	ImportRunning = "running"
+	ImportDone    = "done"
+	ImportFailed  = "failed"
+)
+
+/*
+ImportRequest é uma planilha (CSV ou XLSX) já lida, pronta para ser importada.
+
+Rows traz todas as linhas do arquivo, com o cabeçalho na primeira. Mapping associa
+o nome de uma coluna do arquivo a um campo do item (um de PatchFields, ex: "SKU" → "code");
+colunas fora do mapeamento cujo nome já é o de um campo são usadas diretamente, e as demais são ignoradas.
+*/
+type ImportRequest struct {
+	Filename string            // Nome do arquivo enviado (apenas informativo)
+	Rows     [][]string        // Linhas do arquivo; a primeira é o cabeçalho
+	Mapping  map[string]string // Coluna do arquivo → campo do item (opcional)
+	DryRun   bool              // Só valida: nenhuma linha é gravada
+	Async    bool              // Processa em segundo plano mesmo que o arquivo seja pequeno
+}
+
+/*
+ImportJob é o acompanhamento de uma importação, consultado pelo ID enquanto ela roda em segundo plano.
+
+Os contadores se referem às linhas de dados (sem o cabeçalho e as linhas em branco); em um
+dry-run, Created e Updated contam as linhas que seriam criadas e atualizadas.
+*/
+type ImportJob struct {
+	ID         string           `json:"id"`                    // Identificador do job
+	Status     string           `json:"status"`                // Situação (ImportPending, ImportRunning, ImportDone ou ImportFailed)
+	Filename   string           `json:"filename,omitempty"`    // Nome do arquivo enviado
+	Owner      string           `json:"-"`                     // Principal que iniciou a importação (vazio sem autenticação)
+	DryRun     bool             `json:"dry_run"`               // true se a importação só validou as linhas
+	Total      int              `json:"total_rows"`            // Linhas de dados do arquivo
+	Processed  int              `json:"processed_rows"`        // Linhas já processadas
+	Created    int              `json:"created"`               // Itens criados (códigos novos)
+	Updated    int              `json:"updated"`               // Itens atualizados (códigos existentes)
+	Unchanged  int              `json:"unchanged"`             // Linhas iguais ao item existente
+	Failed     int              `json:"failed"`                // Linhas rejeitadas (detalhes em Errors)
+	Err        error            `json:"-"`                     // Falha geral que interrompeu a importação (ImportFailed)
+	Errors     []ImportRowError `json:"-"`                     // Relatório de erros por linha (baixado à parte)
+	CreatedAt  time.Time        `json:"created_at"`            // Início da importação
+	FinishedAt *time.Time       `json:"finished_at,omitempty"` // Fim do processamento (nil enquanto roda)
+}
+
+// Finished indica se a importação já terminou (com sucesso ou não).
+func (j ImportJob) Finished() bool {
+	return j.Status == ImportDone || j.Status == ImportFailed
+}
+
+/*
+ImportRowError é uma linha do relatório de erros de uma importação.
+
+Uma linha da planilha pode gerar mais de um erro (um por campo inválido).
+*/
+type ImportRowError struct {
+	Row     int    `json:"row"`             // Número da linha na planilha (o cabeçalho é a linha 1)
+	Code    string `json:"code"`            // Código (SKU) informado na linha
+	Field   string `json:"field,omitempty"` // Campo com problema (vazio se o erro é da linha toda)
+	Message string `json:"message"`         // Motivo da rejeição
+}
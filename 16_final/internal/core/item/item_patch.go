@@ -0,0 +1,325 @@
+package item
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"api/internal/core/domainerr"
+)
+
+/*
+PatchFields lista os campos que podem ser alterados por um patch (e por UpdateItem).
+
+O nome do campo é o mesmo da tag `json` do Item e da coluna na tabela `items`,
+por isso pode ser usado diretamente no SET do UPDATE depois de validado.
+Os demais campos (id, version, created_at, updated_at) são controlados pelo repositório.
+*/
+var PatchFields = []string{"code", "title", "description", "price", "stock", "status"}
+
+/*
+Formatos de patch aceitos, identificados pelo media type do corpo da requisição.
+
+  - PatchMerge: JSON Merge Patch (RFC 7396), um objeto com apenas os campos a alterar
+    (`null` volta o campo ao valor zero);
+  - PatchJSON: JSON Patch (RFC 6902), uma lista de operações add, remove, replace,
+    move, copy e test sobre os campos do item (ex: "/price").
+*/
+const (
+	PatchMerge = "application/merge-patch+json"
+	PatchJSON  = "application/json-patch+json"
+)
+
+/*
+Patch é uma alteração parcial de um item, ainda no formato em que foi recebida.
+
+O documento é aplicado sobre a representação JSON do item atual, então os nomes
+dos campos são os mesmos da API (tags `json` do Item).
+*/
+type Patch struct {
+	Type string // Formato do patch (PatchMerge ou PatchJSON)
+	Body []byte // Documento do patch (JSON)
+}
+
+/*
+Apply aplica o patch sobre it e retorna o item resultante; it não é alterado.
+
+Regras:
+  - Campos desconhecidos e valores de tipo errado são violações de validação;
+  - id, created_at e updated_at são somente leitura: o patch pode repeti-los, mas não alterá-los;
+  - version também não pode ser alterada: um valor diferente do atual é tratado como
+    precondição (domainerr.ErrPreconditionFailed), como o header If-Match;
+  - No JSON Patch, a operação test que falha retorna domainerr.ErrPreconditionFailed
+    e nenhuma das operações é aplicada.
+
+As regras de negócio (código obrigatório, preço não negativo etc.) não são verificadas
+aqui: o caso de uso valida o item resultante como em qualquer atualização.
+*/
+func (p Patch) Apply(it Item) (Item, error) {
+	doc, err := toDocument(it)
+	if err != nil {
+		return Item{}, err
+	}
+
+	switch p.Type {
+	case PatchMerge:
+		err = applyMergePatch(doc, p.Body)
+	case PatchJSON:
+		err = applyJSONPatch(doc, p.Body)
+	default:
+		err = domainerr.Validation("patch", fmt.Sprintf("formato de patch não suportado %q (use %s ou %s)", p.Type, PatchMerge, PatchJSON))
+	}
+	if err != nil {
+		return Item{}, err
+	}
+
+	patched, err := fromDocument(doc)
+	if err != nil {
+		return Item{}, err
+	}
+	if err := checkReadOnly(it, patched); err != nil {
+		return Item{}, err
+	}
+	return patched, nil
+}
+
+/*
+ChangedFields retorna os campos de PatchFields em que before e after diferem, na ordem de PatchFields.
+
+É usado para gravar apenas as colunas alteradas (ver ItemRepositoryPort.PatchItem).
+*/
+func ChangedFields(before, after Item) []string {
+	var changed []string
+	for _, f := range PatchFields {
+		if before.fieldValue(f) != after.fieldValue(f) {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
+
+// fieldValue retorna o valor do campo de PatchFields com o nome (tag json) informado.
+func (it Item) fieldValue(name string) any {
+	switch name {
+	case "code":
+		return it.Code
+	case "title":
+		return it.Title
+	case "description":
+		return it.Description
+	case "price":
+		return it.Price
+	case "stock":
+		return it.Stock
+	case "status":
+		return it.Status
+	}
+	return nil
+}
+
+// setField copia o campo de PatchFields com o nome informado de src para it.
+func (it *Item) setField(name string, src Item) {
+	switch name {
+	case "code":
+		it.Code = src.Code
+	case "title":
+		it.Title = src.Title
+	case "description":
+		it.Description = src.Description
+	case "price":
+		it.Price = src.Price
+	case "stock":
+		it.Stock = src.Stock
+	case "status":
+		it.Status = src.Status
+	}
+}
+
+// validPatchFields verifica se todos os campos pertencem a PatchFields (e podem ir para o SET do UPDATE).
+func validPatchFields(fields []string) error {
+	for _, f := range fields {
+		if !slices.Contains(PatchFields, f) {
+			return domainerr.Validation(f, "campo não pode ser alterado")
+		}
+	}
+	return nil
+}
+
+// toDocument converte o item no documento JSON (campo → valor) sobre o qual o patch é aplicado.
+func toDocument(it Item) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(it)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// fromDocument converte o documento de volta em Item; campos ausentes ficam com o valor zero.
+func fromDocument(doc map[string]json.RawMessage) (Item, error) {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return Item{}, err
+	}
+
+	var it Item
+	if err := json.Unmarshal(raw, &it); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return Item{}, domainerr.Validation(typeErr.Field, fmt.Sprintf("tipo inválido: esperado %s, recebido %s", typeErr.Type, typeErr.Value))
+		}
+		return Item{}, domainerr.Validation("patch", err.Error())
+	}
+	return it, nil
+}
+
+// checkReadOnly garante que o patch não alterou os campos controlados pelo repositório.
+func checkReadOnly(before, after Item) error {
+	var fields []domainerr.FieldError
+	if after.ID != before.ID {
+		fields = append(fields, domainerr.FieldError{Field: "id", Message: "é somente leitura"})
+	}
+	if !after.CreatedAt.Equal(before.CreatedAt) {
+		fields = append(fields, domainerr.FieldError{Field: "created_at", Message: "é somente leitura"})
+	}
+	if !after.UpdatedAt.Equal(before.UpdatedAt) {
+		fields = append(fields, domainerr.FieldError{Field: "updated_at", Message: "é somente leitura"})
+	}
+	if len(fields) > 0 {
+		return &domainerr.ValidationError{Fields: fields}
+	}
+
+	if after.Version != before.Version {
+		return errVersionConflict(before.ID, after.Version, before.Version)
+	}
+	return nil
+}
+
+/*
+applyMergePatch aplica um JSON Merge Patch (RFC 7396) ao documento.
+
+Como o item não tem objetos aninhados, cada membro do patch substitui o campo
+de mesmo nome, e `null` o remove (voltando ao valor zero).
+*/
+func applyMergePatch(doc map[string]json.RawMessage, body []byte) error {
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return domainerr.Validation("patch", "o merge patch deve ser um objeto JSON")
+	}
+
+	var fields []domainerr.FieldError
+	for name, value := range patch {
+		if _, known := doc[name]; !known {
+			fields = append(fields, domainerr.FieldError{Field: name, Message: "campo desconhecido"})
+			continue
+		}
+		if isNull(value) {
+			delete(doc, name)
+		} else {
+			doc[name] = value
+		}
+	}
+	if len(fields) > 0 {
+		slices.SortFunc(fields, func(a, b domainerr.FieldError) int { return strings.Compare(a.Field, b.Field) })
+		return &domainerr.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// patchOperation é uma operação de JSON Patch (RFC 6902).
+type patchOperation struct {
+	Op    string          `json:"op"`    // add, remove, replace, move, copy ou test
+	Path  string          `json:"path"`  // Campo alvo, ex: "/price"
+	From  string          `json:"from"`  // Campo de origem (move e copy)
+	Value json.RawMessage `json:"value"` // Valor (add, replace e test)
+}
+
+/*
+applyJSONPatch aplica um JSON Patch (RFC 6902) ao documento.
+
+As operações são aplicadas em ordem e a primeira que falha interrompe o patch;
+como o documento é uma cópia, nada do que veio antes é gravado.
+*/
+func applyJSONPatch(doc map[string]json.RawMessage, body []byte) error {
+	var ops []patchOperation
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return domainerr.Validation("patch", "o JSON Patch deve ser uma lista de operações")
+	}
+
+	for i, op := range ops {
+		field := fmt.Sprintf("patch[%d]", i)
+
+		name, err := patchPath(doc, op.Path)
+		if err != nil {
+			return domainerr.Validation(field, err.Error())
+		}
+
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return domainerr.Validation(field, fmt.Sprintf("a operação %s exige value", op.Op))
+			}
+			doc[name] = op.Value
+		case "remove":
+			delete(doc, name)
+		case "move", "copy":
+			from, err := patchPath(doc, op.From)
+			if err != nil {
+				return domainerr.Validation(field, "from: "+err.Error())
+			}
+			value, ok := doc[from]
+			if !ok {
+				return domainerr.Validation(field, fmt.Sprintf("from: o campo %q não tem valor", from))
+			}
+			if op.Op == "move" {
+				delete(doc, from)
+			}
+			doc[name] = value
+		case "test":
+			if !sameJSON(doc[name], op.Value) {
+				return domainerr.PreconditionFailedf("a operação test em %q falhou", op.Path)
+			}
+		default:
+			return domainerr.Validation(field, fmt.Sprintf("operação inválida %q (use add, remove, replace, move, copy ou test)", op.Op))
+		}
+	}
+	return nil
+}
+
+// patchPath converte um JSON Pointer de um único nível ("/price") no nome do campo.
+func patchPath(doc map[string]json.RawMessage, pointer string) (string, error) {
+	name, ok := strings.CutPrefix(pointer, "/")
+	if !ok || strings.Contains(name, "/") {
+		return "", fmt.Errorf("caminho inválido %q (use /campo)", pointer)
+	}
+	name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+
+	if _, known := doc[name]; !known && !slices.Contains(PatchFields, name) {
+		return "", fmt.Errorf("campo desconhecido %q", name)
+	}
+	return name, nil
+}
+
+// isNull indica se o valor JSON é o literal null.
+func isNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// sameJSON compara dois valores JSON pelo conteúdo (ausente equivale a null).
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb any
+	if len(a) > 0 && json.Unmarshal(a, &va) != nil {
+		return false
+	}
+	if len(b) > 0 && json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
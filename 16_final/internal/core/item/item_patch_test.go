@@ -0,0 +1,87 @@
+package item
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"api/internal/core/domainerr"
+)
+
+func TestPatchApply(t *testing.T) {
+	created := time.Date(2024, 7, 17, 15, 4, 5, 0, time.UTC)
+	base := Item{ID: 7, Code: "ITEM-007", Title: "Caneta", Description: "tinta azul", Price: 2.5, Stock: 10,
+		Status: StatusActive, Version: 3, CreatedAt: created, UpdatedAt: created}
+
+	tests := []struct {
+		name        string
+		patch       Patch
+		modify      func(*Item) // Alterações esperadas sobre base (nil = erro esperado)
+		wantChanged []string
+		wantErr     error
+	}{
+		{
+			name:        "merge altera só os campos enviados",
+			patch:       Patch{PatchMerge, []byte(`{"stock": 4, "title": "Caneta azul"}`)},
+			modify:      func(it *Item) { it.Stock, it.Title = 4, "Caneta azul" },
+			wantChanged: []string{"title", "stock"},
+		},
+		{
+			name:        "merge com null limpa o campo",
+			patch:       Patch{PatchMerge, []byte(`{"description": null}`)},
+			modify:      func(it *Item) { it.Description = "" },
+			wantChanged: []string{"description"},
+		},
+		{
+			name:   "merge repetindo campos somente leitura não altera nada",
+			patch:  Patch{PatchMerge, []byte(`{"id": 7, "version": 3, "created_at": "2024-07-17T15:04:05Z"}`)},
+			modify: func(*Item) {},
+		},
+		{name: "merge com campo desconhecido", patch: Patch{PatchMerge, []byte(`{"color": "blue"}`)}, wantErr: domainerr.ErrValidation},
+		{name: "merge com tipo errado", patch: Patch{PatchMerge, []byte(`{"price": "caro"}`)}, wantErr: domainerr.ErrValidation},
+		{name: "merge alterando o ID", patch: Patch{PatchMerge, []byte(`{"id": 8}`)}, wantErr: domainerr.ErrValidation},
+		{name: "merge que não é objeto", patch: Patch{PatchMerge, []byte(`[1]`)}, wantErr: domainerr.ErrValidation},
+		{name: "merge com versão antiga", patch: Patch{PatchMerge, []byte(`{"version": 2, "stock": 1}`)}, wantErr: domainerr.ErrPreconditionFailed},
+		{
+			name: "json patch com test, replace, remove e copy",
+			patch: Patch{PatchJSON, []byte(`[
+				{"op": "test", "path": "/stock", "value": 10},
+				{"op": "replace", "path": "/price", "value": 3},
+				{"op": "remove", "path": "/description"},
+				{"op": "copy", "from": "/code", "path": "/title"}
+			]`)},
+			modify:      func(it *Item) { it.Price, it.Description, it.Title = 3, "", "ITEM-007" },
+			wantChanged: []string{"title", "description", "price"},
+		},
+		{name: "json patch com test que falha", patch: Patch{PatchJSON, []byte(`[{"op": "test", "path": "/stock", "value": 9}, {"op": "replace", "path": "/stock", "value": 0}]`)}, wantErr: domainerr.ErrPreconditionFailed},
+		{name: "json patch com caminho aninhado", patch: Patch{PatchJSON, []byte(`[{"op": "replace", "path": "/price/0", "value": 1}]`)}, wantErr: domainerr.ErrValidation},
+		{name: "json patch com operação inválida", patch: Patch{PatchJSON, []byte(`[{"op": "increment", "path": "/stock", "value": 1}]`)}, wantErr: domainerr.ErrValidation},
+		{name: "json patch sem value", patch: Patch{PatchJSON, []byte(`[{"op": "replace", "path": "/stock"}]`)}, wantErr: domainerr.ErrValidation},
+		{name: "formato desconhecido", patch: Patch{"text/plain", []byte(`{}`)}, wantErr: domainerr.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.patch.Apply(base)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Apply: esperado %v, obtido %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+
+			want := base
+			tt.modify(&want)
+			if got != want {
+				t.Fatalf("Apply = %+v; esperado %+v", got, want)
+			}
+			if changed := ChangedFields(base, got); !slices.Equal(changed, tt.wantChanged) {
+				t.Fatalf("ChangedFields = %v; esperado %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
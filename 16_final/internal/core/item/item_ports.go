@@ -1,5 +1,10 @@
 package item
 
+import (
+	"context"
+	"time"
+)
+
 /*
 ItemRepositoryPort define o contrato que toda implementação de repositório de itens deve cumprir.
 
@@ -12,21 +17,87 @@ Vantagens:
 - Permite trocar facilmente o repositório (MySQL, MongoDB, memória, etc.);
 - Facilita testes, pois podemos usar mocks ou versões em memória;
 - Segue os princípios da arquitetura limpa (clean architecture).
+
+Todos os métodos recebem um context.Context: quando ele é cancelado (cliente
+desconectou) ou o prazo expira, a operação deve parar e retornar ctx.Err().
 */
 type ItemRepositoryPort interface {
-	// SaveItem salva um novo item no repositório.
-	// Retorna erro caso o item viole regras (ex: ID duplicado).
-	SaveItem(*Item) error
+	// SaveItem salva um novo item no repositório. O ID é gerado pelo repositório e gravado
+	// em it.ID (o recebido é ignorado). Retorna erro caso o item viole regras (ex: código duplicado).
+	SaveItem(ctx context.Context, it *Item) error
+
+	// ListItems retorna os itens que atendem ao filtro, ordenados e paginados.
+	// Retorna a página de itens (com o total e o próximo cursor) e um erro (se houver).
+	ListItems(ctx context.Context, f ListFilter) (Page, error)
+
+	// StreamItems chama fn para cada item que atende ao filtro, na ordem de ListItems, sem
+	// montar a lista inteira em memória; para no primeiro erro de fn e o retorna.
+	StreamItems(ctx context.Context, f ListFilter, fn func(Item) error) error
+
+	// FindByID busca um único item pelo ID.
+	// Retorna domainerr.ErrNotFound caso o item não exista.
+	FindByID(ctx context.Context, id int) (Item, error)
+
+	// FindByIDs busca os itens ativos com os IDs informados, em qualquer ordem;
+	// IDs inexistentes (ou na lixeira) são simplesmente omitidos.
+	FindByIDs(ctx context.Context, ids []int) ([]Item, error)
+
+	// FindByCode busca um único item pelo código (SKU).
+	// Retorna domainerr.ErrNotFound caso o item não exista.
+	FindByCode(ctx context.Context, code string) (Item, error)
+
+	// FindByCodes busca os itens ativos com os códigos informados, com as mesmas regras de FindByIDs.
+	FindByCodes(ctx context.Context, codes []string) ([]Item, error)
 
-	// ListItems retorna todos os itens armazenados no repositório.
-	// Retorna o mapa de itens e um erro (se houver).
-	ListItems() (MapRepo, error)
+	// FindTrashedByCodes busca os itens na lixeira com os códigos informados (que continuam
+	// reservados), com as mesmas regras de FindByIDs.
+	FindTrashedByCodes(ctx context.Context, codes []string) ([]Item, error)
 
 	// UpdateItem atualiza um item existente no repositório.
+	// Se Item.Version for maior que zero, só atualiza se a versão armazenada for a mesma
+	// (domainerr.ErrPreconditionFailed caso contrário); ao final, Item.Version recebe a nova versão.
 	// Retorna erro caso o item não exista.
-	UpdateItem(*Item) error
+	UpdateItem(ctx context.Context, it *Item) error
+
+	// PatchItem grava apenas os campos listados em fields (nomes de PatchFields), além de
+	// updated_at e da nova versão, com as mesmas regras de UpdateItem; ao final, it recebe
+	// o item completo como ficou armazenado.
+	PatchItem(ctx context.Context, it *Item, fields []string) error
+
+	// DeleteItem move um item para a lixeira (preenche DeletedAt e incrementa a versão),
+	// com base no ID e na versão esperada (0 = qualquer versão). Itens na lixeira não são
+	// encontrados por FindByID/FindByCode nem alterados pelos demais métodos, mas mantêm o código reservado.
+	// Retorna erro caso o item não exista (ou já esteja na lixeira), a versão não confira ou o ID seja inválido.
+	DeleteItem(ctx context.Context, id, version int) error
+
+	// RestoreItem tira um item da lixeira, com base no ID e na versão esperada (0 = qualquer versão).
+	// Retorna o item restaurado (com a nova versão) ou domainerr.ErrNotFound se ele não estiver na lixeira.
+	RestoreItem(ctx context.Context, id, version int) (Item, error)
+
+	// PurgeDeleted remove definitivamente os itens que estão na lixeira desde antes de before,
+	// junto com o histórico de estoque deles. Retorna quantos itens foram removidos.
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+
+	// SaveItems salva vários itens novos, com as mesmas regras de SaveItem, preenchendo ID e versão de cada um.
+	// Retorna um erro por item, na mesma ordem de its (nil = gravado); em atomic, se algum item falhar
+	// nenhum é gravado. O segundo retorno é uma falha do lote inteiro (banco, contexto), quando nada é gravado.
+	// Quando nada é gravado, o ID e a versão dos itens ficam zerados.
+	SaveItems(ctx context.Context, its []*Item, atomic bool) ([]error, error)
+
+	// PatchItems aplica PatchItem a cada alteração, com o mesmo retorno e a mesma semântica de atomic de SaveItems.
+	PatchItems(ctx context.Context, changes []ItemChange, atomic bool) ([]error, error)
+
+	// DeleteItems move vários itens para a lixeira, como DeleteItem, com o mesmo retorno e a mesma
+	// semântica de atomic de SaveItems.
+	DeleteItems(ctx context.Context, refs []ItemRef, atomic bool) ([]error, error)
+
+	// AdjustStock aplica o delta da movimentação ao estoque do item de forma atômica
+	// e registra a movimentação no histórico (preenchendo ID e StockAfter).
+	// Retorna o item atualizado, domainerr.ErrNotFound se o item não existir ou erro
+	// de estoque insuficiente quando o saldo ficaria negativo sem allowNegative.
+	AdjustStock(ctx context.Context, m *StockMovement, allowNegative bool) (Item, error)
 
-	// DeleteItem remove um item com base no ID.
-	// Retorna erro caso o item não exista ou ID seja inválido.
-	DeleteItem(int) error
+	// ListMovements retorna o histórico de estoque do item, do mais recente para o mais antigo,
+	// paginado segundo Limit/Offset do filtro. Retorna domainerr.ErrNotFound se o item não existir.
+	ListMovements(ctx context.Context, itemID int, f ListFilter) (MovementPage, error)
 }
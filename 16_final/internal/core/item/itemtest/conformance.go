@@ -0,0 +1,729 @@
+/*
+Package itemtest contém a suíte de conformidade de item.ItemRepositoryPort.
+
+Qualquer implementação do repositório (memória, SQLite, MySQL ou uma nova) deve
+passar pelos mesmos casos, garantindo que trocar de backend na configuração não
+muda o comportamento da API:
+
+	func TestMeuRepositorio(t *testing.T) {
+		itemtest.RunConformance(t, func(t *testing.T) item.ItemRepositoryPort {
+			return novoRepositorioVazio(t)
+		})
+	}
+*/
+package itemtest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"api/internal/core/domainerr"
+	"api/internal/core/item"
+)
+
+/*
+Factory cria um repositório vazio para um caso da suíte.
+
+É chamada uma vez por caso; recursos (conexões, arquivos) devem ser liberados
+com t.Cleanup.
+*/
+type Factory func(t *testing.T) item.ItemRepositoryPort
+
+/*
+RunConformance executa todos os casos da suíte, cada um como subteste com um
+repositório novo criado por newRepo.
+
+Casos:
+  - SaveAndFind: o ID é gerado pelo repositório (crescente, sem reuso, ignorando o ID recebido) e o item salvo
+    é encontrado por ID e por código, com todos os campos;
+  - NotFound: buscas, alterações e movimentações de itens inexistentes retornam ErrNotFound;
+  - Duplicates: código repetido retorna ErrAlreadyExists, no insert e no update;
+  - ListItems: filtros, busca literal, ordenação e paginação;
+  - Versioning: concorrência otimista em UpdateItem e DeleteItem;
+  - PartialUpdate: PatchItem grava só os campos pedidos, com as regras de UpdateItem;
+  - SoftDelete: itens excluídos vão para a lixeira, somem das buscas e podem ser restaurados ou expurgados;
+  - Batch: SaveItems, PatchItems e DeleteItems reportam um erro por linha e, em atomic, não gravam nada se uma linha falhar;
+  - Timestamps: created_at é preservado e updated_at acompanha as alterações;
+  - StockMovements: saldo, histórico do mais recente para o mais antigo (com e sem limite), estoque insuficiente
+    e IDs de movimentação sem reuso depois do expurgo;
+  - CanceledContext: nenhuma operação tem efeito com o contexto já cancelado.
+*/
+func RunConformance(t *testing.T, newRepo Factory) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, repo item.ItemRepositoryPort)
+	}{
+		{"SaveAndFind", testSaveAndFind},
+		{"NotFound", testNotFound},
+		{"Duplicates", testDuplicates},
+		{"ListItems", testListItems},
+		{"Versioning", testVersioning},
+		{"PartialUpdate", testPartialUpdate},
+		{"SoftDelete", testSoftDelete},
+		{"Batch", testBatch},
+		{"Timestamps", testTimestamps},
+		{"StockMovements", testStockMovements},
+		{"CanceledContext", testCanceledContext},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.run(t, newRepo(t))
+		})
+	}
+}
+
+// baseTime é o instante usado nos itens da suíte (UTC, sem frações: cabe em qualquer coluna DATETIME).
+var baseTime = time.Date(2024, 7, 17, 15, 4, 5, 0, time.UTC)
+
+/*
+Seed salva os itens na ordem dada e retorna-os como ficaram no repositório.
+
+O ID é sempre gerado pelo repositório (it.ID é zerado antes de salvar) e deve ser
+devolvido em it.ID; Seed falha o teste se o item não puder ser relido por ele.
+Status vazio vira item.StatusActive, e as datas vazias viram baseTime.
+*/
+func Seed(t *testing.T, repo item.ItemRepositoryPort, its ...item.Item) []item.Item {
+	t.Helper()
+	ctx := context.Background()
+
+	saved := make([]item.Item, 0, len(its))
+	for _, it := range its {
+		it.ID = 0
+		if it.Status == "" {
+			it.Status = item.StatusActive
+		}
+		if it.CreatedAt.IsZero() {
+			it.CreatedAt, it.UpdatedAt = baseTime, baseTime
+		}
+		if err := repo.SaveItem(ctx, &it); err != nil {
+			t.Fatalf("SaveItem(%s): %v", it.Code, err)
+		}
+		if it.ID == 0 {
+			t.Fatalf("SaveItem(%s) não devolveu o ID gerado", it.Code)
+		}
+
+		stored, err := repo.FindByID(ctx, it.ID)
+		if err != nil || stored.Code != it.Code {
+			t.Fatalf("FindByID(%d) depois de salvar %s = %+v, %v", it.ID, it.Code, stored, err)
+		}
+		saved = append(saved, stored)
+	}
+	return saved
+}
+
+func testSaveAndFind(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	want := item.Item{
+		Code: "ITEM-001", Title: "Caneta", Description: "tinta azul",
+		Price: 2.5, Stock: 10, Status: item.StatusInactive,
+	}
+	saved := Seed(t, repo, want)[0]
+
+	if saved.ID == 0 {
+		t.Fatal("o item salvo ficou sem ID")
+	}
+	if saved.Code != want.Code || saved.Title != want.Title || saved.Description != want.Description ||
+		saved.Price != want.Price || saved.Stock != want.Stock || saved.Status != want.Status || saved.Version != 1 {
+		t.Fatalf("item salvo = %+v; esperado os campos de %+v na versão 1", saved, want)
+	}
+
+	byCode, err := repo.FindByCode(ctx, saved.Code)
+	if err != nil {
+		t.Fatalf("FindByCode: %v", err)
+	}
+	if byCode != saved {
+		t.Fatalf("FindByCode = %+v; esperado %+v", byCode, saved)
+	}
+
+	// IDs crescentes e nunca reutilizados, mesmo depois de apagar o maior deles
+	second := Seed(t, repo, item.Item{Code: "ITEM-002", Title: "Lápis"})[0]
+	if second.ID <= saved.ID {
+		t.Fatalf("ID do segundo item = %d, esperado maior que %d", second.ID, saved.ID)
+	}
+	if err := repo.DeleteItem(ctx, second.ID, 0); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	third := Seed(t, repo, item.Item{Code: "ITEM-003", Title: "Borracha"})[0]
+	if third.ID <= second.ID {
+		t.Fatalf("ID do terceiro item = %d, esperado maior que %d (IDs não podem ser reutilizados)", third.ID, second.ID)
+	}
+
+	// O ID que vier no item é ignorado: o repositório sempre gera o seu
+	withID := item.Item{ID: 500, Code: "ITEM-004", Title: "Régua", Status: item.StatusActive, CreatedAt: baseTime, UpdatedAt: baseTime}
+	if err := repo.SaveItem(ctx, &withID); err != nil {
+		t.Fatalf("SaveItem com ID preenchido: %v", err)
+	}
+	if withID.ID == 500 || withID.ID <= third.ID {
+		t.Fatalf("SaveItem com ID 500 gravou o ID %d; esperado um ID gerado, maior que %d", withID.ID, third.ID)
+	}
+	if _, err := repo.FindByID(ctx, 500); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("FindByID(500) depois de SaveItem com ID 500: esperado ErrNotFound, obtido %v", err)
+	}
+}
+
+func testNotFound(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	const missing = 999
+
+	checks := map[string]error{}
+	_, checks["FindByID"] = repo.FindByID(ctx, missing)
+	_, checks["FindByCode"] = repo.FindByCode(ctx, "NOPE")
+	checks["UpdateItem"] = repo.UpdateItem(ctx, &item.Item{ID: missing, Code: "X", Title: "x", Status: item.StatusActive})
+	checks["DeleteItem"] = repo.DeleteItem(ctx, missing, 0)
+	_, checks["AdjustStock"] = repo.AdjustStock(ctx, &item.StockMovement{ItemID: missing, Type: item.MovementReceipt, Delta: 1, CreatedAt: baseTime}, false)
+	_, checks["ListMovements"] = repo.ListMovements(ctx, missing, item.ListFilter{})
+
+	for op, err := range checks {
+		if !errors.Is(err, domainerr.ErrNotFound) {
+			t.Errorf("%s de item inexistente: esperado ErrNotFound, obtido %v", op, err)
+		}
+	}
+}
+
+func testDuplicates(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	its := Seed(t, repo, item.Item{Code: "AAA", Title: "Caneta"}, item.Item{Code: "BBB", Title: "Caderno"})
+
+	dup := item.Item{Code: "AAA", Title: "Outra", Status: item.StatusActive, CreatedAt: baseTime, UpdatedAt: baseTime}
+	if err := repo.SaveItem(ctx, &dup); !errors.Is(err, domainerr.ErrAlreadyExists) {
+		t.Fatalf("SaveItem com código repetido: esperado ErrAlreadyExists, obtido %v", err)
+	}
+
+	rename := its[1]
+	rename.Code = "AAA"
+	if err := repo.UpdateItem(ctx, &rename); !errors.Is(err, domainerr.ErrAlreadyExists) {
+		t.Fatalf("UpdateItem para código de outro item: esperado ErrAlreadyExists, obtido %v", err)
+	}
+
+	// Regravar o próprio código não é duplicidade
+	same := its[0]
+	if err := repo.UpdateItem(ctx, &same); err != nil {
+		t.Fatalf("UpdateItem mantendo o código: %v", err)
+	}
+	if page, _ := repo.ListItems(ctx, item.ListFilter{}); page.Total != 2 {
+		t.Fatalf("total depois das tentativas de duplicar = %d, esperado 2", page.Total)
+	}
+}
+
+func testListItems(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	ptr := func(v float64) *float64 { return &v }
+
+	Seed(t, repo,
+		item.Item{Code: "AAA", Title: "Caneta", Description: "tinta azul", Price: 2, Stock: 10},
+		item.Item{Code: "BBB", Title: "Caderno", Price: 15, Stock: 3},
+		item.Item{Code: "CCC", Title: "Mochila", Price: 20, Stock: 0, Status: item.StatusInactive},
+		item.Item{Code: "DDD", Title: "Desconto 50%", Price: 99.9, Stock: 1},
+	)
+
+	tests := []struct {
+		name      string
+		filter    item.ListFilter
+		wantCodes []string
+		wantTotal int
+		wantNext  bool
+	}{
+		{"sem filtro ordena por id", item.ListFilter{}, []string{"AAA", "BBB", "CCC", "DDD"}, 4, false},
+		{"status", item.ListFilter{Status: item.StatusInactive}, []string{"CCC"}, 1, false},
+		{"faixa de preço", item.ListFilter{MinPrice: ptr(5), MaxPrice: ptr(20)}, []string{"BBB", "CCC"}, 2, false},
+		{"busca literal com curinga", item.ListFilter{Search: "50%"}, []string{"DDD"}, 1, false},
+		{"busca em description", item.ListFilter{Search: "azul"}, []string{"AAA"}, 1, false},
+		{"ordem decrescente por preço", item.ListFilter{SortBy: "price", SortDesc: true}, []string{"DDD", "CCC", "BBB", "AAA"}, 4, false},
+		{"ordem por título", item.ListFilter{SortBy: "title"}, []string{"BBB", "AAA", "DDD", "CCC"}, 4, false},
+		{"primeira página", item.ListFilter{Limit: 2}, []string{"AAA", "BBB"}, 4, true},
+		{"última página", item.ListFilter{Limit: 2, Offset: 2}, []string{"CCC", "DDD"}, 4, false},
+		{"offset sem limit", item.ListFilter{Offset: 3}, []string{"DDD"}, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.ListItems(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListItems: %v", err)
+			}
+			var codes []string
+			for _, it := range page.Items {
+				codes = append(codes, it.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) || page.Total != tt.wantTotal || (page.NextCursor != "") != tt.wantNext {
+				t.Fatalf("ListItems = %v (total %d, next %q); esperado %v (total %d, next %v)",
+					codes, page.Total, page.NextCursor, tt.wantCodes, tt.wantTotal, tt.wantNext)
+			}
+
+			// StreamItems percorre exatamente os itens da listagem, na mesma ordem
+			codes = nil
+			err = repo.StreamItems(ctx, tt.filter, func(it item.Item) error {
+				codes = append(codes, it.Code)
+				return nil
+			})
+			if err != nil || !slices.Equal(codes, tt.wantCodes) {
+				t.Fatalf("StreamItems = %v, %v; esperado %v", codes, err, tt.wantCodes)
+			}
+		})
+	}
+
+	// Um erro de fn interrompe a leitura e é devolvido como está
+	stop := errors.New("parar")
+	calls := 0
+	err := repo.StreamItems(ctx, item.ListFilter{}, func(item.Item) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("StreamItems com erro em fn = %v após %d chamadas; esperado %v após 1", err, calls, stop)
+	}
+}
+
+func testVersioning(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	it := Seed(t, repo, item.Item{Code: "ITEM-001", Title: "Caneta", Stock: 10})[0]
+
+	it.Title = "Caneta azul"
+	if err := repo.UpdateItem(ctx, &it); err != nil {
+		t.Fatalf("UpdateItem: %v", err)
+	}
+	if it.Version != 2 || it.Title != "Caneta azul" {
+		t.Fatalf("item devolvido por UpdateItem = %+v; esperado o novo título na versão 2", it)
+	}
+
+	stale := it
+	stale.Version = 1
+	if err := repo.UpdateItem(ctx, &stale); !errors.Is(err, domainerr.ErrPreconditionFailed) {
+		t.Fatalf("UpdateItem com versão antiga: esperado ErrPreconditionFailed, obtido %v", err)
+	}
+
+	// Versão 0 ignora o controle de concorrência
+	unconditional := it
+	unconditional.Version = 0
+	if err := repo.UpdateItem(ctx, &unconditional); err != nil || unconditional.Version != 3 {
+		t.Fatalf("UpdateItem sem versão = versão %d, %v; esperado versão 3", unconditional.Version, err)
+	}
+
+	if err := repo.DeleteItem(ctx, it.ID, 2); !errors.Is(err, domainerr.ErrPreconditionFailed) {
+		t.Fatalf("DeleteItem com versão antiga: esperado ErrPreconditionFailed, obtido %v", err)
+	}
+	if err := repo.DeleteItem(ctx, it.ID, 3); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if err := repo.DeleteItem(ctx, it.ID, 0); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("DeleteItem repetido: esperado ErrNotFound, obtido %v", err)
+	}
+}
+
+func testPartialUpdate(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	its := Seed(t, repo,
+		item.Item{Code: "ITEM-001", Title: "Caneta", Description: "tinta azul", Price: 2, Stock: 10},
+		item.Item{Code: "ITEM-002", Title: "Lápis"},
+	)
+	original := its[0]
+
+	// Só price é gravado: o título alterado na cópia local não pode chegar ao repositório
+	patch := original
+	patch.Title = "não deve ser gravado"
+	patch.Price = 3.5
+	patch.UpdatedAt = baseTime.Add(time.Minute)
+	if err := repo.PatchItem(ctx, &patch, []string{"price"}); err != nil {
+		t.Fatalf("PatchItem: %v", err)
+	}
+	want := original
+	want.Price, want.Version, want.UpdatedAt = 3.5, 2, baseTime.Add(time.Minute)
+	if patch != want {
+		t.Fatalf("item devolvido por PatchItem = %+v; esperado %+v", patch, want)
+	}
+	if got, _ := repo.FindByID(ctx, original.ID); got != want {
+		t.Fatalf("item armazenado = %+v; esperado %+v", got, want)
+	}
+
+	stale := original // versão 1
+	stale.Stock = 0
+	if err := repo.PatchItem(ctx, &stale, []string{"stock"}); !errors.Is(err, domainerr.ErrPreconditionFailed) {
+		t.Fatalf("PatchItem com versão antiga: esperado ErrPreconditionFailed, obtido %v", err)
+	}
+
+	rename := its[1]
+	rename.Code = "ITEM-001"
+	if err := repo.PatchItem(ctx, &rename, []string{"code"}); !errors.Is(err, domainerr.ErrAlreadyExists) {
+		t.Fatalf("PatchItem para código de outro item: esperado ErrAlreadyExists, obtido %v", err)
+	}
+
+	readOnly := want
+	if err := repo.PatchItem(ctx, &readOnly, []string{"created_at"}); !errors.Is(err, domainerr.ErrValidation) {
+		t.Fatalf("PatchItem de campo fora de item.PatchFields: esperado ErrValidation, obtido %v", err)
+	}
+
+	missing := item.Item{ID: 999, Code: "X", UpdatedAt: baseTime}
+	if err := repo.PatchItem(ctx, &missing, []string{"title"}); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("PatchItem de item inexistente: esperado ErrNotFound, obtido %v", err)
+	}
+}
+
+func testSoftDelete(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	its := Seed(t, repo,
+		item.Item{Code: "AAA", Title: "Caneta", Stock: 5},
+		item.Item{Code: "BBB", Title: "Caderno", Stock: 5},
+		item.Item{Code: "CCC", Title: "Mochila"},
+	)
+	a, b := its[0], its[1]
+
+	before := time.Now().Add(-time.Second)
+	if err := repo.DeleteItem(ctx, a.ID, a.Version); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+
+	// O item excluído some de todas as operações sobre itens ativos
+	checks := map[string]error{}
+	_, checks["FindByID"] = repo.FindByID(ctx, a.ID)
+	_, checks["FindByCode"] = repo.FindByCode(ctx, a.Code)
+	checks["UpdateItem"] = repo.UpdateItem(ctx, &item.Item{ID: a.ID, Code: "AAA", Title: "x", Status: item.StatusActive})
+	checks["PatchItem"] = repo.PatchItem(ctx, &item.Item{ID: a.ID, Title: "x"}, []string{"title"})
+	checks["DeleteItem"] = repo.DeleteItem(ctx, a.ID, 0)
+	_, checks["AdjustStock"] = repo.AdjustStock(ctx, &item.StockMovement{ItemID: a.ID, Type: item.MovementReceipt, Delta: 1, CreatedAt: baseTime}, false)
+	_, checks["ListMovements"] = repo.ListMovements(ctx, a.ID, item.ListFilter{})
+	for op, err := range checks {
+		if !errors.Is(err, domainerr.ErrNotFound) {
+			t.Errorf("%s de item na lixeira: esperado ErrNotFound, obtido %v", op, err)
+		}
+	}
+	if page, _ := repo.ListItems(ctx, item.ListFilter{}); page.Total != 2 {
+		t.Fatalf("listagem depois da exclusão: total %d, esperado 2", page.Total)
+	}
+
+	// O código continua reservado enquanto o item estiver na lixeira
+	reuse := item.Item{Code: "AAA", Title: "Outra", Status: item.StatusActive, CreatedAt: baseTime, UpdatedAt: baseTime}
+	if err := repo.SaveItem(ctx, &reuse); !errors.Is(err, domainerr.ErrAlreadyExists) {
+		t.Fatalf("SaveItem com código de item na lixeira: esperado ErrAlreadyExists, obtido %v", err)
+	}
+	if found, err := repo.FindTrashedByCodes(ctx, []string{a.Code, b.Code, "ZZZ"}); err != nil || len(found) != 1 || found[0].ID != a.ID {
+		t.Fatalf("FindTrashedByCodes = %+v, %v; esperado apenas o item %d", found, err, a.ID)
+	}
+
+	trash, err := repo.ListItems(ctx, item.ListFilter{Trash: true})
+	if err != nil || trash.Total != 1 || trash.Items[0].ID != a.ID {
+		t.Fatalf("lixeira = %+v, %v; esperado apenas o item %d", trash, err, a.ID)
+	}
+	deleted := trash.Items[0]
+	if deleted.DeletedAt == nil || deleted.DeletedAt.Before(before.Truncate(time.Second)) || deleted.Version != a.Version+1 {
+		t.Fatalf("item na lixeira = %+v; esperado deleted_at preenchido e versão %d", deleted, a.Version+1)
+	}
+	if !deleted.UpdatedAt.Equal(*deleted.DeletedAt) {
+		t.Fatalf("updated_at depois da exclusão = %s, esperado igual a deleted_at (%s)", deleted.UpdatedAt, *deleted.DeletedAt)
+	}
+
+	// Restauração: respeita a versão e devolve o item ativo
+	if _, err := repo.RestoreItem(ctx, a.ID, a.Version); !errors.Is(err, domainerr.ErrPreconditionFailed) {
+		t.Fatalf("RestoreItem com versão antiga: esperado ErrPreconditionFailed, obtido %v", err)
+	}
+	restored, err := repo.RestoreItem(ctx, a.ID, deleted.Version)
+	if err != nil {
+		t.Fatalf("RestoreItem: %v", err)
+	}
+	if restored.DeletedAt != nil || restored.Version != deleted.Version+1 || restored.Stock != a.Stock {
+		t.Fatalf("item restaurado = %+v", restored)
+	}
+	if restored.UpdatedAt.Before(*deleted.DeletedAt) || restored.UpdatedAt.After(time.Now().Add(time.Second)) {
+		t.Fatalf("updated_at depois de restaurar = %s, esperado entre %s e agora", restored.UpdatedAt, *deleted.DeletedAt)
+	}
+	if found, err := repo.FindByID(ctx, a.ID); err != nil || found != restored {
+		t.Fatalf("FindByID depois de restaurar = %+v, %v; esperado %+v", found, err, restored)
+	}
+	for _, id := range []int{a.ID, 999} {
+		if _, err := repo.RestoreItem(ctx, id, 0); !errors.Is(err, domainerr.ErrNotFound) {
+			t.Fatalf("RestoreItem(%d) fora da lixeira: esperado ErrNotFound, obtido %v", id, err)
+		}
+	}
+
+	// Expurgo: só remove o que está na lixeira desde antes do corte, junto com o histórico
+	if _, err := repo.AdjustStock(ctx, &item.StockMovement{ItemID: b.ID, Type: item.MovementSale, Delta: -1, CreatedAt: baseTime}, false); err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+	if err := repo.DeleteItem(ctx, b.ID, 0); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if n, err := repo.PurgeDeleted(ctx, before); err != nil || n != 0 {
+		t.Fatalf("PurgeDeleted antes da exclusão = %d, %v; esperado 0", n, err)
+	}
+	if n, err := repo.PurgeDeleted(ctx, time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Fatalf("PurgeDeleted = %d, %v; esperado 1", n, err)
+	}
+	if trash, _ := repo.ListItems(ctx, item.ListFilter{Trash: true}); trash.Total != 0 {
+		t.Fatalf("lixeira depois do expurgo: total %d, esperado 0", trash.Total)
+	}
+	if _, err := repo.RestoreItem(ctx, b.ID, 0); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("RestoreItem de item expurgado: esperado ErrNotFound, obtido %v", err)
+	}
+
+	// O código de um item expurgado fica livre de novo
+	reuse = item.Item{Code: "BBB", Title: "Caderno novo", Status: item.StatusActive, CreatedAt: baseTime, UpdatedAt: baseTime}
+	if err := repo.SaveItem(ctx, &reuse); err != nil {
+		t.Fatalf("SaveItem com código de item expurgado: %v", err)
+	}
+	if page, _ := repo.ListMovements(ctx, reuse.ID, item.ListFilter{}); page.Total != 0 {
+		t.Fatalf("o novo item herdou %d movimentações do item expurgado", page.Total)
+	}
+}
+
+func testBatch(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	existing := Seed(t, repo, item.Item{Code: "AAA", Title: "Caneta", Price: 2})[0]
+
+	newItem := func(code string) *item.Item {
+		return &item.Item{Code: code, Title: "Item " + code, Status: item.StatusActive, CreatedAt: baseTime, UpdatedAt: baseTime}
+	}
+	wantErrs := func(op string, errs []error, want ...error) {
+		t.Helper()
+		if len(errs) != len(want) {
+			t.Fatalf("%s: %d erros, esperado %d", op, len(errs), len(want))
+		}
+		for i := range want {
+			if (want[i] == nil) != (errs[i] == nil) || (want[i] != nil && !errors.Is(errs[i], want[i])) {
+				t.Fatalf("%s: linha %d: esperado %v, obtido %v", op, i, want[i], errs[i])
+			}
+		}
+	}
+
+	// Atômico com um código já usado: nada é gravado
+	rolledBack := []*item.Item{newItem("BBB"), newItem("AAA")}
+	errs, err := repo.SaveItems(ctx, rolledBack, true)
+	if err != nil {
+		t.Fatalf("SaveItems atômico: %v", err)
+	}
+	wantErrs("SaveItems atômico", errs, nil, domainerr.ErrAlreadyExists)
+	if _, err := repo.FindByCode(ctx, "BBB"); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("SaveItems atômico que falhou gravou BBB: %v", err)
+	}
+	for _, it := range rolledBack {
+		if it.ID != 0 || it.Version != 0 {
+			t.Fatalf("item %s de lote desfeito ficou com ID %d e versão %d; esperado 0 e 0", it.Code, it.ID, it.Version)
+		}
+	}
+
+	// Best-effort: grava as linhas válidas, inclusive com código repetido no próprio lote
+	batch := []*item.Item{newItem("BBB"), newItem("AAA"), newItem("CCC"), newItem("BBB")}
+	errs, err = repo.SaveItems(ctx, batch, false)
+	if err != nil {
+		t.Fatalf("SaveItems best-effort: %v", err)
+	}
+	wantErrs("SaveItems best-effort", errs, nil, domainerr.ErrAlreadyExists, nil, domainerr.ErrAlreadyExists)
+	for _, it := range []*item.Item{batch[0], batch[2]} {
+		stored, err := repo.FindByID(ctx, it.ID)
+		if err != nil || stored.Code != it.Code || stored.Version != 1 || it.ID <= existing.ID {
+			t.Fatalf("item %s gravado em lote = %+v (ID %d), %v", it.Code, stored, it.ID, err)
+		}
+	}
+	b, c := *batch[0], *batch[2]
+
+	// Um lote atômico de várias linhas dá certo por inteiro
+	errs, err = repo.SaveItems(ctx, []*item.Item{newItem("DDD"), newItem("EEE")}, true)
+	if err != nil || failedRows(errs) != 0 {
+		t.Fatalf("SaveItems atômico válido: %v, %v", errs, err)
+	}
+
+	found, err := repo.FindByIDs(ctx, []int{existing.ID, c.ID, 999})
+	if err != nil || len(found) != 2 {
+		t.Fatalf("FindByIDs = %+v, %v; esperado 2 itens", found, err)
+	}
+	found, err = repo.FindByCodes(ctx, []string{existing.Code, c.Code, "ZZZ"})
+	if err != nil || len(found) != 2 {
+		t.Fatalf("FindByCodes = %+v, %v; esperado 2 itens", found, err)
+	}
+
+	// PatchItems atômico com uma versão antiga: nenhuma alteração é gravada
+	priceB, stale := b, existing
+	priceB.Price = 9
+	stale.Version = 99
+	errs, err = repo.PatchItems(ctx, []item.ItemChange{{Item: &priceB, Fields: []string{"price"}}, {Item: &stale, Fields: []string{"title"}}}, true)
+	if err != nil {
+		t.Fatalf("PatchItems atômico: %v", err)
+	}
+	wantErrs("PatchItems atômico", errs, nil, domainerr.ErrPreconditionFailed)
+	if got, _ := repo.FindByID(ctx, b.ID); got.Price != b.Price || got.Version != b.Version {
+		t.Fatalf("PatchItems atômico que falhou alterou %s: %+v", b.Code, got)
+	}
+
+	// PatchItems best-effort: as linhas válidas são gravadas e recebem o item armazenado
+	priceB, renameC := b, c
+	priceB.Price = 9
+	renameC.Code = "AAA"
+	missing := item.Item{ID: 999, Title: "x"}
+	errs, err = repo.PatchItems(ctx, []item.ItemChange{
+		{Item: &priceB, Fields: []string{"price"}},
+		{Item: &renameC, Fields: []string{"code"}},
+		{Item: &missing, Fields: []string{"title"}},
+	}, false)
+	if err != nil {
+		t.Fatalf("PatchItems best-effort: %v", err)
+	}
+	wantErrs("PatchItems best-effort", errs, nil, domainerr.ErrAlreadyExists, domainerr.ErrNotFound)
+	if priceB.Price != 9 || priceB.Version != b.Version+1 || !priceB.CreatedAt.Equal(b.CreatedAt) {
+		t.Fatalf("item devolvido por PatchItems = %+v", priceB)
+	}
+
+	// DeleteItems atômico com um item inexistente: nada vai para a lixeira
+	errs, err = repo.DeleteItems(ctx, []item.ItemRef{{ID: c.ID}, {ID: 999}}, true)
+	if err != nil {
+		t.Fatalf("DeleteItems atômico: %v", err)
+	}
+	wantErrs("DeleteItems atômico", errs, nil, domainerr.ErrNotFound)
+	if _, err := repo.FindByID(ctx, c.ID); err != nil {
+		t.Fatalf("DeleteItems atômico que falhou excluiu %s: %v", c.Code, err)
+	}
+
+	// DeleteItems best-effort respeita a versão de cada linha
+	errs, err = repo.DeleteItems(ctx, []item.ItemRef{{ID: c.ID, Version: c.Version}, {ID: existing.ID, Version: 99}}, false)
+	if err != nil {
+		t.Fatalf("DeleteItems best-effort: %v", err)
+	}
+	wantErrs("DeleteItems best-effort", errs, nil, domainerr.ErrPreconditionFailed)
+	if trash, _ := repo.ListItems(ctx, item.ListFilter{Trash: true}); trash.Total != 1 || trash.Items[0].ID != c.ID {
+		t.Fatalf("lixeira depois de DeleteItems = %+v; esperado apenas %s", trash.Items, c.Code)
+	}
+}
+
+// failedRows conta as linhas de um lote que falharam.
+func failedRows(errs []error) int {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func testTimestamps(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	it := Seed(t, repo, item.Item{Code: "ITEM-001", Title: "Caneta", Stock: 10})[0]
+
+	if !it.CreatedAt.Equal(baseTime) || !it.UpdatedAt.Equal(baseTime) {
+		t.Fatalf("datas salvas = %s / %s, esperado %s", it.CreatedAt, it.UpdatedAt, baseTime)
+	}
+
+	later := baseTime.Add(time.Hour)
+	changed := it
+	changed.Title = "Caneta azul"
+	changed.CreatedAt = later // Tentativa de alterar created_at: deve ser ignorada
+	changed.UpdatedAt = later
+	if err := repo.UpdateItem(ctx, &changed); err != nil {
+		t.Fatalf("UpdateItem: %v", err)
+	}
+	if !changed.CreatedAt.Equal(baseTime) {
+		t.Fatalf("UpdateItem devolveu created_at %s, esperado %s", changed.CreatedAt, baseTime)
+	}
+	got, _ := repo.FindByID(ctx, it.ID)
+	if !got.CreatedAt.Equal(baseTime) || !got.UpdatedAt.Equal(later) {
+		t.Fatalf("depois de UpdateItem: created_at %s, updated_at %s; esperado %s e %s",
+			got.CreatedAt, got.UpdatedAt, baseTime, later)
+	}
+
+	adjustedAt := later.Add(time.Hour)
+	m := item.StockMovement{ItemID: it.ID, Type: item.MovementReceipt, Delta: 1, CreatedAt: adjustedAt}
+	adjusted, err := repo.AdjustStock(ctx, &m, false)
+	if err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+	if !adjusted.CreatedAt.Equal(baseTime) || !adjusted.UpdatedAt.Equal(adjustedAt) {
+		t.Fatalf("depois de AdjustStock: created_at %s, updated_at %s; esperado %s e %s",
+			adjusted.CreatedAt, adjusted.UpdatedAt, baseTime, adjustedAt)
+	}
+}
+
+func testStockMovements(t *testing.T, repo item.ItemRepositoryPort) {
+	ctx := context.Background()
+	it := Seed(t, repo, item.Item{Code: "ITEM-001", Title: "Caneta", Stock: 10})[0]
+
+	m := item.StockMovement{ItemID: it.ID, Type: item.MovementSale, Delta: -4, CreatedAt: baseTime}
+	adjusted, err := repo.AdjustStock(ctx, &m, false)
+	if err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+	if adjusted.Stock != 6 || adjusted.Version != 2 || m.StockAfter != 6 || m.ID == 0 {
+		t.Fatalf("AdjustStock = item %+v, movimentação %+v", adjusted, m)
+	}
+
+	over := item.StockMovement{ItemID: it.ID, Type: item.MovementSale, Delta: -7, CreatedAt: baseTime}
+	if _, err := repo.AdjustStock(ctx, &over, false); !errors.Is(err, domainerr.ErrConflict) {
+		t.Fatalf("AdjustStock sem saldo: esperado ErrConflict, obtido %v", err)
+	}
+	if got, _ := repo.FindByID(ctx, it.ID); got.Stock != 6 || got.Version != 2 {
+		t.Fatalf("AdjustStock recusado alterou o item: %+v", got)
+	}
+	if negative, err := repo.AdjustStock(ctx, &over, true); err != nil || negative.Stock != -1 {
+		t.Fatalf("AdjustStock com allowNegative = %+v, %v", negative, err)
+	}
+
+	page, err := repo.ListMovements(ctx, it.ID, item.ListFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("ListMovements: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 1 || page.Items[0].Delta != -7 || page.NextCursor == "" {
+		t.Fatalf("ListMovements = %+v; esperado a movimentação mais recente primeiro", page)
+	}
+	// Offset sem limite pula as primeiras e traz todas as demais
+	page, err = repo.ListMovements(ctx, it.ID, item.ListFilter{Offset: 1})
+	if err != nil {
+		t.Fatalf("ListMovements com offset: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 1 || page.Items[0].Delta != -4 || page.NextCursor != "" {
+		t.Fatalf("ListMovements com offset 1 e sem limite = %+v; esperado só a movimentação mais antiga", page)
+	}
+
+	// O ID da movimentação não é reaproveitado depois que o expurgo apaga as últimas
+	other := Seed(t, repo, item.Item{Code: "ITEM-002", Title: "Lápis"})[0]
+	purgedMove := item.StockMovement{ItemID: other.ID, Type: item.MovementReceipt, Delta: 1, CreatedAt: baseTime}
+	if _, err := repo.AdjustStock(ctx, &purgedMove, false); err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+	if err := repo.DeleteItem(ctx, other.ID, 0); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if n, err := repo.PurgeDeleted(ctx, time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Fatalf("PurgeDeleted = %d, %v; esperado 1", n, err)
+	}
+	next := item.StockMovement{ItemID: it.ID, Type: item.MovementReceipt, Delta: 1, CreatedAt: baseTime}
+	if _, err := repo.AdjustStock(ctx, &next, false); err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+	if next.ID <= purgedMove.ID {
+		t.Fatalf("movimentação depois do expurgo com ID %d; esperado maior que %d", next.ID, purgedMove.ID)
+	}
+}
+
+func testCanceledContext(t *testing.T, repo item.ItemRepositoryPort) {
+	it := Seed(t, repo, item.Item{Code: "ITEM-001", Title: "Caneta", Stock: 10})[0]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checks := map[string]error{}
+	checks["SaveItem"] = repo.SaveItem(ctx, &item.Item{Code: "NEW", Title: "x", Status: item.StatusActive, CreatedAt: baseTime, UpdatedAt: baseTime})
+	_, checks["ListItems"] = repo.ListItems(ctx, item.ListFilter{})
+	checks["StreamItems"] = repo.StreamItems(ctx, item.ListFilter{}, func(item.Item) error { return nil })
+	_, checks["FindByID"] = repo.FindByID(ctx, it.ID)
+	changed := it
+	changed.Title = "alterado"
+	checks["UpdateItem"] = repo.UpdateItem(ctx, &changed)
+	checks["DeleteItem"] = repo.DeleteItem(ctx, it.ID, 0)
+	_, checks["SaveItems"] = repo.SaveItems(ctx, []*item.Item{{Code: "NEW", Title: "x", Status: item.StatusActive, CreatedAt: baseTime, UpdatedAt: baseTime}}, false)
+	_, checks["DeleteItems"] = repo.DeleteItems(ctx, []item.ItemRef{{ID: it.ID}}, false)
+	_, checks["AdjustStock"] = repo.AdjustStock(ctx, &item.StockMovement{ItemID: it.ID, Type: item.MovementReceipt, Delta: 1, CreatedAt: baseTime}, false)
+
+	for op, err := range checks {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s com contexto cancelado: esperado context.Canceled, obtido %v", op, err)
+		}
+	}
+
+	// Nenhuma das operações pode ter tido efeito
+	bg := context.Background()
+	if got, err := repo.FindByID(bg, it.ID); err != nil || got != it {
+		t.Fatalf("item depois das operações canceladas = %+v, %v; esperado %+v", got, err, it)
+	}
+	if _, err := repo.FindByCode(bg, "NEW"); !errors.Is(err, domainerr.ErrNotFound) {
+		t.Fatalf("SaveItem cancelado gravou o item: %v", err)
+	}
+}
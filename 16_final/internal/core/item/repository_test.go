@@ -0,0 +1,85 @@
+package item_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"api/internal/core/item"
+	"api/internal/core/item/itemtest"
+	"api/internal/platform/migrations"
+	sqlitesetup "api/internal/platform/sqlite"
+)
+
+// MySQLDSNEnv aponta para um MySQL de testes (ex: o contêiner do docker-compose).
+// Se estiver vazia, a suíte não roda contra o MySQL.
+const MySQLDSNEnv = "INVENTORY_TEST_MYSQL_DSN"
+
+func TestMemoryRepositoryConformance(t *testing.T) {
+	itemtest.RunConformance(t, func(t *testing.T) item.ItemRepositoryPort {
+		return item.NewMapRepository()
+	})
+}
+
+func TestSQLiteRepositoryConformance(t *testing.T) {
+	itemtest.RunConformance(t, func(t *testing.T) item.ItemRepositoryPort {
+		db, err := sqlitesetup.NewSQLiteSetup(":memory:")
+		if err != nil {
+			t.Fatalf("falha ao abrir o SQLite: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		migrate(t, db, "sqlite")
+		return item.NewSQLiteRepository(db)
+	})
+}
+
+/*
+TestMySQLRepositoryConformance roda a suíte contra um MySQL real, por exemplo:
+
+	INVENTORY_TEST_MYSQL_DSN='api_user:api_password@tcp(localhost:3306)/inventory?parseTime=true' go test ./internal/core/item/
+
+Atenção: apaga todos os itens e movimentações do banco apontado.
+*/
+func TestMySQLRepositoryConformance(t *testing.T) {
+	dsn := os.Getenv(MySQLDSNEnv)
+	if dsn == "" {
+		t.Skipf("defina %s para rodar a suíte contra o MySQL", MySQLDSNEnv)
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("falha ao abrir o MySQL: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("MySQL indisponível em %s: %v", MySQLDSNEnv, err)
+	}
+	migrate(t, db, "mysql")
+
+	itemtest.RunConformance(t, func(t *testing.T) item.ItemRepositoryPort {
+		// Cada caso começa com as tabelas vazias e o AUTO_INCREMENT zerado
+		for _, stmt := range []string{
+			`DELETE FROM stock_movements`,
+			`DELETE FROM items`,
+			`ALTER TABLE items AUTO_INCREMENT = 1`,
+		} {
+			if _, err := db.Exec(stmt); err != nil {
+				t.Fatalf("falha ao limpar o banco (%s): %v", stmt, err)
+			}
+		}
+		return item.NewMySqlRepository(db)
+	})
+}
+
+// migrate aplica as migrações do dialeto no banco de testes.
+func migrate(t *testing.T, db *sql.DB, dialect string) {
+	t.Helper()
+	m, err := migrations.New(db, dialect)
+	if err != nil {
+		t.Fatalf("falha ao carregar as migrações: %v", err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("falha ao aplicar as migrações: %v", err)
+	}
+}
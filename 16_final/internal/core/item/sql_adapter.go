@@ -0,0 +1,939 @@
+package item
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"api/internal/core/domainerr" // Erros de domínio (NotFound, AlreadyExists, ...)
+)
+
+/*
+sqlRepository é uma implementação da interface ItemRepositoryPort sobre `database/sql`.
+
+Ela é compartilhada pelos backends MySQL (NewMySqlRepository) e SQLite (NewSQLiteRepository):
+as queries são as mesmas, e as poucas diferenças entre os bancos ficam no sqlDialect.
+
+Todas as queries usam as variantes *Context de database/sql, então o prazo da
+requisição e o cancelamento pelo cliente interrompem a query no banco.
+*/
+type sqlRepository struct {
+	db      *sql.DB        // Conexão ativa com o banco de dados
+	read    func() *sql.DB // Conexão para listagens (ex: réplica somente leitura); nil = db
+	dialect sqlDialect     // Diferenças de SQL e de erros do banco utilizado
+}
+
+/*
+reader retorna a conexão usada pelas listagens (ListItems, ListMovements).
+
+Leituras que participam de uma escrita (FindByID antes de atualizar, checagem de
+versão) continuam no primário, para não enxergarem dados atrasados da réplica.
+*/
+func (r *sqlRepository) reader() *sql.DB {
+	if r.read == nil {
+		return r.db
+	}
+	return r.read()
+}
+
+/*
+sqlDialect descreve o que muda de um banco para outro nas queries de sqlRepository.
+*/
+type sqlDialect struct {
+	likeEscape     string               // Sufixo do LIKE para usar `\` como caractere de escape
+	unlimited      string               // Valor de LIMIT que equivale a "sem limite" (para OFFSET sem LIMIT)
+	isDuplicateKey func(err error) bool // Indica se o erro do driver é uma violação de chave única
+}
+
+/*
+querier é satisfeito tanto por *sql.DB quanto por *sql.Tx, para que as mesmas queries
+rodem sozinhas (métodos de um item) ou dentro da transação de um lote (SaveItems, PatchItems, DeleteItems).
+*/
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+/*
+batchChunkSize é a quantidade máxima de linhas por INSERT de várias linhas ou por cláusula IN,
+para ficar abaixo do limite de placeholders dos bancos (65535 no MySQL, 32766 no SQLite).
+*/
+const batchChunkSize = 500
+
+/*
+SaveItem insere um novo item na tabela `items`.
+
+Campos:
+- code, title, description, price, stock, status, version (sempre 1), created_at, updated_at
+
+O ID é gerado pelo banco (AUTO_INCREMENT) e gravado em it.ID a partir do LastInsertId.
+
+Retorna:
+- domainerr.ErrAlreadyExists se violar uma chave única
+- Um erro, caso a inserção falhe.
+*/
+func (r *sqlRepository) SaveItem(ctx context.Context, it *Item) error {
+	return r.saveItem(ctx, r.db, it)
+}
+
+// saveItem é o SaveItem sobre q (conexão ou transação).
+func (r *sqlRepository) saveItem(ctx context.Context, q querier, it *Item) error {
+	it.Version = 1 // Todo item nasce na versão 1
+
+	query := `
+		INSERT INTO items 
+		(code, title, description, price, stock, status, version, created_at, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	res, err := q.ExecContext(ctx, query,
+		it.Code, it.Title, it.Description,
+		it.Price, it.Stock, it.Status, it.Version,
+		it.CreatedAt, it.UpdatedAt,
+	)
+	if r.dialect.isDuplicateKey(err) {
+		return errDuplicateCode(it.Code)
+	}
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	it.ID = int(id)
+	return nil
+}
+
+/*
+SaveItems insere vários itens novos em uma única transação, usando INSERTs de várias linhas.
+
+Passos:
+ 1. Marca como erro os itens com código repetido no lote ou já usado no banco
+    (inclusive por itens na lixeira), consultando os códigos em blocos (`code IN (...)`).
+ 2. Em atomic, se algum item falhou, desfaz a transação sem inserir nada.
+ 3. Insere os demais em blocos de até batchChunkSize linhas por INSERT e lê os IDs
+    gerados pelo código, que é único (o LastInsertId de um INSERT de várias linhas
+    não garante IDs consecutivos no MySQL).
+ 4. Se outra requisição gravar um dos códigos entre os passos 1 e 3, o lote atômico
+    falha com domainerr.ErrAlreadyExists; no modo best-effort, os itens restantes são
+    inseridos um a um, para descobrir qual deles conflitou.
+
+Se a transação for desfeita, o ID e a versão dos itens voltam a zero.
+
+Retorna:
+- Um erro por item (nil = gravado) e, em caso de falha do banco, o erro do lote.
+*/
+func (r *sqlRepository) SaveItems(ctx context.Context, its []*Item, atomic bool) ([]error, error) {
+	errs := make([]error, len(its))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // Sem efeito após o Commit
+
+	committed := false
+	defer func() {
+		if !committed {
+			unsave(its) // A transação foi desfeita: nenhum item ficou gravado
+		}
+	}()
+
+	if err := markTakenCodes(ctx, tx, its, errs); err != nil {
+		return nil, err
+	}
+	if atomic && failed(errs) {
+		return errs, nil
+	}
+
+	pending := make([]*Item, 0, len(its))
+	for i, it := range its {
+		if errs[i] == nil {
+			pending = append(pending, it)
+		}
+	}
+
+	err = insertItems(ctx, tx, pending)
+	switch {
+	case r.dialect.isDuplicateKey(err) && atomic:
+		return nil, domainerr.AlreadyExistsf("outro item passou a usar um dos códigos do lote durante a gravação")
+	case r.dialect.isDuplicateKey(err):
+		// Os blocos já inseridos têm ID; os demais são inseridos um a um
+		for i, it := range its {
+			if errs[i] != nil || it.ID != 0 {
+				continue
+			}
+			if err := r.saveItem(ctx, tx, it); err != nil {
+				if !isDomainError(err) {
+					return nil, err
+				}
+				errs[i] = err
+			}
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	committed = true
+	return errs, nil
+}
+
+/*
+markTakenCodes preenche errs[i] para os itens cujo código se repete no lote
+ou já pertence a outro item no banco.
+*/
+func markTakenCodes(ctx context.Context, q querier, its []*Item, errs []error) error {
+	seen := make(map[string]bool, len(its))
+	for i, it := range its {
+		if seen[it.Code] {
+			errs[i] = errDuplicateCode(it.Code)
+		}
+		seen[it.Code] = true
+	}
+
+	taken := make(map[string]bool)
+	codes := make([]any, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	for start := 0; start < len(codes); start += batchChunkSize {
+		chunk := codes[start:min(start+batchChunkSize, len(codes))]
+		rows, err := q.QueryContext(ctx, `SELECT code FROM items WHERE code IN (`+placeholders(len(chunk))+`)`, chunk...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var code string
+			if err := rows.Scan(&code); err != nil {
+				rows.Close()
+				return err
+			}
+			taken[code] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	for i, it := range its {
+		if taken[it.Code] {
+			errs[i] = errDuplicateCode(it.Code)
+		}
+	}
+	return nil
+}
+
+/*
+insertItems insere os itens em blocos de até batchChunkSize linhas por INSERT
+e preenche ID e versão de cada um. Os blocos que já foram inseridos mantêm o ID,
+mesmo que um bloco seguinte falhe.
+*/
+func insertItems(ctx context.Context, q querier, its []*Item) error {
+	for start := 0; start < len(its); start += batchChunkSize {
+		chunk := its[start:min(start+batchChunkSize, len(its))]
+
+		values := make([]string, 0, len(chunk))
+		args := make([]any, 0, len(chunk)*8)
+		codes := make([]any, 0, len(chunk))
+		for _, it := range chunk {
+			values = append(values, "(?, ?, ?, ?, ?, ?, 1, ?, ?)")
+			args = append(args,
+				it.Code, it.Title, it.Description,
+				it.Price, it.Stock, it.Status,
+				it.CreatedAt, it.UpdatedAt,
+			)
+			codes = append(codes, it.Code)
+		}
+		query := `
+			INSERT INTO items 
+			(code, title, description, price, stock, status, version, created_at, updated_at) 
+			VALUES ` + strings.Join(values, ", ")
+		if _, err := q.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		ids := make(map[string]int, len(chunk))
+		rows, err := q.QueryContext(ctx, `SELECT id, code FROM items WHERE code IN (`+placeholders(len(codes))+`)`, codes...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var (
+				id   int
+				code string
+			)
+			if err := rows.Scan(&id, &code); err != nil {
+				rows.Close()
+				return err
+			}
+			ids[code] = id
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, it := range chunk {
+			it.ID, it.Version = ids[it.Code], 1
+		}
+	}
+	return nil
+}
+
+/*
+ListItems busca os itens da tabela `items` que atendem ao filtro.
+
+Sem f.Trash, apenas os itens ativos (deleted_at IS NULL); com f.Trash, apenas os da lixeira.
+Os filtros, a ordenação e a paginação são aplicados no próprio SQL
+(WHERE / ORDER BY / LIMIT / OFFSET), e o total é obtido com um COUNT(*)
+usando o mesmo WHERE.
+
+Retorna:
+- A página de itens, com total e próximo cursor
+- Um erro, caso alguma query falhe
+*/
+func (r *sqlRepository) ListItems(ctx context.Context, f ListFilter) (Page, error) {
+	db := r.reader()
+	where, args := r.whereClause(f)
+
+	var total int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM items`+where, args...).Scan(&total); err != nil {
+		return Page{}, err
+	}
+
+	var items []Item
+	err := r.queryItems(ctx, db, f, func(it Item) error {
+		items = append(items, it)
+		return nil
+	})
+	if err != nil {
+		return Page{}, err
+	}
+	return NewPage(items, f, total), nil
+}
+
+/*
+StreamItems percorre os itens que atendem ao filtro, na ordem pedida, chamando fn para cada um.
+
+As linhas são lidas do cursor do banco à medida que fn as consome (o driver do MySQL
+não carrega o resultado inteiro), então a memória usada não depende do tamanho da tabela.
+A conexão fica ocupada até o fim da leitura; como ListItems, usa a réplica de leitura.
+*/
+func (r *sqlRepository) StreamItems(ctx context.Context, f ListFilter, fn func(Item) error) error {
+	return r.queryItems(ctx, r.reader(), f, fn)
+}
+
+/*
+queryItems executa o SELECT da listagem (WHERE / ORDER BY / LIMIT / OFFSET) em db e chama fn
+para cada linha, parando no primeiro erro de fn ou do banco.
+
+Recebe o banco já escolhido para que ListItems faça o COUNT(*) e o SELECT na mesma réplica.
+*/
+func (r *sqlRepository) queryItems(ctx context.Context, db *sql.DB, f ListFilter, fn func(Item) error) error {
+	where, args := r.whereClause(f)
+
+	// O campo de ordenação já foi validado contra SortFields (ListFilter.Validate)
+	query := `
+		SELECT ` + itemColumns + ` 
+		FROM items` + where + ` ORDER BY ` + f.sortField()
+	if f.SortDesc {
+		query += ` DESC`
+	}
+	query += `, id`
+	page, pageArgs := r.pageClause(f)
+	query += page
+	args = append(args, pageArgs...)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		it, err := scanItem(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(it); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+/*
+whereClause monta o WHERE (com placeholders) correspondente ao filtro.
+
+Retorna a cláusula (sempre com a condição da lixeira) e os argumentos na mesma ordem dos `?`.
+*/
+func (r *sqlRepository) whereClause(f ListFilter) (string, []any) {
+	var (
+		conds = []string{"deleted_at IS NULL"}
+		args  []any
+	)
+	if f.Trash {
+		conds[0] = "deleted_at IS NOT NULL"
+	}
+	if f.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, f.Status)
+	}
+	if f.MinPrice != nil {
+		conds = append(conds, "price >= ?")
+		args = append(args, *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		conds = append(conds, "price <= ?")
+		args = append(args, *f.MaxPrice)
+	}
+	if f.MinStock != nil {
+		conds = append(conds, "stock >= ?")
+		args = append(args, *f.MinStock)
+	}
+	if f.MaxStock != nil {
+		conds = append(conds, "stock <= ?")
+		args = append(args, *f.MaxStock)
+	}
+	if f.Search != "" {
+		like := "%" + likeEscaper.Replace(f.Search) + "%"
+		esc := r.dialect.likeEscape
+		conds = append(conds, "(title LIKE ?"+esc+" OR description LIKE ?"+esc+")")
+		args = append(args, like, like)
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// likeEscaper escapa os curingas do LIKE para que o texto livre seja buscado literalmente.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+/*
+FindByID busca um único item ativo (fora da lixeira) da tabela `items` pelo ID.
+
+Retorna:
+- O item encontrado
+- domainerr.ErrNotFound caso nenhuma linha seja encontrada, ou o erro da query
+*/
+func (r *sqlRepository) FindByID(ctx context.Context, id int) (Item, error) {
+	query := `
+		SELECT ` + itemColumns + ` 
+		FROM items WHERE id=? AND deleted_at IS NULL`
+	it, err := scanItem(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Item{}, domainerr.NotFoundf("item com ID %d não existe", id)
+	}
+	return it, err
+}
+
+/*
+FindByIDs busca os itens ativos com os IDs informados, em blocos de `id IN (...)`.
+
+IDs inexistentes ou na lixeira são omitidos; a ordem do resultado não é garantida.
+Como alimenta escritas (PATCH em lote), lê do primário, e não da réplica.
+*/
+func (r *sqlRepository) FindByIDs(ctx context.Context, ids []int) ([]Item, error) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return r.findActiveIn(ctx, "id", args)
+}
+
+/*
+FindByCodes busca os itens ativos com os códigos informados, em blocos de `code IN (...)`.
+
+Segue as regras de FindByIDs; é usado pela importação de planilhas para decidir,
+em uma consulta por bloco, quais linhas criam e quais atualizam itens.
+*/
+func (r *sqlRepository) FindByCodes(ctx context.Context, codes []string) ([]Item, error) {
+	args := make([]any, len(codes))
+	for i, code := range codes {
+		args[i] = code
+	}
+	return r.findActiveIn(ctx, "code", args)
+}
+
+/*
+FindTrashedByCodes busca os itens na lixeira com os códigos informados, em blocos de `code IN (...)`.
+
+Segue as regras de FindByIDs; é usado pelo dry-run da importação para prever os códigos
+reservados por itens na lixeira, que a gravação recusaria com domainerr.ErrAlreadyExists.
+*/
+func (r *sqlRepository) FindTrashedByCodes(ctx context.Context, codes []string) ([]Item, error) {
+	args := make([]any, len(codes))
+	for i, code := range codes {
+		args[i] = code
+	}
+	return r.findIn(ctx, "deleted_at IS NOT NULL", "code", args)
+}
+
+// findActiveIn busca no primário os itens ativos cuja coluna está entre values, em blocos de batchChunkSize.
+func (r *sqlRepository) findActiveIn(ctx context.Context, column string, values []any) ([]Item, error) {
+	return r.findIn(ctx, "deleted_at IS NULL", column, values)
+}
+
+// findIn busca no primário os itens que atendem a cond e cuja coluna está entre values, em blocos de batchChunkSize.
+func (r *sqlRepository) findIn(ctx context.Context, cond, column string, values []any) ([]Item, error) {
+	var its []Item
+	for start := 0; start < len(values); start += batchChunkSize {
+		chunk := values[start:min(start+batchChunkSize, len(values))]
+
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT `+itemColumns+` 
+			FROM items WHERE `+cond+` AND `+column+` IN (`+placeholders(len(chunk))+`)`, chunk...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			it, err := scanItem(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			its = append(its, it)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return its, nil
+}
+
+/*
+FindByCode busca um único item ativo (fora da lixeira) da tabela `items` pelo código (SKU).
+
+Retorna:
+- O item encontrado
+- domainerr.ErrNotFound caso nenhuma linha seja encontrada, ou o erro da query
+*/
+func (r *sqlRepository) FindByCode(ctx context.Context, code string) (Item, error) {
+	query := `
+		SELECT ` + itemColumns + ` 
+		FROM items WHERE code=? AND deleted_at IS NULL LIMIT 1`
+	it, err := scanItem(r.db.QueryRowContext(ctx, query, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Item{}, domainerr.NotFoundf("item com código %q não existe", code)
+	}
+	return it, err
+}
+
+/*
+itemColumns é a lista de colunas lidas de `items`, sempre na ordem esperada por scanItem.
+*/
+const itemColumns = `id, code, title, description, price, stock, status, version, created_at, updated_at, deleted_at`
+
+// scanner é satisfeito tanto por *sql.Row quanto por *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanItem lê uma linha de `items` (na ordem de itemColumns) para um Item.
+func scanItem(row scanner) (Item, error) {
+	var (
+		it        Item
+		deletedAt sql.NullTime
+	)
+	err := row.Scan(
+		&it.ID, &it.Code, &it.Title, &it.Description,
+		&it.Price, &it.Stock, &it.Status, &it.Version,
+		&it.CreatedAt, &it.UpdatedAt, &deletedAt,
+	)
+	if deletedAt.Valid {
+		it.DeletedAt = &deletedAt.Time
+	}
+	return it, err
+}
+
+/*
+UpdateItem atualiza os dados de um item existente baseado no ID.
+
+Grava todas as colunas de PatchFields; ver PatchItem para as regras de versão e o retorno.
+*/
+func (r *sqlRepository) UpdateItem(ctx context.Context, it *Item) error {
+	return r.PatchItem(ctx, it, PatchFields)
+}
+
+/*
+PatchItem grava apenas as colunas listadas em fields (além de updated_at e version).
+
+Controle de concorrência otimista:
+  - Se it.Version for maior que zero, o UPDATE só acontece se a versão no banco
+    ainda for a mesma (`WHERE id=? AND version=?`);
+  - A cada atualização a versão é incrementada, e it recebe o item como ficou no banco.
+
+Itens na lixeira não são alterados (domainerr.ErrNotFound).
+Os nomes de fields são validados contra PatchFields antes de entrarem no SET,
+então nunca vêm diretamente da requisição.
+
+Retorna:
+- domainerr.ErrValidation se algum campo não puder ser alterado
+- domainerr.ErrNotFound se o item não existir
+- domainerr.ErrPreconditionFailed se a versão informada estiver desatualizada
+- domainerr.ErrAlreadyExists se o novo código já pertencer a outro item
+- Um erro caso o update falhe.
+*/
+func (r *sqlRepository) PatchItem(ctx context.Context, it *Item, fields []string) error {
+	return r.patchItem(ctx, r.db, it, fields)
+}
+
+// patchItem é o PatchItem sobre q (conexão ou transação).
+func (r *sqlRepository) patchItem(ctx context.Context, q querier, it *Item, fields []string) error {
+	if err := validPatchFields(fields); err != nil {
+		return err
+	}
+
+	sets := make([]string, 0, len(fields)+2)
+	args := make([]any, 0, len(fields)+4)
+	for _, f := range fields {
+		sets = append(sets, f+"=?")
+		args = append(args, it.fieldValue(f))
+	}
+	sets = append(sets, "updated_at=?", "version=version+1")
+	args = append(args, it.UpdatedAt, it.ID, it.Version, it.Version)
+
+	query := `UPDATE items SET ` + strings.Join(sets, ", ") + ` WHERE id=? AND deleted_at IS NULL AND (?=0 OR version=?)`
+	res, err := q.ExecContext(ctx, query, args...)
+	if r.dialect.isDuplicateKey(err) {
+		return errDuplicateCode(it.Code)
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkAffected(ctx, q, res, it.ID, it.Version); err != nil {
+		return err
+	}
+
+	// Relê a linha para devolver o item como ficou (nova versão, created_at original)
+	stored, err := scanItem(q.QueryRowContext(ctx, `SELECT `+itemColumns+` FROM items WHERE id=?`, it.ID))
+	if err != nil {
+		return err
+	}
+	*it = stored
+	return nil
+}
+
+/*
+DeleteItem move um item para a lixeira (exclusão lógica), preenchendo deleted_at.
+
+A linha continua na tabela (e o código continua reservado) até ser restaurada com
+RestoreItem ou removida definitivamente por PurgeDeleted. A versão é incrementada.
+Se version for maior que zero, a exclusão só acontece se o item ainda estiver nessa versão.
+
+Retorna:
+- domainerr.ErrNotFound (inclusive se o item já estiver na lixeira) ou domainerr.ErrPreconditionFailed, conforme o caso
+- Um erro, caso a atualização falhe.
+*/
+func (r *sqlRepository) DeleteItem(ctx context.Context, id, version int) error {
+	return deleteItem(ctx, r.db, id, version, time.Now().UTC().Truncate(time.Second))
+}
+
+/*
+deleteItem é o DeleteItem sobre q (conexão ou transação), gravando now em deleted_at e updated_at.
+
+updated_at vai explícito para não depender do ON UPDATE do MySQL, que usaria o relógio do banco.
+*/
+func deleteItem(ctx context.Context, q querier, id, version int, now time.Time) error {
+	query := `
+		UPDATE items SET deleted_at=?, updated_at=?, version=version+1 
+		WHERE id=? AND deleted_at IS NULL AND (?=0 OR version=?)`
+	res, err := q.ExecContext(ctx, query, now, now, id, version, version)
+	if err != nil {
+		return err
+	}
+	return checkAffected(ctx, q, res, id, version)
+}
+
+/*
+PatchItems aplica PatchItem a cada alteração, na ordem, dentro de uma única transação.
+
+Falhas de uma linha (item inexistente, versão desatualizada, código duplicado) não
+interrompem o lote: são devolvidas no erro da linha, e a transação continua válida.
+Em atomic, qualquer falha desfaz a transação inteira; no modo best-effort, as linhas
+que deram certo são confirmadas. Uma falha do banco interrompe o lote sem gravar nada.
+*/
+func (r *sqlRepository) PatchItems(ctx context.Context, changes []ItemChange, atomic bool) ([]error, error) {
+	return r.inBatch(ctx, len(changes), atomic, func(q querier, i int) error {
+		return r.patchItem(ctx, q, changes[i].Item, changes[i].Fields)
+	})
+}
+
+/*
+DeleteItems move os itens para a lixeira, na ordem, dentro de uma única transação,
+com as mesmas regras de PatchItems. Todos recebem o mesmo deleted_at.
+*/
+func (r *sqlRepository) DeleteItems(ctx context.Context, refs []ItemRef, atomic bool) ([]error, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	return r.inBatch(ctx, len(refs), atomic, func(q querier, i int) error {
+		return deleteItem(ctx, q, refs[i].ID, refs[i].Version, now)
+	})
+}
+
+/*
+inBatch executa apply para as n linhas de um lote dentro de uma transação.
+
+Erros de domínio ficam no erro da linha; qualquer outro erro (banco, contexto) interrompe
+o lote e desfaz a transação, já que o MySQL pode ter desfeito a transação por conta própria
+(ex: deadlock) e as linhas seguintes não estariam mais protegidas por ela.
+*/
+func (r *sqlRepository) inBatch(ctx context.Context, n int, atomic bool, apply func(q querier, i int) error) ([]error, error) {
+	errs := make([]error, n)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // Sem efeito após o Commit
+
+	for i := range errs {
+		if err := apply(tx, i); err != nil {
+			if !isDomainError(err) {
+				return nil, err
+			}
+			errs[i] = err
+		}
+	}
+	if atomic && failed(errs) {
+		return errs, nil
+	}
+	return errs, tx.Commit()
+}
+
+/*
+RestoreItem tira um item da lixeira (deleted_at volta a NULL), atualiza updated_at e incrementa a versão.
+
+Se version for maior que zero, a restauração só acontece se o item ainda estiver nessa versão.
+
+Retorna:
+  - O item restaurado, como ficou no banco
+  - domainerr.ErrNotFound se o item não estiver na lixeira, domainerr.ErrPreconditionFailed
+    se a versão não conferir, ou o erro do banco
+*/
+func (r *sqlRepository) RestoreItem(ctx context.Context, id, version int) (Item, error) {
+	query := `
+		UPDATE items SET deleted_at=NULL, updated_at=?, version=version+1 
+		WHERE id=? AND deleted_at IS NOT NULL AND (?=0 OR version=?)`
+	now := time.Now().UTC().Truncate(time.Second)
+	res, err := r.db.ExecContext(ctx, query, now, id, version, version)
+	if err != nil {
+		return Item{}, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return Item{}, err
+	} else if n == 0 {
+		var current int
+		err := r.db.QueryRowContext(ctx, `SELECT version FROM items WHERE id=? AND deleted_at IS NOT NULL`, id).Scan(&current)
+		if errors.Is(err, sql.ErrNoRows) {
+			return Item{}, errNotInTrash(id)
+		}
+		if err != nil {
+			return Item{}, err
+		}
+		return Item{}, errVersionConflict(id, version, current)
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+/*
+PurgeDeleted remove definitivamente os itens que estão na lixeira desde antes de before.
+
+As movimentações de estoque desses itens são removidas na mesma transação,
+para que o histórico não fique apontando para itens inexistentes.
+
+Retorna:
+- A quantidade de itens removidos, ou o erro do banco
+*/
+func (r *sqlRepository) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	before = before.UTC() // Mesmo fuso de deleted_at, para a comparação ser correta também no SQLite (texto)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() // Sem efeito após o Commit
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM stock_movements 
+		WHERE item_id IN (SELECT id FROM items WHERE deleted_at IS NOT NULL AND deleted_at < ?)`, before); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM items WHERE deleted_at IS NOT NULL AND deleted_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), tx.Commit()
+}
+
+/*
+checkAffected interpreta um UPDATE/DELETE que não afetou nenhuma linha.
+
+Nesse caso, consulta a versão atual do item para diferenciar
+"item não existe ou está na lixeira" (domainerr.ErrNotFound) de "versão desatualizada" (domainerr.ErrPreconditionFailed).
+*/
+func checkAffected(ctx context.Context, q querier, res sql.Result, id, version int) error {
+	n, err := res.RowsAffected()
+	if err != nil || n > 0 {
+		return err
+	}
+
+	var current int
+	err = q.QueryRowContext(ctx, `SELECT version FROM items WHERE id=? AND deleted_at IS NULL`, id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domainerr.NotFoundf("item com ID %d não existe", id)
+	}
+	if err != nil {
+		return err
+	}
+	return errVersionConflict(id, version, current)
+}
+
+/*
+AdjustStock aplica o delta ao estoque e registra a movimentação em uma única transação.
+
+Passos:
+ 1. `UPDATE items SET stock = stock + ?` condicionado ao saldo não ficar negativo
+    (a menos que allowNegative seja true), o que torna a operação atômica mesmo
+    com requisições concorrentes.
+ 2. Se nenhuma linha for afetada, descobre se o item não existe ou se faltou estoque.
+ 3. Lê o saldo resultante e insere a linha em `stock_movements`.
+
+Retorna:
+- O item atualizado
+- domainerr.ErrNotFound (item inexistente ou na lixeira), erro de estoque insuficiente ou o erro do banco
+*/
+func (r *sqlRepository) AdjustStock(ctx context.Context, m *StockMovement, allowNegative bool) (Item, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Item{}, err
+	}
+	defer tx.Rollback() // Sem efeito após o Commit
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE items SET stock = stock + ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND deleted_at IS NULL AND (? OR stock + ? >= 0)`,
+		m.Delta, m.CreatedAt, m.ItemID, allowNegative, m.Delta,
+	)
+	if err != nil {
+		return Item{}, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return Item{}, err
+	} else if n == 0 {
+		var stock int
+		err := tx.QueryRowContext(ctx, `SELECT stock FROM items WHERE id = ? AND deleted_at IS NULL`, m.ItemID).Scan(&stock)
+		if errors.Is(err, sql.ErrNoRows) {
+			return Item{}, domainerr.NotFoundf("item com ID %d não existe", m.ItemID)
+		}
+		if err != nil {
+			return Item{}, err
+		}
+		return Item{}, errInsufficientStock(m.ItemID, stock, m.Delta)
+	}
+
+	it, err := scanItem(tx.QueryRowContext(ctx, `
+		SELECT `+itemColumns+` 
+		FROM items WHERE id=?`, m.ItemID))
+	if err != nil {
+		return Item{}, err
+	}
+
+	m.StockAfter = it.Stock
+	res, err = tx.ExecContext(ctx, `
+		INSERT INTO stock_movements 
+		(item_id, type, delta, stock_after, reason, actor, reference, created_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.ItemID, m.Type, m.Delta, m.StockAfter,
+		m.Reason, m.Actor, m.Reference, m.CreatedAt,
+	)
+	if err != nil {
+		return Item{}, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Item{}, err
+	}
+	m.ID = int(id)
+
+	return it, tx.Commit()
+}
+
+/*
+pageClause monta o LIMIT / OFFSET da página pedida em f, com os argumentos na ordem dos `?`.
+
+Sem limite e sem offset não há cláusula. Nem MySQL nem SQLite aceitam OFFSET sem LIMIT,
+então um offset sem limite usa o "sem limite" do dialeto.
+*/
+func (r *sqlRepository) pageClause(f ListFilter) (string, []any) {
+	switch {
+	case f.Limit > 0:
+		return ` LIMIT ? OFFSET ?`, []any{f.Limit, f.Offset}
+	case f.Offset > 0:
+		return ` LIMIT ` + r.dialect.unlimited + ` OFFSET ?`, []any{f.Offset}
+	default:
+		return "", nil
+	}
+}
+
+/*
+ListMovements busca o histórico de estoque do item na tabela `stock_movements`,
+do mais recente para o mais antigo.
+
+Retorna:
+- A página de movimentações, com total e próximo cursor
+- domainerr.ErrNotFound caso o item não exista, ou o erro da query
+*/
+func (r *sqlRepository) ListMovements(ctx context.Context, itemID int, f ListFilter) (MovementPage, error) {
+	if _, err := r.FindByID(ctx, itemID); err != nil {
+		return MovementPage{}, err
+	}
+
+	db := r.reader()
+	var total int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM stock_movements WHERE item_id = ?`, itemID).Scan(&total); err != nil {
+		return MovementPage{}, err
+	}
+
+	query := `
+		SELECT id, item_id, type, delta, stock_after, reason, actor, reference, created_at 
+		FROM stock_movements WHERE item_id = ? 
+		ORDER BY created_at DESC, id DESC`
+	page, pageArgs := r.pageClause(f)
+	query += page
+	args := append([]any{itemID}, pageArgs...)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return MovementPage{}, err
+	}
+	defer rows.Close()
+
+	var ms []StockMovement
+	for rows.Next() {
+		var m StockMovement
+		if err := rows.Scan(
+			&m.ID, &m.ItemID, &m.Type, &m.Delta, &m.StockAfter,
+			&m.Reason, &m.Actor, &m.Reference, &m.CreatedAt,
+		); err != nil {
+			return MovementPage{}, err
+		}
+		ms = append(ms, m)
+	}
+	if err := rows.Err(); err != nil {
+		return MovementPage{}, err
+	}
+
+	return NewMovementPage(ms, f, total), nil
+}
@@ -0,0 +1,48 @@
+package item
+
+import (
+	"database/sql"
+	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+/*
+NewSQLiteRepository retorna uma nova instância do repositório SQLite.
+
+Usa as mesmas queries do repositório MySQL (ver sqlRepository), o que permite rodar
+a API completa sem um contêiner MySQL (desenvolvimento local e CI).
+
+Parâmetros:
+- db: conexão já aberta com o driver "sqlite" e com o schema criado (ver internal/platform/sqlite).
+
+Retorna:
+- A interface ItemRepositoryPort (abstração), com implementação SQLite concreta.
+*/
+func NewSQLiteRepository(db *sql.DB) ItemRepositoryPort {
+	return &sqlRepository{
+		db:      db,
+		dialect: sqliteDialect,
+	}
+}
+
+/*
+sqliteDialect contém as particularidades do SQLite:
+- o LIKE não tem caractere de escape padrão, então ele é declarado com ESCAPE;
+- LIMIT -1 significa "sem limite".
+*/
+var sqliteDialect = sqlDialect{
+	likeEscape:     ` ESCAPE '\'`,
+	unlimited:      "-1",
+	isDuplicateKey: isSQLiteDuplicateKey,
+}
+
+// isSQLiteDuplicateKey indica se o erro do driver é uma violação de UNIQUE ou PRIMARY KEY.
+func isSQLiteDuplicateKey(err error) bool {
+	var se *sqlite.Error
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || se.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
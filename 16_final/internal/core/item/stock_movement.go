@@ -0,0 +1,102 @@
+package item
+
+import (
+	"fmt"
+	"time"
+
+	"api/internal/core/domainerr"
+)
+
+/*
+MovementType identifica o motivo de negócio de uma movimentação de estoque.
+*/
+type MovementType string
+
+const (
+	MovementReceipt    MovementType = "receipt"    // Entrada de mercadoria (compra, recebimento)
+	MovementSale       MovementType = "sale"       // Saída por venda
+	MovementAdjustment MovementType = "adjustment" // Ajuste de inventário (contagem, perda, avaria)
+	MovementReturn     MovementType = "return"     // Devolução de cliente
+	MovementTransfer   MovementType = "transfer"   // Transferência entre depósitos
+)
+
+/*
+StockMovement representa uma linha do histórico (ledger) de estoque de um item.
+
+Cada alteração de Item.Stock feita via ajuste gera exatamente uma movimentação,
+registrando quanto mudou, o saldo resultante e quem/por que mudou.
+*/
+type StockMovement struct {
+	ID         int          `json:"id"`          // Identificador único da movimentação
+	ItemID     int          `json:"item_id"`     // Item movimentado
+	Type       MovementType `json:"type"`        // Tipo da movimentação (receipt, sale, ...)
+	Delta      int          `json:"delta"`       // Variação do estoque (positiva ou negativa)
+	StockAfter int          `json:"stock_after"` // Saldo do item logo após a movimentação
+	Reason     string       `json:"reason"`      // Motivo em texto livre
+	Actor      string       `json:"actor"`       // Quem realizou a movimentação
+	Reference  string       `json:"reference"`   // Documento de origem (pedido, nota fiscal, ...)
+	CreatedAt  time.Time    `json:"created_at"`  // Momento da movimentação
+}
+
+/*
+Validate verifica se a movimentação é coerente com o seu tipo.
+
+Regras:
+- O delta não pode ser zero;
+- receipt e return só podem somar ao estoque;
+- sale só pode subtrair do estoque;
+- adjustment e transfer aceitam os dois sentidos.
+
+Retorna domainerr.ValidationError apontando o campo inválido.
+*/
+func (m StockMovement) Validate() error {
+	if m.Delta == 0 {
+		return domainerr.Validation("delta", "delta da movimentação não pode ser 0")
+	}
+
+	switch m.Type {
+	case MovementReceipt, MovementReturn:
+		if m.Delta < 0 {
+			return domainerr.Validation("delta", fmt.Sprintf("movimentação %q exige delta positivo", m.Type))
+		}
+	case MovementSale:
+		if m.Delta > 0 {
+			return domainerr.Validation("delta", fmt.Sprintf("movimentação %q exige delta negativo", m.Type))
+		}
+	case MovementAdjustment, MovementTransfer:
+	default:
+		return domainerr.Validation("type", fmt.Sprintf("tipo de movimentação inválido %q", m.Type))
+	}
+	return nil
+}
+
+/*
+MovementPage é o envelope da listagem paginada do histórico de estoque,
+no mesmo formato de Page (itens, próximo cursor e total).
+*/
+type MovementPage struct {
+	Items      []StockMovement `json:"items"`
+	NextCursor string          `json:"next_cursor"`
+	Total      int             `json:"total"`
+}
+
+/*
+NewMovementPage monta o envelope de uma página do histórico, calculando o próximo cursor.
+
+Usa apenas Limit e Offset do filtro, com o mesmo formato de cursor da listagem de itens.
+*/
+func NewMovementPage(ms []StockMovement, f ListFilter, total int) MovementPage {
+	if ms == nil {
+		ms = []StockMovement{}
+	}
+	page := MovementPage{Items: ms, Total: total}
+	if next := f.Offset + len(ms); f.Limit > 0 && next < total {
+		page.NextCursor = EncodeCursor(next)
+	}
+	return page
+}
+
+// errInsufficientStock monta o erro de estoque insuficiente para o item.
+func errInsufficientStock(id, stock, delta int) error {
+	return domainerr.Conflictf("estoque insuficiente para o item %d (saldo %d, delta %d)", id, stock, delta)
+}
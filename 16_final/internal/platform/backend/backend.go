@@ -0,0 +1,90 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	auth "api/internal/core/auth"              // Pacote com as chaves de API e o repositório delas
+	item "api/internal/core/item"              // Pacote com o modelo e repositórios de Item
+	mysqlsetup "api/internal/platform/mysql"   // Configuração do cliente MySQL
+	sqlitesetup "api/internal/platform/sqlite" // Configuração do banco SQLite
+	"api/pkg/config"                           // Configuração tipada (arquivo + variáveis de ambiente)
+)
+
+/*
+Backend reúne o repositório de itens escolhido na configuração e os recursos que ele usa.
+
+É construído uma única vez por entrypoint (REST, gRPC e CLI), para que trocar de banco
+seja só uma questão de configuração (`repository` / REPOSITORY_BACKEND).
+*/
+type Backend struct {
+	Name    string                    // Nome do backend (mysql, memory ou sqlite)
+	Items   item.ItemRepositoryPort   // Repositório de itens
+	APIKeys auth.APIKeyRepositoryPort // Repositório das chaves de API (tabela api_keys, ou memória)
+	DB      *sql.DB                   // Conexão com o banco (nil para o backend em memória)
+	close   func()                    // Libera a conexão, se houver
+}
+
+/*
+New constrói o repositório de itens do backend configurado em cfg.Repository.
+
+Backends:
+  - mysql: conecta ao MySQL com cfg.DB (item.NewMySqlRepository), enviando as listagens
+    para as réplicas de cfg.DB.Replicas, se houver;
+  - sqlite: abre o arquivo cfg.SQLite.Path e cria o schema (item.NewSQLiteRepository);
+  - memory: repositório em memória, sem banco de dados (item.NewMapRepository).
+
+As chaves de API ficam no mesmo banco (auth.NewSQLRepository), ou em memória no backend memory.
+
+Retorna erro se o backend for desconhecido ou se a conexão falhar.
+*/
+func New(cfg config.Config) (*Backend, error) {
+	b := &Backend{Name: cfg.Repository, close: func() {}}
+
+	switch cfg.Repository {
+	case config.BackendMySQL:
+		client, err := mysqlsetup.NewMySQLSetup(cfg.DB)
+		if err != nil {
+			return nil, err
+		}
+		b.DB = client.DB()
+		b.Items = item.NewMySqlRepository(b.DB)
+		if len(cfg.DB.Replicas) > 0 {
+			b.Items = item.NewMySqlRepositoryWithReplicas(b.DB, client.ReadDB)
+		}
+		b.APIKeys = auth.NewSQLRepository(b.DB)
+		b.close = client.Close
+	case config.BackendSQLite:
+		db, err := sqlitesetup.NewSQLiteSetup(cfg.SQLite.Path)
+		if err != nil {
+			return nil, err
+		}
+		b.DB = db
+		b.Items = item.NewSQLiteRepository(db)
+		b.APIKeys = auth.NewSQLRepository(db)
+		b.close = func() { db.Close() }
+	case config.BackendMemory:
+		b.Items = item.NewMapRepository()
+		b.APIKeys = auth.NewMapRepository()
+	default:
+		return nil, fmt.Errorf("repositório desconhecido: %q", cfg.Repository)
+	}
+	return b, nil
+}
+
+/*
+Ping verifica se o banco do backend está respondendo.
+O backend em memória não tem banco, então está sempre pronto.
+*/
+func (b *Backend) Ping(ctx context.Context) error {
+	if b.DB == nil {
+		return nil
+	}
+	return b.DB.PingContext(ctx)
+}
+
+// Close libera a conexão com o banco. Deve ser chamado (com `defer`) ao encerrar o programa.
+func (b *Backend) Close() {
+	b.close()
+}
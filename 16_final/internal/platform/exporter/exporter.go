@@ -0,0 +1,267 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2" // Escrita de planilhas do Excel (.xlsx)
+
+	"api/internal/core/item"
+)
+
+/*
+Formatos de exportação do catálogo.
+
+- FormatCSV: uma linha por item, com cabeçalho (Columns), no formato aceito pela importação;
+- FormatNDJSON: um objeto JSON por linha (newline-delimited JSON), fácil de processar aos poucos;
+- FormatXLSX: planilha do Excel com uma aba "itens" e as mesmas colunas do CSV;
+- FormatJSON: uma única lista JSON, como a `items` de GET /items.
+*/
+const (
+	FormatCSV    = "csv" // Textos que começariam uma fórmula saem protegidos (ver safeCell)
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+	FormatJSON   = "json"
+)
+
+// Formats lista os formatos aceitos.
+var Formats = []string{FormatCSV, FormatNDJSON, FormatXLSX, FormatJSON}
+
+// Columns é a ordem das colunas dos formatos tabulares (CSV e XLSX).
+var Columns = []string{"id", "code", "title", "description", "price", "stock", "status"}
+
+// ErrUnsupportedFormat indica um formato que não é um de Formats.
+var ErrUnsupportedFormat = errors.New("formato de exportação não suportado")
+
+/*
+Writer escreve itens, um por vez, no formato escolhido.
+
+Close completa o arquivo (ex: o fim da lista JSON ou o pacote do XLSX) e descarrega
+o que estiver em buffer, mas não fecha o io.Writer de destino.
+
+Abort libera os recursos (ex: a pasta de trabalho e os arquivos temporários do XLSX)
+sem completar o arquivo, quando a exportação falha antes do Close. Depois do Close não
+faz nada, então pode ser chamado com defer logo depois de New.
+*/
+type Writer interface {
+	Write(it item.Item) error
+	Close() error
+	Abort()
+}
+
+/*
+New cria o Writer do formato sobre w.
+
+CSV, NDJSON e JSON são escritos à medida que os itens chegam, com memória constante.
+O XLSX é um pacote zip que só pode ser montado no fim: as linhas vão para a planilha em
+modo streaming (o excelize passa para um arquivo temporário acima de alguns MiB) e o
+arquivo é escrito em w no Close.
+*/
+func New(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q (use %s)", ErrUnsupportedFormat, format, strings.Join(Formats, ", "))
+	}
+}
+
+// ContentType retorna o media type do formato, para o header Content-Type.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/json"
+	}
+}
+
+// record converte o item nas células de Columns (preço com duas casas, como na listagem).
+func record(it item.Item) []string {
+	return []string{
+		strconv.Itoa(it.ID), it.Code, it.Title, it.Description,
+		strconv.FormatFloat(it.Price, 'f', 2, 64), strconv.Itoa(it.Stock), it.Status,
+	}
+}
+
+// csvWriter escreve o cabeçalho antes do primeiro item (ou no Close, se não houver itens).
+type csvWriter struct {
+	w      *csv.Writer // Escritor CSV sobre o destino
+	header bool        // true depois que o cabeçalho foi escrito
+}
+
+func (e *csvWriter) Write(it item.Item) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	rec := record(it)
+	for _, i := range []int{1, 2, 3} { // code, title e description, preenchidos pelos usuários
+		rec[i] = safeCell(rec[i])
+	}
+	return e.w.Write(rec)
+}
+
+func (e *csvWriter) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvWriter) Abort() {}
+
+/*
+safeCell evita a injeção de fórmulas (CSV injection): um texto que começa com `=`, `+`,
+`-`, `@`, tab ou CR seria interpretado como fórmula pelo Excel ou pelo Sheets ao abrir o
+CSV, então recebe um `'` na frente e é exibido como texto.
+
+O `'` faz parte do valor exportado: reimportado, o arquivo grava o texto com o prefixo.
+O XLSX não precisa disso, já que as células de texto são gravadas como texto.
+*/
+func safeCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func (e *csvWriter) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	return e.w.Write(Columns)
+}
+
+// ndjsonWriter escreve um item JSON por linha.
+type ndjsonWriter struct {
+	enc *json.Encoder // Codificador JSON sobre o destino (acrescenta a quebra de linha)
+}
+
+func (e *ndjsonWriter) Write(it item.Item) error {
+	return e.enc.Encode(it)
+}
+
+func (e *ndjsonWriter) Close() error {
+	return nil
+}
+
+func (e *ndjsonWriter) Abort() {}
+
+// jsonWriter escreve uma lista JSON, um item por linha, abrindo-a no primeiro item.
+type jsonWriter struct {
+	w     io.Writer // Destino
+	count int       // Itens já escritos
+}
+
+func (e *jsonWriter) Write(it item.Item) error {
+	data, err := json.Marshal(it)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+	e.count++
+	_, err = io.WriteString(e.w, sep+string(data))
+	return err
+}
+
+func (e *jsonWriter) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+func (e *jsonWriter) Abort() {}
+
+// xlsxSheet é o nome da aba da planilha exportada.
+const xlsxSheet = "itens"
+
+// xlsxWriter monta a planilha com o StreamWriter do excelize e a escreve no Close.
+type xlsxWriter struct {
+	w    io.Writer              // Destino
+	f    *excelize.File         // Pasta de trabalho
+	sw   *excelize.StreamWriter // Escrita das linhas em streaming
+	row  int                    // Próxima linha da planilha (1 = cabeçalho)
+	done bool                   // true depois que a pasta de trabalho foi fechada (Close ou Abort)
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	if err := f.SetSheetName(f.GetSheetName(0), xlsxSheet); err != nil {
+		f.Close()
+		return nil, err
+	}
+	sw, err := f.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	e := &xlsxWriter{w: w, f: f, sw: sw, row: 1}
+	header := make([]any, len(Columns))
+	for i, c := range Columns {
+		header[i] = c
+	}
+	if err := e.setRow(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *xlsxWriter) Write(it item.Item) error {
+	// Números vão como números, para que a planilha possa somar e ordenar as colunas
+	return e.setRow([]any{it.ID, it.Code, it.Title, it.Description, it.Price, it.Stock, it.Status})
+}
+
+func (e *xlsxWriter) Close() error {
+	if e.done {
+		return nil
+	}
+	defer e.Abort()
+	if err := e.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := e.f.WriteTo(e.w)
+	return err
+}
+
+// Abort fecha a pasta de trabalho, removendo os arquivos temporários do StreamWriter.
+func (e *xlsxWriter) Abort() {
+	if e.done {
+		return
+	}
+	e.done = true
+	e.f.Close()
+}
+
+func (e *xlsxWriter) setRow(values []any) error {
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	e.row++
+	return e.sw.SetRow(cell, values)
+}
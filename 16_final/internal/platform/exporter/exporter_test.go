@@ -0,0 +1,49 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"api/internal/core/item"
+)
+
+// TestCSVFormulaInjection garante que textos que começariam uma fórmula saem protegidos no CSV.
+func TestCSVFormulaInjection(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"Caneta", "Caneta"},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+cmd|' /C calc'!A0", "'+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := New(&buf, FormatCSV)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			it := item.Item{ID: 1, Code: "ITEM-001", Title: tt.title, Description: tt.title, Status: item.StatusActive}
+			if err := w.Write(it); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil || len(records) != 2 {
+				t.Fatalf("CSV exportado = %q, %v", records, err)
+			}
+			if got := records[1]; got[1] != "ITEM-001" || got[2] != tt.want || got[3] != tt.want {
+				t.Fatalf("linha exportada = %q; esperado title e description %q", got, tt.want)
+			}
+		})
+	}
+}
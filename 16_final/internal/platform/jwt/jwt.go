@@ -0,0 +1,395 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"api/internal/core/auth" // Principal devolvido para o caso de uso de autenticação
+	"api/pkg/config"         // Configuração tipada (auth.jwt)
+)
+
+/*
+Algoritmos de assinatura aceitos. Qualquer outro (inclusive "none") é recusado, e cada
+chave só vale para o seu algoritmo: um segredo HS256 nunca valida um token RS256 e vice-versa.
+*/
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+/*
+minRSABits é o tamanho mínimo do módulo das chaves RSA (PEM ou JWKS). O go.mod fixa uma
+versão do Go anterior ao GODEBUG rsa1024min, então o limite é conferido aqui.
+*/
+const minRSABits = 2048
+
+// Erros de validação de tokens, encadeados com `%w` nas mensagens de Verify.
+var (
+	ErrMalformed    = errors.New("token malformado")
+	ErrUnknownKey   = errors.New("nenhuma chave configurada para o token")
+	ErrSignature    = errors.New("assinatura inválida")
+	ErrExpired      = errors.New("token expirado")
+	ErrNotYetValid  = errors.New("token ainda não é válido")
+	ErrInvalidClaim = errors.New("claim inválido")
+)
+
+/*
+Verifier valida tokens JWT assinados com HS256 ou RS256.
+
+As chaves são lidas uma única vez, ao criar o Verifier (New): trocar o segredo, o
+arquivo PEM ou o JWKS exige reiniciar a API. Com JWKS, a chave é escolhida pelo
+header `kid` do token, e um `kid` sem chave correspondente é recusado; tokens sem `kid`
+usam a chave sem ID (PEM ou segredo HS256) ou, se houver uma só chave do algoritmo, essa chave.
+*/
+type Verifier struct {
+	hmacKeys map[string][]byte         // Segredos HS256 por kid ("" = auth.jwt.hs256_secret)
+	rsaKeys  map[string]*rsa.PublicKey // Chaves públicas RS256 por kid ("" = auth.jwt.rs256_public_key_file)
+	issuer   string                    // `iss` exigido (vazio = não confere)
+	audience string                    // `aud` exigido (vazio = não confere)
+	leeway   time.Duration             // Tolerância de relógio em `exp` e `nbf`
+	now      func() time.Time          // Relógio (substituído nos testes)
+}
+
+/*
+New cria o Verifier com as chaves de cfg.
+
+Retorna (nil, nil) se nenhuma chave foi configurada (ver config.JWTConfig.Configured),
+e erro se o arquivo PEM ou o JWKS não puder ser lido ou não tiver nenhuma chave utilizável.
+*/
+func New(cfg config.JWTConfig) (*Verifier, error) {
+	if !cfg.Configured() {
+		return nil, nil
+	}
+	v := &Verifier{
+		hmacKeys: map[string][]byte{},
+		rsaKeys:  map[string]*rsa.PublicKey{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway.Duration,
+		now:      time.Now,
+	}
+
+	if cfg.HS256Secret != "" {
+		v.hmacKeys[""] = []byte(cfg.HS256Secret)
+	}
+	if cfg.RS256PublicKeyFile != "" {
+		key, err := readPublicKey(cfg.RS256PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys[""] = key
+	}
+	if cfg.JWKSFile != "" {
+		if err := v.loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// readPublicKey lê uma chave pública RSA em PEM ("PUBLIC KEY" ou "RSA PUBLIC KEY") de pelo menos minRSABits.
+func readPublicKey(path string) (*rsa.PublicKey, error) {
+	key, err := parsePublicKey(path)
+	if err != nil {
+		return nil, err
+	}
+	if key.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("chave pública JWT %s tem %d bits; o mínimo é %d", path, key.N.BitLen(), minRSABits)
+	}
+	return key, nil
+}
+
+// parsePublicKey interpreta o arquivo PEM de readPublicKey.
+func parsePublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler a chave pública JWT: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("chave pública JWT %s não está em PEM", path)
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao interpretar a chave pública JWT %s: %w", path, err)
+	}
+	key, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("chave pública JWT %s não é RSA", path)
+	}
+	return key, nil
+}
+
+// jwk é o subconjunto de uma JSON Web Key (RFC 7517) usado aqui: chaves RSA e segredos (oct).
+type jwk struct {
+	Kty string `json:"kty"` // Tipo: RSA ou oct
+	Kid string `json:"kid"` // ID da chave, comparado com o header `kid` do token
+	Use string `json:"use"` // Uso: apenas "sig" (ou vazio) é considerado
+	Alg string `json:"alg"` // Algoritmo, se restrito (RS256 ou HS256)
+	N   string `json:"n"`   // Módulo RSA (base64url)
+	E   string `json:"e"`   // Expoente RSA (base64url)
+	K   string `json:"k"`   // Segredo simétrico (base64url)
+}
+
+/*
+loadJWKS lê um arquivo JWKS ({"keys": [...]}) e guarda as chaves de assinatura por kid.
+
+Chaves de outros tipos (ex: EC) ou com `use` diferente de "sig" são ignoradas; o arquivo
+precisa ter ao menos uma chave RSA (RS256) ou oct (HS256) utilizável. Uma chave oct mais
+curta que config.MinHS256SecretLen ou RSA menor que minRSABits é recusada.
+*/
+func (v *Verifier) loadJWKS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("falha ao ler o JWKS: %w", err)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("falha ao interpretar o JWKS %s: %w", path, err)
+	}
+
+	loaded := 0
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch {
+		case k.Kty == "RSA" && (k.Alg == "" || k.Alg == AlgRS256):
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+				return fmt.Errorf("JWKS %s: chave RSA %q inválida", path, k.Kid)
+			}
+			key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+			if key.N.BitLen() < minRSABits {
+				return fmt.Errorf("JWKS %s: chave RSA %q tem %d bits; o mínimo é %d", path, k.Kid, key.N.BitLen(), minRSABits)
+			}
+			v.rsaKeys[k.Kid] = key
+		case k.Kty == "oct" && (k.Alg == "" || k.Alg == AlgHS256):
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil || len(secret) == 0 {
+				return fmt.Errorf("JWKS %s: chave oct %q inválida", path, k.Kid)
+			}
+			if len(secret) < config.MinHS256SecretLen {
+				return fmt.Errorf("JWKS %s: chave oct %q deve ter pelo menos %d bytes", path, k.Kid, config.MinHS256SecretLen)
+			}
+			v.hmacKeys[k.Kid] = secret
+		default:
+			continue
+		}
+		loaded++
+	}
+	if loaded == 0 {
+		return fmt.Errorf("JWKS %s não tem nenhuma chave RS256 ou HS256", path)
+	}
+	return nil
+}
+
+// header é o cabeçalho (JOSE) do token.
+type header struct {
+	Alg string `json:"alg"` // Algoritmo da assinatura
+	Kid string `json:"kid"` // ID da chave (opcional)
+}
+
+/*
+Claims são os claims registrados (RFC 7519) conferidos por Verify, mais o `name` e os `roles`
+usados no Principal.
+*/
+type Claims struct {
+	Subject   string   `json:"sub"`   // Identificador do usuário ou serviço (obrigatório)
+	Name      string   `json:"name"`  // Nome legível (opcional)
+	Roles     roles    `json:"roles"` // Papéis (ver auth.Roles), em lista ou texto separado por espaços (opcional)
+	Issuer    string   `json:"iss"`   // Emissor
+	Audience  audience `json:"aud"`   // Destinatários (texto ou lista)
+	ExpiresAt *float64 `json:"exp"`   // Expiração, em segundos Unix (obrigatório)
+	NotBefore *float64 `json:"nbf"`   // Início da validade, em segundos Unix (opcional)
+}
+
+// audience aceita o claim `aud` como texto ou como lista de textos.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, (*[]string)(a))
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*a = audience{s}
+	return nil
+}
+
+// roles aceita o claim `roles` como lista de textos ou como um texto separado por espaços (como `scope`).
+type roles []string
+
+func (r *roles) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, (*[]string)(r))
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*r = strings.Fields(s)
+	return nil
+}
+
+/*
+Verify confere a assinatura e os claims do token (sem o prefixo "Bearer ").
+
+Regras:
+  - o algoritmo precisa ser HS256 ou RS256, com uma chave configurada para ele;
+  - `exp` é obrigatório e `nbf`, se presente, é respeitado (ambos com a tolerância leeway);
+  - `iss` e `aud` precisam bater com auth.jwt.issuer e auth.jwt.audience, se configurados;
+  - `sub` é obrigatório, pois identifica o principal.
+*/
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformed
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return Claims{}, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	if err := v.verifySignature(h, parts[0]+"."+parts[1], sig); err != nil {
+		return Claims{}, err
+	}
+
+	var c Claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return Claims{}, err
+	}
+	if err := v.checkClaims(c); err != nil {
+		return Claims{}, err
+	}
+	return c, nil
+}
+
+/*
+VerifyToken implementa auth.TokenVerifier: valida o token com Verify e monta o
+Principal a partir de `sub`, `name` e `roles` (sem `roles`, o principal não tem permissões).
+*/
+func (v *Verifier) VerifyToken(token string) (auth.Principal, error) {
+	c, err := v.Verify(token)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	return auth.Principal{Type: auth.PrincipalJWT, ID: c.Subject, Name: c.Name, Roles: c.Roles}, nil
+}
+
+// verifySignature confere a assinatura de signed com a chave do algoritmo e do kid do header.
+func (v *Verifier) verifySignature(h header, signed string, sig []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch h.Alg {
+	case AlgHS256:
+		key, ok := pickKey(v.hmacKeys, h.Kid)
+		if !ok {
+			return fmt.Errorf("%w (alg %s, kid %q)", ErrUnknownKey, h.Alg, h.Kid)
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return ErrSignature
+		}
+	case AlgRS256:
+		key, ok := pickKey(v.rsaKeys, h.Kid)
+		if !ok {
+			return fmt.Errorf("%w (alg %s, kid %q)", ErrUnknownKey, h.Alg, h.Kid)
+		}
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) != nil {
+			return ErrSignature
+		}
+	default:
+		return fmt.Errorf("%w: algoritmo %q não é aceito (use %s ou %s)", ErrMalformed, h.Alg, AlgHS256, AlgRS256)
+	}
+	return nil
+}
+
+/*
+pickKey escolhe a chave pelo kid: com kid, só a chave com esse ID, para que um erro na
+rotação ou na configuração das chaves não passe despercebido. Sem kid, a chave sem ID;
+senão, a única chave do algoritmo (comum em JWKS com uma só chave).
+*/
+func pickKey[K any](keys map[string]K, kid string) (K, bool) {
+	if kid != "" {
+		key, ok := keys[kid]
+		return key, ok
+	}
+	if key, ok := keys[""]; ok {
+		return key, true
+	}
+	if len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	var zero K
+	return zero, false
+}
+
+// checkClaims confere expiração, início da validade, emissor, destinatário e sujeito.
+func (v *Verifier) checkClaims(c Claims) error {
+	now := v.now()
+	if c.ExpiresAt == nil {
+		return fmt.Errorf("%w: exp é obrigatório", ErrInvalidClaim)
+	}
+	if now.After(unixTime(*c.ExpiresAt).Add(v.leeway)) {
+		return ErrExpired
+	}
+	if c.NotBefore != nil && now.Add(v.leeway).Before(unixTime(*c.NotBefore)) {
+		return ErrNotYetValid
+	}
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return fmt.Errorf("%w: iss %q não é o emissor esperado", ErrInvalidClaim, c.Issuer)
+	}
+	if v.audience != "" && !slices.Contains(c.Audience, v.audience) {
+		return fmt.Errorf("%w: aud não inclui %q", ErrInvalidClaim, v.audience)
+	}
+	if c.Subject == "" {
+		return fmt.Errorf("%w: sub é obrigatório", ErrInvalidClaim)
+	}
+	return nil
+}
+
+// unixTime converte um NumericDate (segundos Unix, possivelmente com fração) em time.Time.
+func unixTime(secs float64) time.Time {
+	return time.Unix(0, int64(secs*float64(time.Second)))
+}
+
+// decodeSegment decodifica uma parte base64url do token como JSON.
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return nil
+}
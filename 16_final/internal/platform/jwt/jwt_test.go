@@ -0,0 +1,218 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"api/pkg/config"
+)
+
+const testSecret = "segredo-de-teste-com-pelo-menos-32-bytes"
+
+// now é o relógio fixo dos testes.
+var now = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// sign monta um token com o header e os claims informados, assinado por signFn.
+func sign(t *testing.T, h map[string]any, claims map[string]any, signFn func(signed string) []byte) string {
+	t.Helper()
+	hb, _ := json.Marshal(h)
+	cb, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(hb) + "." + base64.RawURLEncoding.EncodeToString(cb)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signFn(signed))
+}
+
+func hs256(secret []byte) func(string) []byte {
+	return func(signed string) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signed))
+		return mac.Sum(nil)
+	}
+}
+
+func rs256(t *testing.T, key *rsa.PrivateKey) func(string) []byte {
+	return func(signed string) []byte {
+		digest := sha256.Sum256([]byte(signed))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("SignPKCS1v15: %v", err)
+		}
+		return sig
+	}
+}
+
+// claims devolve claims válidos no relógio dos testes, com os campos extras sobrescritos.
+func claims(extra map[string]any) map[string]any {
+	c := map[string]any{"sub": "maria", "name": "Maria", "iss": "https://id.exemplo", "aud": "inventory", "exp": now.Add(time.Hour).Unix()}
+	for k, v := range extra {
+		if v == nil {
+			delete(c, k)
+			continue
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// newVerifier cria o Verifier com o relógio dos testes.
+func newVerifier(t *testing.T, cfg config.JWTConfig) *Verifier {
+	t.Helper()
+	cfg.Issuer, cfg.Audience, cfg.Leeway = "https://id.exemplo", "inventory", config.Duration{Duration: 30 * time.Second}
+	v, err := New(cfg)
+	if err != nil || v == nil {
+		t.Fatalf("New: %v, %v", v, err)
+	}
+	v.now = func() time.Time { return now }
+	return v
+}
+
+// writeFile grava data em um arquivo temporário e devolve o caminho.
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestVerifyHS256(t *testing.T) {
+	v := newVerifier(t, config.JWTConfig{HS256Secret: testSecret})
+	hs := map[string]any{"alg": "HS256", "typ": "JWT"}
+
+	p, err := v.VerifyToken(sign(t, hs, claims(nil), hs256([]byte(testSecret))))
+	if err != nil || p.Type != "jwt" || p.ID != "maria" || p.Name != "Maria" {
+		t.Fatalf("VerifyToken = %+v, %v", p, err)
+	}
+	// roles em lista ou em texto separado por espaços
+	for _, roles := range []any{[]string{"warehouse", "catalog"}, "warehouse catalog"} {
+		p, err := v.VerifyToken(sign(t, hs, claims(map[string]any{"roles": roles}), hs256([]byte(testSecret))))
+		if err != nil || !slices.Equal(p.Roles, []string{"warehouse", "catalog"}) {
+			t.Fatalf("VerifyToken(roles %v) = %+v, %v", roles, p, err)
+		}
+	}
+	// aud em lista e exp vencido há menos que a tolerância
+	if _, err := v.Verify(sign(t, hs, claims(map[string]any{"aud": []string{"outro", "inventory"}, "exp": now.Add(-10 * time.Second).Unix()}), hs256([]byte(testSecret)))); err != nil {
+		t.Fatalf("token dentro da tolerância: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"segredo errado", sign(t, hs, claims(nil), hs256([]byte("outro-segredo"))), ErrSignature},
+		{"expirado", sign(t, hs, claims(map[string]any{"exp": now.Add(-time.Minute).Unix()}), hs256([]byte(testSecret))), ErrExpired},
+		{"sem exp", sign(t, hs, claims(map[string]any{"exp": nil}), hs256([]byte(testSecret))), ErrInvalidClaim},
+		{"nbf no futuro", sign(t, hs, claims(map[string]any{"nbf": now.Add(time.Hour).Unix()}), hs256([]byte(testSecret))), ErrNotYetValid},
+		{"outro emissor", sign(t, hs, claims(map[string]any{"iss": "https://outro"}), hs256([]byte(testSecret))), ErrInvalidClaim},
+		{"outro destinatário", sign(t, hs, claims(map[string]any{"aud": "outra-api"}), hs256([]byte(testSecret))), ErrInvalidClaim},
+		{"sem sub", sign(t, hs, claims(map[string]any{"sub": nil}), hs256([]byte(testSecret))), ErrInvalidClaim},
+		{"alg none", sign(t, map[string]any{"alg": "none"}, claims(nil), func(string) []byte { return nil }), ErrMalformed},
+		{"RS256 sem chave RSA", sign(t, map[string]any{"alg": "RS256"}, claims(nil), hs256([]byte(testSecret))), ErrUnknownKey},
+		{"malformado", "abc.def", ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Verify(tt.token); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	pemFile := writeFile(t, "jwt.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	// Arquivo PEM: a chave valida os tokens sem kid; um segredo HS256 não é aceito no lugar dela
+	v := newVerifier(t, config.JWTConfig{RS256PublicKeyFile: pemFile})
+	if _, err := v.Verify(sign(t, map[string]any{"alg": "RS256"}, claims(nil), rs256(t, key))); err != nil {
+		t.Fatalf("RS256 com PEM: %v", err)
+	}
+	if _, err := v.Verify(sign(t, map[string]any{"alg": "RS256", "kid": "k1"}, claims(nil), rs256(t, key))); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("kid desconhecido com PEM: %v", err)
+	}
+	if _, err := v.Verify(sign(t, map[string]any{"alg": "RS256"}, claims(nil), rs256(t, other))); !errors.Is(err, ErrSignature) {
+		t.Fatalf("outra chave RSA: %v", err)
+	}
+	if _, err := v.Verify(sign(t, map[string]any{"alg": "HS256"}, claims(nil), hs256(der))); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("HS256 assinado com a chave pública: %v", err)
+	}
+
+	// JWKS: a chave é escolhida pelo kid
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "k1", "use": "sig", "alg": "RS256", "n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes())},
+		{"kty": "RSA", "kid": "k2", "use": "sig", "n": b64(other.N.Bytes()), "e": b64(big.NewInt(int64(other.E)).Bytes())},
+		{"kty": "EC", "kid": "k3", "crv": "P-256"},
+	}})
+	v = newVerifier(t, config.JWTConfig{JWKSFile: writeFile(t, "jwks.json", jwks)})
+	for kid, k := range map[string]*rsa.PrivateKey{"k1": key, "k2": other} {
+		if _, err := v.Verify(sign(t, map[string]any{"alg": "RS256", "kid": kid}, claims(nil), rs256(t, k))); err != nil {
+			t.Fatalf("JWKS kid %s: %v", kid, err)
+		}
+	}
+	if _, err := v.Verify(sign(t, map[string]any{"alg": "RS256", "kid": "k2"}, claims(nil), rs256(t, key))); !errors.Is(err, ErrSignature) {
+		t.Fatalf("kid trocado: %v", err)
+	}
+	if _, err := v.Verify(sign(t, map[string]any{"alg": "RS256", "kid": "k9"}, claims(nil), rs256(t, key))); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("kid desconhecido: %v", err)
+	}
+	if _, err := v.Verify(sign(t, map[string]any{"alg": "RS256"}, claims(nil), rs256(t, key))); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("sem kid entre várias chaves: %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if v, err := New(config.JWTConfig{}); v != nil || err != nil {
+		t.Fatalf("sem chaves: %v, %v; esperado JWT desabilitado", v, err)
+	}
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	weakDER, _ := x509.MarshalPKIXPublicKey(&weak.PublicKey)
+	b64 := base64.RawURLEncoding.EncodeToString
+	weakJWKS, _ := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "k1", "n": b64(weak.N.Bytes()), "e": b64(big.NewInt(int64(weak.E)).Bytes())},
+	}})
+
+	tests := []struct {
+		name string
+		cfg  config.JWTConfig
+	}{
+		{"PEM inexistente", config.JWTConfig{RS256PublicKeyFile: filepath.Join(t.TempDir(), "nao-existe.pem")}},
+		{"PEM inválido", config.JWTConfig{RS256PublicKeyFile: writeFile(t, "jwt.pub", []byte("não é PEM"))}},
+		{"JWKS inválido", config.JWTConfig{JWKSFile: writeFile(t, "jwks.json", []byte("{"))}},
+		{"JWKS sem chaves utilizáveis", config.JWTConfig{JWKSFile: writeFile(t, "jwks.json", []byte(`{"keys": [{"kty": "EC"}]}`))}},
+		{"PEM RSA de 1024 bits", config.JWTConfig{RS256PublicKeyFile: writeFile(t, "jwt.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: weakDER}))}},
+		{"JWKS com chave RSA de 1024 bits", config.JWTConfig{JWKSFile: writeFile(t, "jwks.json", weakJWKS)}},
+		{"JWKS com chave oct curta", config.JWTConfig{JWKSFile: writeFile(t, "jwks.json", []byte(`{"keys": [{"kty": "oct", "k": "YQ"}]}`))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Fatal("esperado erro")
+			}
+		})
+	}
+}
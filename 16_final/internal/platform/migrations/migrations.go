@@ -0,0 +1,467 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Os scripts ficam em um diretório por dialeto (mysql/ e sqlite/) e são embutidos no binário.
+
+Cada migração tem dois arquivos com o mesmo número e nome:
+
+	0003_add_deleted_at.up.sql    -- aplica a mudança
+	0003_add_deleted_at.down.sql  -- desfaz a mudança
+
+Os comandos de um script são separados por `;` no fim da linha.
+Uma migração já aplicada nunca deve ser editada: o checksum do script `up`
+é gravado em schema_migrations e conferido a cada execução.
+*/
+//
+//go:embed mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// fileName reconhece os nomes de arquivo das migrações (ex: 0001_create_items.up.sql).
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+/*
+Migration é uma mudança versionada do schema.
+*/
+type Migration struct {
+	Version  int    // Número da migração (ordem de aplicação)
+	Name     string // Nome descritivo (ex: create_items)
+	Up       string // Script que aplica a mudança
+	Down     string // Script que desfaz a mudança
+	Checksum string // SHA-256 do script Up, gravado em schema_migrations
+}
+
+/*
+Status descreve a situação de uma migração no banco.
+*/
+type Status struct {
+	Migration
+	Applied   bool       // true se já foi aplicada
+	AppliedAt *time.Time // Momento em que foi aplicada (nil se pendente)
+	Modified  bool       // true se o script mudou depois de aplicado (checksum diferente)
+}
+
+/*
+Migrator aplica e desfaz as migrações de um dialeto sobre uma conexão.
+*/
+type Migrator struct {
+	db         *sql.DB     // Conexão com o banco
+	dialect    dialect     // Particularidades do banco (trava e DDL)
+	migrations []Migration // Migrações embutidas, em ordem crescente de versão
+}
+
+/*
+New cria um Migrator para o banco informado.
+
+Parâmetros:
+- db: conexão já aberta
+- dialectName: "mysql" ou "sqlite" (os mesmos nomes de config.Backends)
+
+Retorna erro se o dialeto não existir ou se os scripts embutidos estiverem inconsistentes
+(arquivo sem par up/down, versão repetida, etc.).
+*/
+func New(db *sql.DB, dialectName string) (*Migrator, error) {
+	d, ok := dialects[dialectName]
+	if !ok {
+		return nil, fmt.Errorf("migrações não suportadas para o banco %q", dialectName)
+	}
+	ms, err := load(dialectName)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: d, migrations: ms}, nil
+}
+
+/*
+load lê e valida os scripts embutidos de um dialeto.
+*/
+func load(dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		match := fileName.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("arquivo de migração com nome inválido: %s/%s", dir, e.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		body, err := files.ReadFile(path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migração %d com nomes diferentes: %s e %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+			sum := sha256.Sum256(body)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migração %04d_%s precisa dos scripts up e down", m.Version, m.Name)
+		}
+		ms = append(ms, *m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+/*
+Status lista todas as migrações conhecidas pelo binário e a situação de cada uma no banco.
+
+Só faz leituras: se schema_migrations ainda não existir, todas as migrações aparecem como pendentes.
+*/
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied := map[int]appliedMigration{}
+	exists, err := m.tableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if applied, err = m.applied(ctx, m.db); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Migration: mig}
+		if a, ok := applied[mig.Version]; ok {
+			at := a.appliedAt
+			st.Applied, st.AppliedAt = true, &at
+			st.Modified = a.checksum != mig.Checksum
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+/*
+Pending retorna quantas migrações ainda não foram aplicadas.
+
+Usado pelo readiness check para saber se o schema está atualizado; como Status, não altera o banco.
+*/
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	sts, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, st := range sts {
+		if !st.Applied {
+			n++
+		}
+	}
+	return n, nil
+}
+
+/*
+Up aplica, em ordem, todas as migrações pendentes.
+
+Passos:
+ 1. Obtém a trava de migração do banco (GET_LOCK no MySQL), para que várias
+    réplicas subindo ao mesmo tempo não apliquem a mesma migração;
+ 2. Confere o checksum das migrações já aplicadas (falha se algum script mudou);
+ 3. Aplica cada migração pendente e a registra em schema_migrations.
+
+Retorna as migrações aplicadas nesta execução.
+*/
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, conn, mig.Up,
+				`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+				mig.Version, mig.Name, mig.Checksum, time.Now().UTC(),
+			); err != nil {
+				return fmt.Errorf("falha ao aplicar a migração %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+/*
+Down desfaz as últimas `steps` migrações aplicadas, da mais nova para a mais antiga.
+
+Retorna as migrações desfeitas nesta execução.
+*/
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.run(ctx, conn, mig.Down,
+				`DELETE FROM schema_migrations WHERE version = ?`, mig.Version,
+			); err != nil {
+				return fmt.Errorf("falha ao desfazer a migração %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+/*
+withLock executa fn em uma conexão dedicada, com a tabela de controle criada e a trava obtida.
+*/
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.dialect.lock(ctx, conn); err != nil {
+		return err
+	}
+	defer m.dialect.unlock(context.WithoutCancel(ctx), conn)
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+/*
+run executa os comandos do script e o registro em schema_migrations.
+
+No SQLite tudo acontece em uma transação. No MySQL os comandos DDL fazem commit
+implícito, então um script com vários comandos que falhe no meio pode precisar
+de correção manual — por isso cada migração deve ser pequena.
+*/
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, script, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // Sem efeito após o Commit
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+/*
+verify falha se alguma migração aplicada teve o script alterado
+ou não existe mais neste binário (banco mais novo que o código).
+*/
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	known := map[int]Migration{}
+	for _, mig := range m.migrations {
+		known[mig.Version] = mig
+	}
+	for version, a := range applied {
+		mig, ok := known[version]
+		if !ok {
+			return fmt.Errorf("migração %d aplicada no banco não existe neste binário", version)
+		}
+		if a.checksum != mig.Checksum {
+			return fmt.Errorf("checksum da migração %04d_%s não confere: o script foi alterado depois de aplicado", mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+// queryer é satisfeito por *sql.DB e *sql.Conn.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// ensureTable cria a tabela de controle schema_migrations, se ainda não existir.
+func (m *Migrator) ensureTable(ctx context.Context, q queryer) error {
+	_, err := q.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("falha ao criar schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// tableExists informa se a tabela schema_migrations já existe, sem criá-la.
+func (m *Migrator) tableExists(ctx context.Context) (bool, error) {
+	var n int
+	if err := m.db.QueryRowContext(ctx, m.dialect.tableQuery, "schema_migrations").Scan(&n); err != nil {
+		return false, fmt.Errorf("falha ao consultar schema_migrations: %w", err)
+	}
+	return n > 0, nil
+}
+
+// appliedMigration é uma linha de schema_migrations.
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// applied lê as migrações já registradas em schema_migrations, indexadas pela versão.
+func (m *Migrator) applied(ctx context.Context, q queryer) (map[int]appliedMigration, error) {
+	rows, err := q.QueryContext(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int]appliedMigration{}
+	for rows.Next() {
+		var (
+			version int
+			a       appliedMigration
+		)
+		if err := rows.Scan(&version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		out[version] = a
+	}
+	return out, rows.Err()
+}
+
+/*
+splitStatements divide um script em comandos, ignorando linhas de comentário (`--`).
+
+Um comando termina em uma linha cujo último caractere é `;`.
+*/
+func splitStatements(script string) []string {
+	var (
+		stmts []string
+		cur   strings.Builder
+	)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(cur.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
+
+/*
+dialect contém o que muda entre os bancos na execução das migrações.
+*/
+type dialect struct {
+	lock       func(ctx context.Context, conn *sql.Conn) error // Obtém a trava de migração
+	unlock     func(ctx context.Context, conn *sql.Conn)       // Libera a trava de migração
+	tableQuery string                                          // Conta as tabelas com o nome informado (0 ou 1)
+}
+
+var dialects = map[string]dialect{
+	"mysql": {
+		lock:       mysqlLock,
+		unlock:     mysqlUnlock,
+		tableQuery: `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?`,
+	},
+	"sqlite": {
+		lock:       noLock,
+		unlock:     func(context.Context, *sql.Conn) {},
+		tableQuery: `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`,
+	},
+}
+
+const (
+	lockName    = "inventory.schema_migrations" // Nome da trava consultiva (advisory lock) do MySQL
+	lockTimeout = 60                            // Segundos de espera pela trava antes de desistir
+)
+
+/*
+mysqlLock usa GET_LOCK, uma trava consultiva do MySQL presa à sessão (conexão):
+a segunda réplica fica esperando até a primeira terminar as migrações.
+*/
+func mysqlLock(ctx context.Context, conn *sql.Conn) error {
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, lockName, lockTimeout).Scan(&got); err != nil {
+		return fmt.Errorf("falha ao obter a trava de migração: %w", err)
+	}
+	if !got.Valid || got.Int64 != 1 {
+		return errors.New("tempo esgotado esperando a trava de migração (outra instância está migrando?)")
+	}
+	return nil
+}
+
+// mysqlUnlock libera a trava obtida por mysqlLock.
+func mysqlUnlock(ctx context.Context, conn *sql.Conn) {
+	conn.ExecContext(ctx, `SELECT RELEASE_LOCK(?)`, lockName)
+}
+
+/*
+noLock é usado no SQLite: o banco é local e o pool tem uma única conexão,
+então não há réplicas concorrendo pelas migrações.
+*/
+func noLock(context.Context, *sql.Conn) error {
+	return nil
+}
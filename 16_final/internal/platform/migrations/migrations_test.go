@@ -0,0 +1,167 @@
+package migrations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	sqlitesetup "api/internal/platform/sqlite"
+)
+
+// newTestMigrator cria um Migrator sobre um banco SQLite vazio em memória.
+func newTestMigrator(t *testing.T) *Migrator {
+	t.Helper()
+	db, err := sqlitesetup.NewSQLiteSetup(":memory:")
+	if err != nil {
+		t.Fatalf("falha ao abrir o SQLite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := New(db, "sqlite")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return m
+}
+
+func TestEmbeddedMigrationsMatchAcrossDialects(t *testing.T) {
+	mysql, err := load("mysql")
+	if err != nil {
+		t.Fatalf("load(mysql): %v", err)
+	}
+	sqlite, err := load("sqlite")
+	if err != nil {
+		t.Fatalf("load(sqlite): %v", err)
+	}
+	if len(mysql) != len(sqlite) {
+		t.Fatalf("mysql tem %d migrações e sqlite tem %d", len(mysql), len(sqlite))
+	}
+	for i := range mysql {
+		if mysql[i].Version != sqlite[i].Version || mysql[i].Name != sqlite[i].Name {
+			t.Fatalf("migração %d difere: mysql %04d_%s, sqlite %04d_%s",
+				i, mysql[i].Version, mysql[i].Name, sqlite[i].Version, sqlite[i].Name)
+		}
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMigrator(t)
+	total := len(m.migrations)
+
+	if n, err := m.Pending(ctx); err != nil || n != total {
+		t.Fatalf("Pending antes de Up = %d, %v; esperado %d", n, err, total)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil || len(applied) != total {
+		t.Fatalf("Up = %d migrações, %v; esperado %d", len(applied), err, total)
+	}
+	if again, err := m.Up(ctx); err != nil || len(again) != 0 {
+		t.Fatalf("Up repetido = %d migrações, %v; esperado nenhuma", len(again), err)
+	}
+	if n, _ := m.Pending(ctx); n != 0 {
+		t.Fatalf("Pending depois de Up = %d, esperado 0", n)
+	}
+
+	reverted, err := m.Down(ctx, 1)
+	if err != nil || len(reverted) != 1 || reverted[0].Version != m.migrations[total-1].Version {
+		t.Fatalf("Down(1) = %+v, %v; esperado apenas a última migração", reverted, err)
+	}
+	sts, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if last := sts[total-1]; last.Applied || !sts[0].Applied {
+		t.Fatalf("Status depois de Down(1) = %+v", sts)
+	}
+}
+
+func TestStatusIsReadOnly(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMigrator(t)
+
+	if n, err := m.Pending(ctx); err != nil || n != len(m.migrations) {
+		t.Fatalf("Pending em banco vazio = %d, %v; esperado %d", n, err, len(m.migrations))
+	}
+	if exists, err := m.tableExists(ctx); err != nil || exists {
+		t.Fatalf("schema_migrations existe = %v, %v; Pending não deveria criar a tabela", exists, err)
+	}
+}
+
+// TestUpOnBaselineSchema aplica as migrações sobre um banco criado com o schema original (init.sql),
+// sem a coluna version e sem a unicidade do código: as migrações 0002 e 0003 não podem ser puladas.
+func TestUpOnBaselineSchema(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMigrator(t)
+	if _, err := m.db.Exec(`
+		CREATE TABLE items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			code VARCHAR(255) NOT NULL,
+			title VARCHAR(255) NOT NULL,
+			description TEXT,
+			price DECIMAL(10, 2),
+			stock INT,
+			status VARCHAR(50),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		t.Fatalf("falha ao criar o schema original: %v", err)
+	}
+	if _, err := m.db.Exec(`INSERT INTO items (code, title) VALUES ('ITEM-001', 'Caneta')`); err != nil {
+		t.Fatalf("falha ao inserir: %v", err)
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	var version int
+	if err := m.db.QueryRow(`SELECT version FROM items WHERE code = 'ITEM-001'`).Scan(&version); err != nil || version != 1 {
+		t.Fatalf("version do item existente = %d, %v; esperado 1", version, err)
+	}
+	if _, err := m.db.Exec(`INSERT INTO items (code, title) VALUES ('ITEM-001', 'Outra')`); err == nil {
+		t.Fatal("código repetido aceito depois das migrações")
+	}
+
+	// Todas as migrações podem ser desfeitas e reaplicadas
+	if _, err := m.Down(ctx, len(m.migrations)); err != nil {
+		t.Fatalf("Down de todas: %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up depois de Down: %v", err)
+	}
+}
+
+func TestUpRejectsModifiedMigration(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMigrator(t)
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	if _, err := m.db.Exec(`UPDATE schema_migrations SET checksum = 'outro' WHERE version = 1`); err != nil {
+		t.Fatalf("falha ao alterar o checksum: %v", err)
+	}
+
+	if _, err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("Up com checksum alterado: esperado erro de checksum, obtido %v", err)
+	}
+	sts, _ := m.Status(ctx)
+	if !sts[0].Modified {
+		t.Fatalf("Status não marcou a migração 1 como modificada: %+v", sts[0])
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- comentário
+CREATE TABLE a (
+    id INT -- coluna
+);
+
+CREATE INDEX i ON a (id);
+`
+	got := splitStatements(script)
+	if len(got) != 2 || !strings.HasPrefix(got[0], "CREATE TABLE a") || got[1] != "CREATE INDEX i ON a (id);" {
+		t.Fatalf("splitStatements = %q", got)
+	}
+}
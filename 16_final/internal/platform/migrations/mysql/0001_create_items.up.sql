@@ -0,0 +1,13 @@
+-- Cria a tabela 'items' exatamente como no schema original (init.sql), para que bancos criados
+-- antes das migrações recebam as mudanças seguintes (0002 em diante) em vez de pulá-las.
+CREATE TABLE IF NOT EXISTS items (
+    id INT AUTO_INCREMENT PRIMARY KEY,                         -- ID autoincrementável como chave primária
+    code VARCHAR(255) NOT NULL,                                -- Código do item (SKU); a unicidade vem na 0003
+    title VARCHAR(255) NOT NULL,                               -- Título ou nome do item
+    description TEXT,                                          -- Descrição longa (opcional)
+    price DECIMAL(10, 2),                                      -- Preço com duas casas decimais
+    stock INT,                                                 -- Quantidade em estoque
+    status VARCHAR(50),                                        -- Status do item (active, inactive, discontinued)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,            -- Data de criação (valor padrão: agora)
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP -- Atualiza sempre que o registro é alterado
+);
@@ -0,0 +1,3 @@
+-- Adiciona a versão do registro (concorrência otimista / ETag); os itens existentes começam na versão 1
+ALTER TABLE items
+    ADD COLUMN version INT NOT NULL DEFAULT 1 AFTER status;        -- Versão do registro, incrementada a cada alteração
@@ -0,0 +1 @@
+ALTER TABLE items DROP INDEX uq_items_code;
@@ -0,0 +1,4 @@
+-- Garante que o código (SKU) é único. Em um banco com códigos repetidos a migração falha:
+-- corrija as duplicatas (ex: SELECT code FROM items GROUP BY code HAVING COUNT(*) > 1) e rode de novo.
+ALTER TABLE items
+    ADD UNIQUE INDEX uq_items_code (code);                         -- Também acelera a busca por código
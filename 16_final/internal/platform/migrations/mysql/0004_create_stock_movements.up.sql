@@ -0,0 +1,13 @@
+-- Cria a tabela 'stock_movements' com o histórico (ledger) de movimentações de estoque
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id INT AUTO_INCREMENT PRIMARY KEY,                         -- ID autoincrementável como chave primária
+    item_id INT NOT NULL,                                      -- Item movimentado
+    type VARCHAR(20) NOT NULL,                                 -- Tipo: receipt, sale, adjustment, return, transfer
+    delta INT NOT NULL,                                        -- Variação do estoque (positiva ou negativa)
+    stock_after INT NOT NULL,                                  -- Saldo do item após a movimentação
+    reason VARCHAR(255) NOT NULL DEFAULT '',                   -- Motivo da movimentação
+    actor VARCHAR(255) NOT NULL DEFAULT '',                    -- Quem realizou a movimentação
+    reference VARCHAR(255) NOT NULL DEFAULT '',                -- Documento de origem (pedido, nota fiscal, etc.)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,            -- Data da movimentação
+    INDEX idx_stock_movements_item (item_id, created_at)       -- Acelera a consulta do histórico por item
+);
@@ -0,0 +1,4 @@
+-- Atenção: os itens que estiverem na lixeira voltam a aparecer como ativos
+ALTER TABLE items
+    DROP INDEX idx_items_deleted_at,
+    DROP COLUMN deleted_at;
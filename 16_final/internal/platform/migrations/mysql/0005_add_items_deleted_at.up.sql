@@ -0,0 +1,4 @@
+-- Adiciona a exclusão lógica (lixeira) de itens: deleted_at preenchido = item na lixeira
+ALTER TABLE items
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL,             -- Data em que o item foi para a lixeira (NULL = ativo)
+    ADD INDEX idx_items_deleted_at (deleted_at);                   -- Acelera a listagem da lixeira e o expurgo por data
@@ -0,0 +1,2 @@
+-- Atenção: sem a coluna, toda chave de API volta a ter acesso total
+ALTER TABLE api_keys DROP COLUMN roles;
@@ -0,0 +1,12 @@
+-- Cria a tabela 'items' com o schema original (mesmas colunas da migração MySQL, com a sintaxe do SQLite)
+CREATE TABLE IF NOT EXISTS items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,                      -- ID autoincrementável como chave primária
+    code VARCHAR(255) NOT NULL,                                -- Código do item (SKU); a unicidade vem na 0003
+    title VARCHAR(255) NOT NULL,                               -- Título ou nome do item
+    description TEXT,                                          -- Descrição longa (opcional)
+    price DECIMAL(10, 2),                                      -- Preço com duas casas decimais
+    stock INT,                                                 -- Quantidade em estoque
+    status VARCHAR(50),                                        -- Status do item (active, inactive, discontinued)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,            -- Data de criação (valor padrão: agora)
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP             -- Data da última alteração (preenchida pela aplicação)
+);
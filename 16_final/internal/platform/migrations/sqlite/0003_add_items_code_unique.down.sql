@@ -0,0 +1 @@
+DROP INDEX IF EXISTS uq_items_code;
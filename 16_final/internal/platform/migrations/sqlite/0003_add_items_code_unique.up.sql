@@ -0,0 +1,3 @@
+-- Garante que o código (SKU) é único. Em um banco com códigos repetidos a migração falha:
+-- corrija as duplicatas (ex: SELECT code FROM items GROUP BY code HAVING COUNT(*) > 1) e rode de novo.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_items_code ON items (code);
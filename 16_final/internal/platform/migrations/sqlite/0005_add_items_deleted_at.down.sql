@@ -0,0 +1,3 @@
+-- Atenção: os itens que estiverem na lixeira voltam a aparecer como ativos
+DROP INDEX IF EXISTS idx_items_deleted_at;
+ALTER TABLE items DROP COLUMN deleted_at;
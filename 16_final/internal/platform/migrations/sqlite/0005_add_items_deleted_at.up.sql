@@ -0,0 +1,5 @@
+-- Adiciona a exclusão lógica (lixeira) de itens: deleted_at preenchido = item na lixeira
+ALTER TABLE items ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL;  -- Data em que o item foi para a lixeira (NULL = ativo)
+
+-- Acelera a listagem da lixeira e o expurgo por data
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at);
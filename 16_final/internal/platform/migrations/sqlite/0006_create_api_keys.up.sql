@@ -0,0 +1,9 @@
+-- Cria a tabela 'api_keys' (mesmas colunas da migração MySQL, com a sintaxe do SQLite)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,                      -- ID autoincrementável como chave primária
+    name VARCHAR(255) NOT NULL,                                -- Nome descritivo da chave
+    prefix VARCHAR(32) NOT NULL UNIQUE,                        -- Parte pública da chave, usada para localizá-la
+    hash CHAR(64) NOT NULL,                                    -- SHA-256 (hexadecimal) da chave completa; a chave não é guardada
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,            -- Data de criação
+    revoked_at TIMESTAMP NULL DEFAULT NULL                     -- Data da revogação (NULL = chave ativa)
+);
@@ -0,0 +1,3 @@
+-- Adiciona os papéis (roles) de cada chave de API, separados por vírgula (ex: "warehouse,catalog").
+-- As chaves já existentes tinham acesso total, por isso recebem o papel 'admin'.
+ALTER TABLE api_keys ADD COLUMN roles VARCHAR(255) NOT NULL DEFAULT 'admin'; -- Papéis concedidos a quem usa a chave
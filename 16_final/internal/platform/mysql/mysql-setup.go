@@ -1,6 +1,7 @@
 package mysqlsetup
 
 import (
+	"api/pkg/config"
 	gosqldriver "api/pkg/mysql/go-sql-driver"
 )
 
@@ -10,20 +11,37 @@ NewMySQLSetup configura e retorna um novo cliente MySQL.
 Essa função encapsula a criação do cliente de banco de dados.
 É usada pelo `main.go` para obter uma conexão pronta para uso.
 
+Parâmetro:
+- cfg: seção `db` da configuração (ver pkg/config), com as partes da DSN, o pool e o prazo de conexão
+
 Retorno:
 - Um ponteiro para `MySQLClient` (estrutura que provavelmente encapsula `*sql.DB`)
 - Um erro, caso a conexão falhe
 */
-func NewMySQLSetup() (*gosqldriver.MySQLClient, error) {
-	// Define as credenciais e dados de conexão com o banco MySQL
-	config := gosqldriver.MySQLClientConfig{
-		User:     "api_user",     // Nome do usuário do banco
-		Password: "api_password", // Senha do banco
-		Host:     "mysql",        // Host (nome do serviço Docker ou IP)
-		Port:     "3306",         // Porta padrão do MySQL
-		Database: "inventory",    // Nome do banco de dados a ser usado
+func NewMySQLSetup(cfg config.DBConfig) (*gosqldriver.MySQLClient, error) {
+	// Define as credenciais, o pool e a política de conexão a partir da configuração
+	clientConfig := gosqldriver.MySQLClientConfig{
+		User:            cfg.User,                          // Nome do usuário do banco
+		Password:        cfg.Password,                      // Senha do banco
+		Host:            cfg.Host,                          // Host (nome do serviço Docker ou IP)
+		Port:            cfg.Port,                          // Porta do MySQL
+		Database:        cfg.Name,                          // Nome do banco de dados a ser usado
+		TLS:             cfg.TLS,                           // Modo TLS
+		Timezone:        cfg.Timezone,                      // Fuso horário das colunas DATETIME
+		Params:          cfg.Params,                        // Parâmetros extras da DSN
+		MaxOpenConns:    cfg.MaxOpenConns,                  // Tamanho máximo do pool
+		MaxIdleConns:    cfg.MaxIdleConns,                  // Conexões ociosas mantidas no pool
+		ConnMaxLifetime: cfg.ConnMaxLifetime.Duration,      // Vida máxima de uma conexão
+		ConnMaxIdleTime: cfg.ConnMaxIdleTime.Duration,      // Tempo máximo ocioso de uma conexão
+		ConnectTimeout:  cfg.ConnectTimeout.Duration,       // Prazo para o banco responder ao subir
+		RetryInterval:   cfg.ConnectRetryInterval.Duration, // Espera inicial entre tentativas
+	}
+
+	// Cria o cliente usando o pacote go-sql-driver (aguarda o banco com backoff exponencial)
+	client, err := gosqldriver.NewMySQLClient(clientConfig)
+	if err != nil {
+		return nil, err
 	}
 
-	// Cria o cliente usando o pacote go-sql-driver
-	return gosqldriver.NewMySQLClient(config)
+	return client, nil
 }
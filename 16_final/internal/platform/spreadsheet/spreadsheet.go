@@ -0,0 +1,107 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2" // Leitura de planilhas do Excel (.xlsx)
+)
+
+/*
+Formatos de planilha suportados.
+
+- FormatCSV: texto separado por vírgula ou ponto e vírgula (como o Excel exporta em pt-BR);
+- FormatXLSX: pasta de trabalho do Excel; apenas a primeira aba é lida.
+*/
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Formats lista os formatos aceitos.
+var Formats = []string{FormatCSV, FormatXLSX}
+
+// ErrUnsupportedFormat indica um formato de planilha que não é um de Formats.
+var ErrUnsupportedFormat = errors.New("formato de planilha não suportado")
+
+// utf8BOM é a marca que o Excel coloca no início dos CSV salvos como "UTF-8".
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+/*
+FormatOf deduz o formato pela extensão do nome do arquivo (ex: "itens.XLSX" → FormatXLSX).
+Retorna "" se a extensão não corresponder a nenhum formato suportado.
+*/
+func FormatOf(filename string) string {
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), ".")); ext {
+	case FormatCSV, FormatXLSX:
+		return ext
+	default:
+		return ""
+	}
+}
+
+/*
+Read lê todas as linhas da planilha, na ordem do arquivo (a primeira costuma ser o cabeçalho).
+
+As linhas podem ter quantidades diferentes de células. Um formato fora de Formats
+retorna ErrUnsupportedFormat; um arquivo corrompido retorna o erro de leitura com contexto.
+*/
+func Read(r io.Reader, format string) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatXLSX:
+		return readXLSX(r)
+	default:
+		return nil, fmt.Errorf("%w: %q (use %s)", ErrUnsupportedFormat, format, strings.Join(Formats, " ou "))
+	}
+}
+
+/*
+readCSV lê um CSV, descartando o BOM do UTF-8 e detectando o separador pela primeira
+linha: ponto e vírgula se ele aparecer mais que a vírgula, vírgula caso contrário.
+*/
+func readCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler o CSV: %w", err)
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	first, _, _ := bytes.Cut(data, []byte("\n"))
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		cr.Comma = ';'
+	}
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV inválido: %w", err)
+	}
+	return rows, nil
+}
+
+// readXLSX lê a primeira aba de uma pasta de trabalho do Excel, com os valores como são exibidos.
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("XLSX inválido: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	rows, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler a aba %q do XLSX: %w", sheets[0], err)
+	}
+	return rows, nil
+}
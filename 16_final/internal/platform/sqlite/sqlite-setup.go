@@ -0,0 +1,40 @@
+package sqlitesetup
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite" // Driver SQLite em Go puro (não exige CGO)
+)
+
+/*
+NewSQLiteSetup abre (ou cria) o banco SQLite.
+
+O schema não é criado aqui: ele vem das migrações em internal/platform/migrations
+(`cli migrate up` ou MIGRATE_ON_START=true).
+
+Parâmetro:
+- path: caminho do arquivo do banco, ou ":memory:" para um banco temporário em memória
+
+Observações:
+  - O pool é limitado a uma conexão: o SQLite só aceita um escritor por vez e,
+    com ":memory:", cada conexão teria o seu próprio banco vazio.
+  - O busy_timeout faz o SQLite aguardar um lock em vez de falhar imediatamente.
+
+Retorno:
+- A conexão `*sql.DB` pronta para item.NewSQLiteRepository
+- Um erro, caso a abertura falhe
+*/
+func NewSQLiteSetup(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir o SQLite: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("falha ao verificar o SQLite: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv é a variável de ambiente com o caminho do arquivo de configuração.
+const ConfigFileEnv = "CONFIG_FILE"
+
+/*
+Load monta a configuração final: padrões → arquivo → variáveis de ambiente.
+
+Parâmetro:
+  - path: caminho de um arquivo .yaml/.yml ou .toml. Se vazio, usa a variável
+    CONFIG_FILE; se ela também estiver vazia, nenhum arquivo é lido.
+
+Retorna a configuração já validada, ou erro se o arquivo, alguma variável
+de ambiente ou a validação falhar.
+*/
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv(ConfigFileEnv)
+	}
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := LoadEnv(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+/*
+loadFile lê o arquivo de configuração sobre cfg, escolhendo o formato pela extensão.
+
+Campos ausentes no arquivo mantêm o valor que já estava em cfg (padrões).
+*/
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("falha ao ler arquivo de configuração: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("formato de configuração não suportado %q (use .yaml, .yml ou .toml)", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao interpretar %s: %w", path, err)
+	}
+	return nil
+}
+
+/*
+LoadEnv sobrescreve cfg com as variáveis de ambiente definidas.
+
+Variáveis suportadas:
+
+	HTTP_ADDR, HTTP_READ_TIMEOUT, HTTP_WRITE_TIMEOUT
+	HTTP_REQUEST_TIMEOUT, HTTP_ROUTE_TIMEOUTS, HTTP_EXPORT_TIMEOUT, HTTP_DRAIN_TIMEOUT
+	GRPC_ADDR
+	DB_USER, DB_PASSWORD, DB_HOST, DB_PORT, DB_SOCKET, DB_NAME
+	DB_TLS, DB_TLS_CA_FILE, DB_TLS_CERT_FILE, DB_TLS_KEY_FILE, DB_TLS_SERVER_NAME
+	DB_TIMEZONE, DB_PARAMS, DB_REPLICAS
+	DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME, DB_CONN_MAX_IDLE_TIME
+	DB_CONNECT_TIMEOUT, DB_CONNECT_RETRY_INTERVAL
+	SQLITE_PATH
+	LOG_LEVEL, REPOSITORY_BACKEND, MIGRATE_ON_START, TRASH_RETENTION
+	AUTH_ENABLED, AUTH_JWT_HS256_SECRET, AUTH_JWT_RS256_PUBLIC_KEY_FILE, AUTH_JWT_JWKS_FILE
+	AUTH_JWT_ISSUER, AUTH_JWT_AUDIENCE, AUTH_JWT_LEEWAY
+
+Durações usam o formato de time.ParseDuration (ex: "5s", "1m").
+HTTP_ROUTE_TIMEOUTS é uma lista "rota=duração" separada por vírgulas,
+ex: "GET /items=2s,POST /items/:id/stock/adjust=5s".
+DB_PARAMS é uma lista "chave=valor" separada por vírgulas, ex: "readTimeout=5s,writeTimeout=5s".
+DB_REPLICAS é uma lista "host:porta" separada por vírgulas, ex: "replica1:3306,replica2:3306".
+*/
+func LoadEnv(cfg *Config) error {
+	e := envReader{}
+
+	e.string("HTTP_ADDR", &cfg.HTTP.Addr)
+	e.duration("HTTP_READ_TIMEOUT", &cfg.HTTP.ReadTimeout)
+	e.duration("HTTP_WRITE_TIMEOUT", &cfg.HTTP.WriteTimeout)
+	e.duration("HTTP_REQUEST_TIMEOUT", &cfg.HTTP.RequestTimeout)
+	e.durationMap("HTTP_ROUTE_TIMEOUTS", &cfg.HTTP.RouteTimeouts)
+	e.duration("HTTP_EXPORT_TIMEOUT", &cfg.HTTP.ExportTimeout)
+	e.duration("HTTP_DRAIN_TIMEOUT", &cfg.HTTP.DrainTimeout)
+
+	e.string("GRPC_ADDR", &cfg.GRPC.Addr)
+
+	e.string("DB_USER", &cfg.DB.User)
+	e.string("DB_PASSWORD", &cfg.DB.Password)
+	e.string("DB_HOST", &cfg.DB.Host)
+	e.string("DB_PORT", &cfg.DB.Port)
+	e.string("DB_SOCKET", &cfg.DB.Socket)
+	e.string("DB_NAME", &cfg.DB.Name)
+	e.string("DB_TLS", &cfg.DB.TLS)
+	e.string("DB_TLS_CA_FILE", &cfg.DB.TLSCAFile)
+	e.string("DB_TLS_CERT_FILE", &cfg.DB.TLSCertFile)
+	e.string("DB_TLS_KEY_FILE", &cfg.DB.TLSKeyFile)
+	e.string("DB_TLS_SERVER_NAME", &cfg.DB.TLSServerName)
+	e.string("DB_TIMEZONE", &cfg.DB.Timezone)
+	e.stringMap("DB_PARAMS", &cfg.DB.Params)
+	e.stringList("DB_REPLICAS", &cfg.DB.Replicas)
+	e.int("DB_MAX_OPEN_CONNS", &cfg.DB.MaxOpenConns)
+	e.int("DB_MAX_IDLE_CONNS", &cfg.DB.MaxIdleConns)
+	e.duration("DB_CONN_MAX_LIFETIME", &cfg.DB.ConnMaxLifetime)
+	e.duration("DB_CONN_MAX_IDLE_TIME", &cfg.DB.ConnMaxIdleTime)
+	e.duration("DB_CONNECT_TIMEOUT", &cfg.DB.ConnectTimeout)
+	e.duration("DB_CONNECT_RETRY_INTERVAL", &cfg.DB.ConnectRetryInterval)
+
+	e.string("SQLITE_PATH", &cfg.SQLite.Path)
+
+	e.string("LOG_LEVEL", &cfg.LogLevel)
+	e.string("REPOSITORY_BACKEND", &cfg.Repository)
+	e.bool("MIGRATE_ON_START", &cfg.MigrateOnStart)
+	e.duration("TRASH_RETENTION", &cfg.TrashRetention)
+
+	e.bool("AUTH_ENABLED", &cfg.Auth.Enabled)
+	e.string("AUTH_JWT_HS256_SECRET", &cfg.Auth.JWT.HS256Secret)
+	e.string("AUTH_JWT_RS256_PUBLIC_KEY_FILE", &cfg.Auth.JWT.RS256PublicKeyFile)
+	e.string("AUTH_JWT_JWKS_FILE", &cfg.Auth.JWT.JWKSFile)
+	e.string("AUTH_JWT_ISSUER", &cfg.Auth.JWT.Issuer)
+	e.string("AUTH_JWT_AUDIENCE", &cfg.Auth.JWT.Audience)
+	e.duration("AUTH_JWT_LEEWAY", &cfg.Auth.JWT.Leeway)
+
+	return e.err
+}
+
+/*
+envReader lê variáveis de ambiente para campos tipados, guardando o primeiro erro
+de conversão para que LoadEnv possa ser escrito sem um `if err` por variável.
+*/
+type envReader struct {
+	err error
+}
+
+func (e *envReader) string(name string, dst *string) {
+	if v, ok := os.LookupEnv(name); ok {
+		*dst = v
+	}
+}
+
+func (e *envReader) stringList(name string, dst *[]string) {
+	v, ok := os.LookupEnv(name)
+	if !ok || e.err != nil {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func (e *envReader) stringMap(name string, dst *map[string]string) {
+	v, ok := os.LookupEnv(name)
+	if !ok || e.err != nil {
+		return
+	}
+	m := map[string]string{}
+	for _, pair := range strings.Split(v, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			e.err = fmt.Errorf("variável %s inválida %q: esperado \"chave=valor\" separados por vírgula", name, pair)
+			return
+		}
+		m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	*dst = m
+}
+
+func (e *envReader) int(name string, dst *int) {
+	v, ok := os.LookupEnv(name)
+	if !ok || e.err != nil {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		e.err = fmt.Errorf("variável %s inválida %q: esperado um número inteiro", name, v)
+		return
+	}
+	*dst = n
+}
+
+func (e *envReader) bool(name string, dst *bool) {
+	v, ok := os.LookupEnv(name)
+	if !ok || e.err != nil {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		e.err = fmt.Errorf("variável %s inválida %q: esperado true ou false", name, v)
+		return
+	}
+	*dst = b
+}
+
+func (e *envReader) duration(name string, dst *Duration) {
+	v, ok := os.LookupEnv(name)
+	if !ok || e.err != nil {
+		return
+	}
+	if err := dst.UnmarshalText([]byte(v)); err != nil {
+		e.err = fmt.Errorf("variável %s inválida %q: esperado uma duração (ex: 5s)", name, v)
+	}
+}
+
+func (e *envReader) durationMap(name string, dst *map[string]Duration) {
+	v, ok := os.LookupEnv(name)
+	if !ok || e.err != nil {
+		return
+	}
+	m := map[string]Duration{}
+	for _, pair := range strings.Split(v, ",") {
+		route, value, found := strings.Cut(pair, "=")
+		var d Duration
+		if !found || d.UnmarshalText([]byte(strings.TrimSpace(value))) != nil {
+			e.err = fmt.Errorf("variável %s inválida %q: esperado \"MÉTODO /caminho=duração\" separados por vírgula", name, pair)
+			return
+		}
+		m[strings.TrimSpace(route)] = d
+	}
+	*dst = m
+}
@@ -1,12 +1,301 @@
 package config
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
 
 /*
-	ErrNotFound é um erro reutilizável que representa a situação
-	em que um recurso (ex: item) não foi encontrado.
+Backends de repositório aceitos em Config.Repository.
+*/
+const (
+	BackendMySQL  = "mysql"  // Repositório MySQL (padrão)
+	BackendMemory = "memory" // Repositório em memória, sem banco de dados
+	BackendSQLite = "sqlite" // Repositório SQLite em arquivo, sem contêiner MySQL
+)
+
+// Backends lista os valores aceitos em Config.Repository.
+var Backends = []string{BackendMySQL, BackendMemory, BackendSQLite}
+
+// LogLevels lista os níveis de log aceitos em Config.LogLevel.
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+/*
+Config é a configuração tipada de todos os entrypoints (REST, gRPC e CLI).
+
+Os valores são resolvidos nesta ordem (o último vence):
+ 1. Valores padrão (Default);
+ 2. Arquivo YAML ou TOML opcional (ver Load);
+ 3. Variáveis de ambiente (ver LoadEnv).
+*/
+type Config struct {
+	HTTP           HTTPConfig   `yaml:"http" toml:"http"`                         // Servidor REST
+	GRPC           GRPCConfig   `yaml:"grpc" toml:"grpc"`                         // Servidor gRPC
+	DB             DBConfig     `yaml:"db" toml:"db"`                             // Banco de dados MySQL
+	SQLite         SQLiteConfig `yaml:"sqlite" toml:"sqlite"`                     // Banco de dados SQLite
+	LogLevel       string       `yaml:"log_level" toml:"log_level"`               // debug, info, warn ou error
+	Repository     string       `yaml:"repository" toml:"repository"`             // Backend do repositório: mysql, memory ou sqlite
+	MigrateOnStart bool         `yaml:"migrate_on_start" toml:"migrate_on_start"` // Aplica as migrações pendentes ao subir o servidor
+	TrashRetention Duration     `yaml:"trash_retention" toml:"trash_retention"`   // Tempo na lixeira antes de o item poder ser expurgado (items purge)
+	Auth           AuthConfig   `yaml:"auth" toml:"auth"`                         // Autenticação da API REST
+}
+
+/*
+HTTPConfig contém as configurações do servidor REST.
+*/
+type HTTPConfig struct {
+	Addr           string              `yaml:"addr" toml:"addr"`                       // Endereço de escuta (ex: :8080)
+	ReadTimeout    Duration            `yaml:"read_timeout" toml:"read_timeout"`       // Tempo máximo para ler a requisição
+	WriteTimeout   Duration            `yaml:"write_timeout" toml:"write_timeout"`     // Tempo máximo para escrever a resposta
+	RequestTimeout Duration            `yaml:"request_timeout" toml:"request_timeout"` // Prazo padrão de cada requisição (0 = sem prazo)
+	RouteTimeouts  map[string]Duration `yaml:"route_timeouts" toml:"route_timeouts"`   // Prazo por rota, ex: "GET /items": 2s
+	ExportTimeout  Duration            `yaml:"export_timeout" toml:"export_timeout"`   // Prazo de GET /items/export, inclusive a escrita do download (0 = sem prazo)
+	DrainTimeout   Duration            `yaml:"drain_timeout" toml:"drain_timeout"`     // Tempo para concluir as requisições em andamento ao desligar
+}
+
+// ExportRoute é a rota de exportação do catálogo, cujo prazo padrão é ExportTimeout.
+const ExportRoute = "GET /items/export"
+
+/*
+TimeoutFor retorna o prazo da rota ("MÉTODO /caminho", com o caminho como registrado
+no roteador, ex: "GET /items/:id"), ou RequestTimeout se a rota não tiver prazo próprio.
+A rota de exportação (ExportRoute), que pode levar minutos, usa ExportTimeout como padrão.
+*/
+func (h HTTPConfig) TimeoutFor(route string) time.Duration {
+	if d, ok := h.RouteTimeouts[route]; ok {
+		return d.Duration
+	}
+	if route == ExportRoute {
+		return h.ExportTimeout.Duration
+	}
+	return h.RequestTimeout.Duration
+}
+
+/*
+GRPCConfig contém as configurações do servidor gRPC.
+*/
+type GRPCConfig struct {
+	Addr string `yaml:"addr" toml:"addr"` // Endereço de escuta (ex: :50051)
+}
+
+/*
+DBConfig contém as partes da DSN, o pool de conexões e a política de conexão inicial do MySQL.
+*/
+type DBConfig struct {
+	User                 string            `yaml:"user" toml:"user"`                                     // Usuário do banco
+	Password             string            `yaml:"password" toml:"password"`                             // Senha do usuário
+	Host                 string            `yaml:"host" toml:"host"`                                     // Host (nome do serviço Docker ou IP)
+	Port                 string            `yaml:"port" toml:"port"`                                     // Porta (normalmente 3306)
+	Socket               string            `yaml:"socket" toml:"socket"`                                 // Socket unix; se informado, substitui host e port
+	Name                 string            `yaml:"name" toml:"name"`                                     // Nome do banco de dados
+	TLS                  string            `yaml:"tls" toml:"tls"`                                       // Modo TLS do driver: "", true, false, skip-verify ou preferred
+	TLSCAFile            string            `yaml:"tls_ca_file" toml:"tls_ca_file"`                       // CA (PEM) para validar o servidor
+	TLSCertFile          string            `yaml:"tls_cert_file" toml:"tls_cert_file"`                   // Certificado do cliente (PEM), para TLS mútuo
+	TLSKeyFile           string            `yaml:"tls_key_file" toml:"tls_key_file"`                     // Chave do certificado do cliente (PEM)
+	TLSServerName        string            `yaml:"tls_server_name" toml:"tls_server_name"`               // Nome esperado no certificado do servidor (padrão: host)
+	Timezone             string            `yaml:"timezone" toml:"timezone"`                             // Fuso horário das colunas DATETIME (padrão: UTC)
+	Params               map[string]string `yaml:"params" toml:"params"`                                 // Parâmetros extras da DSN (ex: readTimeout: 5s)
+	Replicas             []string          `yaml:"replicas" toml:"replicas"`                             // Réplicas somente leitura ("host:porta"), usadas nas listagens
+	MaxOpenConns         int               `yaml:"max_open_conns" toml:"max_open_conns"`                 // Máximo de conexões abertas (0 = ilimitado)
+	MaxIdleConns         int               `yaml:"max_idle_conns" toml:"max_idle_conns"`                 // Máximo de conexões ociosas no pool
+	ConnMaxLifetime      Duration          `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`           // Tempo máximo de vida de uma conexão
+	ConnMaxIdleTime      Duration          `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`         // Tempo máximo de uma conexão ociosa no pool
+	ConnectTimeout       Duration          `yaml:"connect_timeout" toml:"connect_timeout"`               // Prazo para o banco responder ao subir (0 = uma tentativa)
+	ConnectRetryInterval Duration          `yaml:"connect_retry_interval" toml:"connect_retry_interval"` // Espera inicial entre tentativas (dobra a cada falha)
+}
+
+/*
+SQLiteConfig contém as configurações do backend SQLite.
+*/
+type SQLiteConfig struct {
+	Path string `yaml:"path" toml:"path"` // Arquivo do banco, ou ":memory:" para um banco temporário
+}
+
+/*
+AuthConfig contém as configurações de autenticação da API REST.
+
+Com Enabled, toda rota (exceto /healthz e /readyz) exige uma chave de API (header
+X-API-Key ou Authorization: Bearer) ou um token JWT (Authorization: Bearer).
+As chaves de API ficam no banco e são gerenciadas pela CLI (`cli keys`).
+*/
+type AuthConfig struct {
+	Enabled bool      `yaml:"enabled" toml:"enabled"` // Exige credencial nas rotas da API REST (padrão true)
+	JWT     JWTConfig `yaml:"jwt" toml:"jwt"`         // Chaves e claims esperados nos tokens JWT
+}
+
+/*
+JWTConfig contém as chaves usadas para validar tokens JWT (HS256 e RS256) e os claims exigidos.
+
+Nenhuma chave configurada desabilita a autenticação por JWT (apenas chaves de API são aceitas).
+*/
+type JWTConfig struct {
+	HS256Secret        string   `yaml:"hs256_secret" toml:"hs256_secret"`                   // Segredo compartilhado dos tokens HS256 (mínimo 32 bytes)
+	RS256PublicKeyFile string   `yaml:"rs256_public_key_file" toml:"rs256_public_key_file"` // Chave pública RSA (PEM) dos tokens RS256
+	JWKSFile           string   `yaml:"jwks_file" toml:"jwks_file"`                         // Arquivo JWKS local com as chaves (escolhidas pelo `kid` do token)
+	Issuer             string   `yaml:"issuer" toml:"issuer"`                               // Valor exigido no claim `iss` (vazio = não confere)
+	Audience           string   `yaml:"audience" toml:"audience"`                           // Valor exigido no claim `aud` (vazio = não confere)
+	Leeway             Duration `yaml:"leeway" toml:"leeway"`                               // Tolerância de relógio ao conferir `exp` e `nbf`
+}
+
+// Configured indica se alguma chave de validação de JWT foi informada.
+func (j JWTConfig) Configured() bool {
+	return j.HS256Secret != "" || j.RS256PublicKeyFile != "" || j.JWKSFile != ""
+}
+
+// MinHS256SecretLen é o tamanho mínimo de um segredo HS256 (256 bits, o tamanho do hash), também exigido das chaves oct do JWKS.
+const MinHS256SecretLen = 32
+
+/*
+Default retorna a configuração padrão, equivalente ao ambiente do docker-compose.yml.
+*/
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Addr:           ":8080",
+			ReadTimeout:    Duration{10 * time.Second},
+			WriteTimeout:   Duration{30 * time.Second},
+			RequestTimeout: Duration{15 * time.Second},
+			ExportTimeout:  Duration{10 * time.Minute},
+			DrainTimeout:   Duration{20 * time.Second},
+		},
+		GRPC: GRPCConfig{
+			Addr: ":50051",
+		},
+		DB: DBConfig{
+			User:                 "api_user",
+			Password:             "api_password",
+			Host:                 "mysql",
+			Port:                 "3306",
+			Name:                 "inventory",
+			MaxOpenConns:         25,
+			MaxIdleConns:         25,
+			ConnMaxLifetime:      Duration{5 * time.Minute},
+			ConnMaxIdleTime:      Duration{time.Minute},
+			ConnectTimeout:       Duration{30 * time.Second},
+			ConnectRetryInterval: Duration{500 * time.Millisecond},
+		},
+		SQLite: SQLiteConfig{
+			Path: "inventory.db",
+		},
+		LogLevel:       "info",
+		Repository:     BackendMySQL,
+		TrashRetention: Duration{30 * 24 * time.Hour},
+		Auth: AuthConfig{
+			Enabled: true,
+			JWT: JWTConfig{
+				Leeway: Duration{30 * time.Second},
+			},
+		},
+	}
+}
 
-	Ele pode ser usado em qualquer lugar da aplicação (repositórios, usecases, handlers),
-	permitindo que a camada superior (ex: handler HTTP) saiba que deve responder com 404 (Not Found).
+/*
+Validate verifica se a configuração é utilizável.
+
+Retorna um único erro listando todos os problemas encontrados.
+*/
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.HTTP.Addr == "" {
+		errs = append(errs, errors.New("http.addr é obrigatório"))
+	}
+	if c.GRPC.Addr == "" {
+		errs = append(errs, errors.New("grpc.addr é obrigatório"))
+	}
+	if c.HTTP.ReadTimeout.Duration < 0 || c.HTTP.WriteTimeout.Duration < 0 || c.HTTP.RequestTimeout.Duration < 0 ||
+		c.HTTP.ExportTimeout.Duration < 0 || c.HTTP.DrainTimeout.Duration < 0 {
+		errs = append(errs, errors.New("timeouts http não podem ser negativos"))
+	}
+	for route, d := range c.HTTP.RouteTimeouts {
+		if method, path, ok := strings.Cut(route, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("http.route_timeouts: rota inválida %q (use \"MÉTODO /caminho\")", route))
+		}
+		if d.Duration < 0 {
+			errs = append(errs, fmt.Errorf("http.route_timeouts: prazo negativo para %q", route))
+		}
+	}
+	if !slices.Contains(LogLevels, c.LogLevel) {
+		errs = append(errs, fmt.Errorf("log_level inválido %q (use %s)", c.LogLevel, strings.Join(LogLevels, ", ")))
+	}
+	if !slices.Contains(Backends, c.Repository) {
+		errs = append(errs, fmt.Errorf("repository inválido %q (use %s)", c.Repository, strings.Join(Backends, ", ")))
+	}
+
+	if c.TrashRetention.Duration < 0 {
+		errs = append(errs, errors.New("trash_retention não pode ser negativo"))
+	}
+
+	if c.Auth.JWT.HS256Secret != "" && len(c.Auth.JWT.HS256Secret) < MinHS256SecretLen {
+		errs = append(errs, fmt.Errorf("auth.jwt.hs256_secret deve ter pelo menos %d bytes", MinHS256SecretLen))
+	}
+	if c.Auth.JWT.Leeway.Duration < 0 {
+		errs = append(errs, errors.New("auth.jwt.leeway não pode ser negativo"))
+	}
+
+	if c.Repository == BackendMySQL {
+		if c.DB.User == "" || c.DB.Name == "" || (c.DB.Socket == "" && (c.DB.Host == "" || c.DB.Port == "")) {
+			errs = append(errs, errors.New("db.user, db.name e db.host/db.port (ou db.socket) são obrigatórios para o repositório mysql"))
+		}
+		if (c.DB.TLSCertFile == "") != (c.DB.TLSKeyFile == "") {
+			errs = append(errs, errors.New("db.tls_cert_file e db.tls_key_file devem ser informados juntos"))
+		}
+		for _, addr := range c.DB.Replicas {
+			if host, port, err := net.SplitHostPort(addr); err != nil || host == "" || port == "" {
+				errs = append(errs, fmt.Errorf("db.replicas: endereço inválido %q (use \"host:porta\")", addr))
+			}
+		}
+	}
+	if c.Repository == BackendSQLite && c.SQLite.Path == "" {
+		errs = append(errs, errors.New("sqlite.path é obrigatório para o repositório sqlite"))
+	}
+	if c.DB.MaxOpenConns < 0 || c.DB.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("tamanhos do pool não podem ser negativos"))
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		errs = append(errs, errors.New("db.max_idle_conns não pode ser maior que db.max_open_conns"))
+	}
+	if c.DB.ConnMaxLifetime.Duration < 0 || c.DB.ConnMaxIdleTime.Duration < 0 {
+		errs = append(errs, errors.New("db.conn_max_lifetime e db.conn_max_idle_time não podem ser negativos"))
+	}
+	if c.DB.ConnectTimeout.Duration < 0 || c.DB.ConnectRetryInterval.Duration < 0 {
+		errs = append(errs, errors.New("db.connect_timeout e db.connect_retry_interval não podem ser negativos"))
+	}
+	if c.DB.Timezone != "" {
+		if _, err := time.LoadLocation(c.DB.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("db.timezone inválido %q", c.DB.Timezone))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("configuração inválida: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+/*
+Duration é um time.Duration que pode ser lido como texto ("5s", "1m30s")
+tanto em YAML quanto em TOML e nas variáveis de ambiente.
 */
-var ErrNotFound = errors.New("not found")
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText converte textos como "5s" usando time.ParseDuration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalText escreve a duração no mesmo formato aceito por UnmarshalText.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.Duration.String()), nil
+}
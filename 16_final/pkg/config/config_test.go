@@ -0,0 +1,267 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv remove do ambiente do teste as variáveis lidas por Load, restaurando-as ao final.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	prefixes := []string{"HTTP_", "GRPC_", "DB_", "SQLITE_", "AUTH_", "LOG_LEVEL", "REPOSITORY_BACKEND", "MIGRATE_ON_START", "TRASH_RETENTION", ConfigFileEnv}
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, p := range prefixes {
+			if strings.HasPrefix(name, p) {
+				t.Setenv(name, "") // Registra a restauração do valor original
+				os.Unsetenv(name)
+				break
+			}
+		}
+	}
+}
+
+// writeFile grava um arquivo de configuração temporário e devolve o caminho.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("falha ao gravar %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string            // Nome do arquivo de configuração (vazio = nenhum)
+		content string            // Conteúdo do arquivo
+		env     map[string]string // Variáveis de ambiente do caso
+		viaEnv  bool              // Informa o arquivo por CONFIG_FILE em vez do parâmetro de Load
+		check   func(t *testing.T, cfg Config)
+		wantErr string // Trecho esperado na mensagem de erro (vazio = sucesso)
+	}{
+		{
+			name: "apenas padrões",
+			check: func(t *testing.T, cfg Config) {
+				if !reflect.DeepEqual(cfg, Default()) {
+					t.Fatalf("cfg = %+v, esperado Default()", cfg)
+				}
+			},
+		},
+		{
+			name: "arquivo yaml sobre os padrões",
+			file: "config.yaml",
+			content: `
+http:
+  addr: ":9000"
+  route_timeouts:
+    "GET /items": 2s
+db:
+  host: db.interno
+  replicas: ["r1:3306"]
+trash_retention: 48h
+`,
+			check: func(t *testing.T, cfg Config) {
+				if cfg.HTTP.Addr != ":9000" || cfg.DB.Host != "db.interno" || cfg.TrashRetention.Duration != 48*time.Hour {
+					t.Fatalf("valores do arquivo não aplicados: %+v", cfg)
+				}
+				if cfg.HTTP.TimeoutFor("GET /items") != 2*time.Second || !reflect.DeepEqual(cfg.DB.Replicas, []string{"r1:3306"}) {
+					t.Fatalf("route_timeouts = %v, replicas = %v", cfg.HTTP.RouteTimeouts, cfg.DB.Replicas)
+				}
+				// Campos ausentes no arquivo mantêm o padrão
+				if cfg.GRPC.Addr != ":50051" || cfg.DB.Port != "3306" || cfg.HTTP.ReadTimeout.Duration != 10*time.Second {
+					t.Fatalf("padrões perdidos: %+v", cfg)
+				}
+			},
+		},
+		{
+			name:    "arquivo toml",
+			file:    "config.toml",
+			content: "repository = \"sqlite\"\nmigrate_on_start = true\n\n[sqlite]\npath = \"/tmp/itens.db\"\n",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Repository != BackendSQLite || !cfg.MigrateOnStart || cfg.SQLite.Path != "/tmp/itens.db" {
+					t.Fatalf("valores do arquivo não aplicados: %+v", cfg)
+				}
+			},
+		},
+		{
+			name:    "arquivo por CONFIG_FILE",
+			file:    "config.yml",
+			content: "log_level: debug\n",
+			viaEnv:  true,
+			check: func(t *testing.T, cfg Config) {
+				if cfg.LogLevel != "debug" {
+					t.Fatalf("log_level = %q, esperado o do arquivo de CONFIG_FILE", cfg.LogLevel)
+				}
+			},
+		},
+		{
+			name:    "ambiente sobrescreve o arquivo",
+			file:    "config.yaml",
+			content: "http:\n  addr: \":9000\"\ndb:\n  host: db.interno\n",
+			env:     map[string]string{"HTTP_ADDR": ":9100"},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.HTTP.Addr != ":9100" || cfg.DB.Host != "db.interno" {
+					t.Fatalf("http.addr = %q, db.host = %q; esperado :9100 (ambiente) e db.interno (arquivo)", cfg.HTTP.Addr, cfg.DB.Host)
+				}
+			},
+		},
+		{
+			name: "durações, booleanos, números, listas e mapas do ambiente",
+			env: map[string]string{
+				"HTTP_READ_TIMEOUT":   "2s",
+				"HTTP_ROUTE_TIMEOUTS": "GET /items=3s, POST /items/:id/stock/adjust=1m",
+				"DB_MAX_OPEN_CONNS":   "50",
+				"DB_REPLICAS":         "r1:3306, r2:3306,",
+				"DB_PARAMS":           "readTimeout=5s,writeTimeout=5s",
+				"MIGRATE_ON_START":    "true",
+				"AUTH_ENABLED":        "false",
+				"AUTH_JWT_LEEWAY":     "1m30s",
+			},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.HTTP.ReadTimeout.Duration != 2*time.Second || cfg.Auth.JWT.Leeway.Duration != 90*time.Second {
+					t.Fatalf("durações = %v e %v", cfg.HTTP.ReadTimeout, cfg.Auth.JWT.Leeway)
+				}
+				wantRoutes := map[string]Duration{"GET /items": {3 * time.Second}, "POST /items/:id/stock/adjust": {time.Minute}}
+				if !reflect.DeepEqual(cfg.HTTP.RouteTimeouts, wantRoutes) {
+					t.Fatalf("route_timeouts = %v", cfg.HTTP.RouteTimeouts)
+				}
+				if cfg.DB.MaxOpenConns != 50 || !cfg.MigrateOnStart || cfg.Auth.Enabled {
+					t.Fatalf("max_open_conns = %d, migrate_on_start = %v, auth.enabled = %v", cfg.DB.MaxOpenConns, cfg.MigrateOnStart, cfg.Auth.Enabled)
+				}
+				if !reflect.DeepEqual(cfg.DB.Replicas, []string{"r1:3306", "r2:3306"}) {
+					t.Fatalf("replicas = %q", cfg.DB.Replicas)
+				}
+				if !reflect.DeepEqual(cfg.DB.Params, map[string]string{"readTimeout": "5s", "writeTimeout": "5s"}) {
+					t.Fatalf("params = %v", cfg.DB.Params)
+				}
+			},
+		},
+		{name: "duração inválida no ambiente", env: map[string]string{"HTTP_READ_TIMEOUT": "5"}, wantErr: "HTTP_READ_TIMEOUT"},
+		{name: "booleano inválido no ambiente", env: map[string]string{"MIGRATE_ON_START": "talvez"}, wantErr: "MIGRATE_ON_START"},
+		{name: "número inválido no ambiente", env: map[string]string{"DB_MAX_OPEN_CONNS": "muitas"}, wantErr: "DB_MAX_OPEN_CONNS"},
+		{name: "mapa inválido no ambiente", env: map[string]string{"DB_PARAMS": "readTimeout"}, wantErr: "DB_PARAMS"},
+		{name: "prazo por rota inválido no ambiente", env: map[string]string{"HTTP_ROUTE_TIMEOUTS": "GET /items=logo"}, wantErr: "HTTP_ROUTE_TIMEOUTS"},
+		{name: "duração inválida no arquivo", file: "config.yaml", content: "http:\n  read_timeout: sempre\n", wantErr: "falha ao interpretar"},
+		{name: "extensão desconhecida", file: "config.json", content: "{}", wantErr: "formato de configuração não suportado"},
+		{name: "usuário do mysql ausente", env: map[string]string{"DB_USER": ""}, wantErr: "db.user"},
+		{name: "caminho do sqlite ausente", env: map[string]string{"REPOSITORY_BACKEND": "sqlite", "SQLITE_PATH": ""}, wantErr: "sqlite.path"},
+		{name: "backend desconhecido", env: map[string]string{"REPOSITORY_BACKEND": "postgres"}, wantErr: "repository inválido"},
+		{name: "sem banco, sem exigências do mysql", env: map[string]string{"REPOSITORY_BACKEND": "memory", "DB_USER": ""}, check: func(t *testing.T, cfg Config) {
+			if cfg.Repository != BackendMemory {
+				t.Fatalf("repository = %q", cfg.Repository)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			var path string
+			if tt.file != "" {
+				path = writeFile(t, tt.file, tt.content)
+			}
+			if tt.viaEnv {
+				t.Setenv(ConfigFileEnv, path)
+				path = ""
+			}
+
+			cfg, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load = %v, esperado erro com %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	clearEnv(t)
+	if _, err := Load(filepath.Join(t.TempDir(), "nao-existe.yaml")); err == nil || !strings.Contains(err.Error(), "falha ao ler") {
+		t.Fatalf("Load de arquivo inexistente = %v, esperado erro de leitura", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr []string // Trechos esperados na mensagem (vazio = configuração válida)
+	}{
+		{"padrão", func(c *Config) {}, nil},
+		{"mysql por socket, sem host", func(c *Config) { c.DB.Host, c.DB.Port, c.DB.Socket = "", "", "/run/mysqld.sock" }, nil},
+		{"endereços ausentes", func(c *Config) { c.HTTP.Addr, c.GRPC.Addr = "", "" }, []string{"http.addr", "grpc.addr"}},
+		{"timeout http negativo", func(c *Config) { c.HTTP.WriteTimeout.Duration = -time.Second }, []string{"timeouts http"}},
+		{"rota sem método", func(c *Config) { c.HTTP.RouteTimeouts = map[string]Duration{"/items": {time.Second}} }, []string{"rota inválida"}},
+		{"prazo de rota negativo", func(c *Config) { c.HTTP.RouteTimeouts = map[string]Duration{"GET /items": {-time.Second}} }, []string{"prazo negativo"}},
+		{"nível de log", func(c *Config) { c.LogLevel = "trace" }, []string{"log_level inválido"}},
+		{"retenção negativa", func(c *Config) { c.TrashRetention.Duration = -time.Hour }, []string{"trash_retention"}},
+		{"segredo hs256 curto", func(c *Config) { c.Auth.JWT.HS256Secret = "curto" }, []string{"hs256_secret"}},
+		{"leeway negativo", func(c *Config) { c.Auth.JWT.Leeway.Duration = -time.Second }, []string{"auth.jwt.leeway"}},
+		{"certificado sem chave", func(c *Config) { c.DB.TLSCertFile = "client.pem" }, []string{"tls_cert_file"}},
+		{"réplica sem porta", func(c *Config) { c.DB.Replicas = []string{"replica1"} }, []string{"db.replicas"}},
+		{"pool", func(c *Config) { c.DB.MaxOpenConns, c.DB.MaxIdleConns = 5, 10 }, []string{"max_idle_conns"}},
+		{"pool negativo", func(c *Config) { c.DB.MaxOpenConns = -1 }, []string{"pool"}},
+		{"fuso desconhecido", func(c *Config) { c.DB.Timezone = "Marte/Olympus" }, []string{"db.timezone"}},
+		{"vários problemas de uma vez", func(c *Config) {
+			c.LogLevel = "trace"
+			c.Repository = "postgres"
+			c.DB.ConnMaxLifetime.Duration = -time.Second
+		}, []string{"log_level", "repository", "conn_max_lifetime"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(&cfg)
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate = nil, esperado erro com %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate = %v, esperado %q na mensagem", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeoutFor(t *testing.T) {
+	h := Default().HTTP
+	h.RouteTimeouts = map[string]Duration{"GET /items": {2 * time.Second}}
+
+	tests := []struct {
+		route string
+		want  time.Duration
+	}{
+		{"GET /items", 2 * time.Second},
+		{"GET /items/:id", h.RequestTimeout.Duration},
+		{ExportRoute, h.ExportTimeout.Duration},
+	}
+	for _, tt := range tests {
+		if got := h.TimeoutFor(tt.route); got != tt.want {
+			t.Errorf("TimeoutFor(%q) = %v, esperado %v", tt.route, got, tt.want)
+		}
+	}
+}
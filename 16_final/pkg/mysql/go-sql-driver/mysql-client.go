@@ -1,8 +1,12 @@
 package gosqldriver
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -13,10 +17,13 @@ MySQLClient representa um cliente para interagir com uma base de dados MySQL.
 Ele encapsula:
 - A configuração da conexão (host, usuário, senha, etc.)
 - A instância da conexão ativa com o banco (`*sql.DB`)
+- As conexões com as réplicas somente leitura, se configuradas
 */
 type MySQLClient struct {
-	config MySQLClientConfig // Configuração do cliente MySQL (ver struct abaixo)
-	db     *sql.DB           // Conexão ativa com o banco de dados
+	config   MySQLClientConfig // Configuração do cliente MySQL (ver struct abaixo)
+	db       *sql.DB           // Conexão ativa com o banco de dados (primário)
+	replicas []*sql.DB         // Conexões com as réplicas somente leitura
+	next     atomic.Uint64     // Contador do rodízio entre as réplicas (ver ReadDB)
 }
 
 /*
@@ -42,25 +49,95 @@ func NewMySQLClient(config MySQLClientConfig) (*MySQLClient, error) {
 connect realiza a conexão com o banco de dados MySQL com base na configuração.
 
 Passos:
-- Monta a DSN (data source name) com `config.dsn()`.
-- Usa `sql.Open` para abrir a conexão.
-- Realiza um `Ping()` para garantir que a conexão está funcional.
+- Monta a DSN (data source name) do primário e de cada réplica.
+- Abre cada conexão com `open`, que aguarda o banco responder.
+- Se alguma falhar, fecha as que já foram abertas.
 
+Assim a aplicação pode subir antes do MySQL (ex: no docker-compose) sem falhar de imediato.
 Se der erro em qualquer etapa, ele é retornado com contexto.
 */
 func (client *MySQLClient) connect() error {
-	dsn := client.config.dsn() // Monta string de conexão
-	conn, err := sql.Open("mysql", dsn)
+	dsn, err := client.config.dsn() // Monta string de conexão
+	if err != nil {
+		return fmt.Errorf("configuração do MySQL inválida: %w", err)
+	}
+	replicaDSNs, err := client.config.replicaDSNs()
 	if err != nil {
-		return fmt.Errorf("falha ao conectar ao MySQL: %w", err)
+		return fmt.Errorf("configuração do MySQL inválida: %w", err)
+	}
+
+	if client.db, err = client.open(dsn); err != nil {
+		return err
 	}
-	if err := conn.Ping(); err != nil {
-		return fmt.Errorf("falha ao verificar conexão com MySQL: %w", err)
+	for i, replicaDSN := range replicaDSNs {
+		replica, err := client.open(replicaDSN)
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("réplica %s: %w", client.config.Replicas[i], err)
+		}
+		client.replicas = append(client.replicas, replica)
 	}
-	client.db = conn
 	return nil
 }
 
+/*
+open abre um pool de conexões para dsn, aplica as configurações do pool e
+executa `Ping()` até o banco responder, com backoff exponencial entre as tentativas
+(ver pingWithRetry), por até `config.ConnectTimeout`.
+*/
+func (client *MySQLClient) open(dsn string) (*sql.DB, error) {
+	conn, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar ao MySQL: %w", err)
+	}
+	client.config.applyPool(conn)
+
+	ctx := context.Background()
+	if client.config.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.config.ConnectTimeout)
+		defer cancel()
+	}
+	if err := client.pingWithRetry(ctx, conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("falha ao verificar conexão com MySQL: %w", err)
+	}
+	return conn, nil
+}
+
+/*
+pingWithRetry executa `PingContext` até o banco responder ou ctx expirar.
+
+A espera entre as tentativas começa em `config.RetryInterval` e dobra a cada falha,
+limitada a `config.MaxRetryInterval`. Sem prazo em ctx (ConnectTimeout zero),
+apenas uma tentativa é feita; com prazo, desiste quando a próxima espera
+terminaria depois dele. Se ctx for cancelado durante a espera, desiste na hora.
+
+Retorna o erro da última tentativa (ou o de ctx, se foi cancelado), com o número de tentativas realizadas.
+*/
+func (client *MySQLClient) pingWithRetry(ctx context.Context, conn *sql.DB) error {
+	wait, maxWait := client.config.backoff()
+
+	for attempt := 1; ; attempt++ {
+		err := conn.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) <= wait {
+			return fmt.Errorf("MySQL indisponível após %d tentativa(s): %w", attempt, err)
+		}
+		log.Printf("MySQL indisponível (tentativa %d): %v; nova tentativa em %s", attempt, err, wait)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("MySQL indisponível após %d tentativa(s): %w", attempt, ctx.Err())
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, maxWait)
+	}
+}
+
 /*
 Close fecha a conexão com o banco de dados, se estiver aberta.
 
@@ -71,6 +148,9 @@ func (client *MySQLClient) Close() {
 	if client.db != nil {
 		client.db.Close()
 	}
+	for _, replica := range client.replicas {
+		replica.Close()
+	}
 }
 
 /*
@@ -80,3 +160,24 @@ DB retorna o ponteiro para `*sql.DB`, permitindo que outras partes da aplicaçã
 func (client *MySQLClient) DB() *sql.DB {
 	return client.db
 }
+
+/*
+ReadDB retorna uma conexão para consultas que toleram atraso de replicação
+(ex: listagens), alternando entre as réplicas a cada chamada.
+
+Sem réplicas configuradas, retorna o próprio primário.
+*/
+func (client *MySQLClient) ReadDB() *sql.DB {
+	if len(client.replicas) == 0 {
+		return client.db
+	}
+	return client.replicas[client.next.Add(1)%uint64(len(client.replicas))]
+}
+
+/*
+Stats retorna as estatísticas do pool de conexões (conexões abertas, em uso, ociosas,
+esperas por conexão livre, ...) do primário, para exposição como métricas.
+*/
+func (client *MySQLClient) Stats() sql.DBStats {
+	return client.db.Stats()
+}
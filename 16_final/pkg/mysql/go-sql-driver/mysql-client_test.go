@@ -0,0 +1,87 @@
+package gosqldriver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// closedPort retorna uma porta local em que ninguém está escutando.
+func closedPort(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("falha ao reservar porta: %v", err)
+	}
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	l.Close()
+	return port
+}
+
+func TestConnectRetriesUntilDeadline(t *testing.T) {
+	config := MySQLClientConfig{
+		User: "u", Password: "p", Host: "127.0.0.1", Port: closedPort(t), Database: "db",
+		ConnectTimeout:   400 * time.Millisecond,
+		RetryInterval:    20 * time.Millisecond,
+		MaxRetryInterval: 80 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_, err := NewMySQLClient(config)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("esperado erro ao conectar numa porta fechada")
+	}
+	// 20ms, 40ms, 80ms, 80ms... dentro de 400ms: bem mais que uma tentativa
+	if strings.Contains(err.Error(), "após 1 tentativa") {
+		t.Fatalf("esperado mais de uma tentativa, obtido %v", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("connect levou %s, esperado respeitar o prazo de %s", elapsed, config.ConnectTimeout)
+	}
+}
+
+func TestConnectWithoutTimeoutTriesOnce(t *testing.T) {
+	config := MySQLClientConfig{User: "u", Host: "127.0.0.1", Port: closedPort(t), Database: "db"}
+
+	_, err := NewMySQLClient(config)
+	if err == nil || !strings.Contains(err.Error(), "após 1 tentativa") {
+		t.Fatalf("esperado falha após uma única tentativa, obtido %v", err)
+	}
+}
+
+func TestPingWithRetryStopsWhenCanceled(t *testing.T) {
+	config := MySQLClientConfig{
+		User: "u", Host: "127.0.0.1", Port: closedPort(t), Database: "db",
+		RetryInterval:    time.Minute,
+		MaxRetryInterval: time.Minute,
+	}
+	dsn, err := config.dsn()
+	if err != nil {
+		t.Fatalf("dsn: %v", err)
+	}
+	conn, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer conn.Close()
+
+	// Prazo longo, mas cancelado logo depois da primeira tentativa:
+	// a espera de 1 minuto não pode segurar o retorno
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err = (&MySQLClient{config: config}).pingWithRetry(ctx, conn)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("esperado context.Canceled, obtido %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("pingWithRetry levou %s depois do cancelamento", elapsed)
+	}
+}
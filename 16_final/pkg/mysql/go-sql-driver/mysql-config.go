@@ -1,37 +1,245 @@
 package gosqldriver
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+/*
+Valores usados quando os campos de retentativa de MySQLClientConfig ficam zerados.
+*/
+const (
+	defaultRetryInterval    = 250 * time.Millisecond // Espera antes da segunda tentativa de conexão
+	defaultMaxRetryInterval = 5 * time.Second        // Teto da espera entre tentativas (backoff exponencial)
 )
 
 /*
 MySQLClientConfig contém os dados necessários para se conectar a uma base de dados MySQL.
 
 Essa configuração é usada para montar a DSN (Data Source Name), que é a string
-utilizada pelo driver `database/sql` para se conectar ao MySQL.
+utilizada pelo driver `database/sql` para se conectar ao MySQL, para ajustar o
+pool de conexões e para definir quanto tempo `connect()` insiste até o banco responder.
 */
 type MySQLClientConfig struct {
 	User     string // Nome do usuário do banco de dados
-	Password string // Senha do usuário
+	Password string // Senha do usuário (pode conter qualquer caractere, inclusive '@', ':' e '/')
 	Host     string // Endereço do host onde o MySQL está rodando (ex: localhost, mysql)
 	Port     string // Porta de conexão (normalmente 3306)
+	Socket   string // Caminho do socket unix; se informado, Host e Port são ignorados
 	Database string // Nome do banco de dados a ser utilizado
+
+	TLS           string // Parâmetro `tls` do driver: "", "true", "false", "skip-verify" ou "preferred"
+	TLSCAFile     string // Certificado da CA (PEM) usado para validar o servidor
+	TLSCertFile   string // Certificado do cliente (PEM), para autenticação mútua
+	TLSKeyFile    string // Chave privada do certificado do cliente (PEM)
+	TLSServerName string // Nome esperado no certificado do servidor (padrão: Host)
+
+	Timezone string            // Fuso horário usado para interpretar DATETIME (`loc`); vazio = "UTC"
+	Params   map[string]string // Parâmetros extras da DSN (ex: "readTimeout": "5s"); sobrescrevem os padrões
+
+	Replicas []string // Endereços "host:porta" de réplicas somente leitura (mesmas credenciais e TLS)
+
+	MaxOpenConns    int           // Máximo de conexões abertas (0 = ilimitado)
+	MaxIdleConns    int           // Máximo de conexões ociosas no pool
+	ConnMaxLifetime time.Duration // Tempo máximo de vida de uma conexão (0 = sem limite)
+	ConnMaxIdleTime time.Duration // Tempo máximo que uma conexão fica ociosa no pool (0 = sem limite)
+
+	ConnectTimeout   time.Duration // Prazo total para o banco responder ao ping inicial (0 = uma única tentativa)
+	RetryInterval    time.Duration // Espera antes da segunda tentativa; dobra a cada falha (0 = 250ms)
+	MaxRetryInterval time.Duration // Espera máxima entre duas tentativas (0 = 5s)
 }
 
 /*
-dsn gera a string de conexão (Data Source Name) no formato esperado pelo driver do MySQL.
+dsn gera a string de conexão (Data Source Name) com `mysql.Config.FormatDSN`, que
+escapa usuário, senha e banco no formato esperado pelo driver.
 
-Formato gerado:
+Exemplo gerado:
 
-	usuario:senha@tcp(host:porta)/banco?charset=utf8mb4&parseTime=True&loc=Local
+	usuario:senha@tcp(host:porta)/banco?parseTime=true&charset=utf8mb4
 
-- `utf8mb4`: permite suporte a emojis e caracteres especiais
-- `parseTime=True`: faz com que o Go trate campos de data/hora corretamente
-- `loc=Local`: define o fuso horário como local (pode ser ajustado conforme necessário)
+- `charset=utf8mb4`: permite suporte a emojis e caracteres especiais
+- `parseTime=true`: faz com que o Go trate campos de data/hora corretamente
+- `loc`: fuso horário de config.Timezone; omitido quando é UTC (o padrão)
+- `tls`: o modo de config.TLS ou, com certificados em arquivo, a configuração registrada
+- config.Params: aplicados antes dos campos acima, que têm prioridade sobre eles
 
 Essa função é usada internamente no método `connect()` do `MySQLClient`.
 */
-func (config MySQLClientConfig) dsn() string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.User, config.Password, config.Host, config.Port, config.Database)
+func (config MySQLClientConfig) dsn() (string, error) {
+	cfg, err := config.driverConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.FormatDSN(), nil
+}
+
+/*
+replicaDSNs gera a DSN de cada réplica em config.Replicas.
+
+As réplicas herdam usuário, senha, banco, TLS, fuso horário e parâmetros do primário;
+muda apenas o endereço.
+*/
+func (config MySQLClientConfig) replicaDSNs() ([]string, error) {
+	dsns := make([]string, 0, len(config.Replicas))
+	for _, addr := range config.Replicas {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("endereço de réplica inválido %q: %w", addr, err)
+		}
+
+		replica := config
+		replica.Host, replica.Port, replica.Socket, replica.Replicas = host, port, "", nil
+		dsn, err := replica.dsn()
+		if err != nil {
+			return nil, fmt.Errorf("réplica %s: %w", addr, err)
+		}
+		dsns = append(dsns, dsn)
+	}
+	return dsns, nil
+}
+
+/*
+driverConfig monta a configuração do driver a partir de config.
+
+Passos:
+ 1. Interpreta config.Params com mysql.ParseDSN, para que parâmetros do driver
+    (readTimeout, collation, ...) sejam validados e os demais virem variáveis de sessão;
+ 2. Preenche credenciais, endereço (TCP ou socket unix) e banco;
+ 3. Aplica charset, parseTime e fuso horário (UTC se config.Timezone estiver vazio);
+ 4. Aplica o TLS, registrando no driver a configuração lida dos arquivos, se houver.
+*/
+func (config MySQLClientConfig) driverConfig() (*mysql.Config, error) {
+	params := url.Values{}
+	for k, v := range config.Params {
+		params.Set(k, v)
+	}
+	cfg, err := mysql.ParseDSN("/?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("parâmetros da DSN inválidos: %w", err)
+	}
+	if cfg.Params == nil {
+		cfg.Params = map[string]string{}
+	}
+
+	cfg.User = config.User
+	cfg.Passwd = config.Password
+	cfg.DBName = config.Database
+	cfg.Net, cfg.Addr = "tcp", net.JoinHostPort(config.Host, config.Port)
+	if config.Socket != "" {
+		cfg.Net, cfg.Addr = "unix", config.Socket
+	}
+
+	if _, ok := config.Params["charset"]; !ok {
+		cfg.Params["charset"] = "utf8mb4"
+	}
+	if _, ok := config.Params["parseTime"]; !ok {
+		cfg.ParseTime = true
+	}
+	if _, ok := config.Params["loc"]; !ok || config.Timezone != "" {
+		cfg.Loc = time.UTC
+		if config.Timezone != "" {
+			if cfg.Loc, err = time.LoadLocation(config.Timezone); err != nil {
+				return nil, fmt.Errorf("fuso horário inválido %q: %w", config.Timezone, err)
+			}
+		}
+	}
+
+	if config.TLS != "" {
+		cfg.TLSConfig = config.TLS
+	}
+	if name, err := config.registerTLS(); err != nil {
+		return nil, err
+	} else if name != "" {
+		cfg.TLSConfig = name
+	}
+	return cfg, nil
+}
+
+/*
+registerTLS lê os certificados informados em arquivos e registra a configuração TLS
+no driver (mysql.RegisterTLSConfig), retornando o nome usado em `tls=<nome>`.
+
+Retorna "" se nenhum certificado foi informado. Com config.TLS = "skip-verify",
+a CA é carregada mas o certificado do servidor não é validado.
+*/
+func (config MySQLClientConfig) registerTLS() (string, error) {
+	if config.TLSCAFile == "" && config.TLSCertFile == "" && config.TLSKeyFile == "" {
+		return "", nil
+	}
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		return "", errors.New("TLS: informe o certificado e a chave do cliente juntos")
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         config.TLSServerName,
+		InsecureSkipVerify: config.TLS == "skip-verify",
+		MinVersion:         tls.VersionTLS12,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = config.Host
+	}
+
+	if config.TLSCAFile != "" {
+		pem, err := os.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return "", fmt.Errorf("TLS: falha ao ler a CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return "", fmt.Errorf("TLS: nenhum certificado PEM válido em %s", config.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return "", fmt.Errorf("TLS: falha ao carregar o certificado do cliente: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// Um nome por endereço, para que primário e réplicas possam validar nomes diferentes
+	name := "inventory-" + config.Host + "-" + config.Port
+	if config.Socket != "" {
+		name = "inventory-" + config.Socket
+	}
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", fmt.Errorf("TLS: falha ao registrar a configuração: %w", err)
+	}
+	return name, nil
+}
+
+// applyPool aplica as configurações do pool de conexões em db.
+func (config MySQLClientConfig) applyPool(db *sql.DB) {
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+}
+
+/*
+backoff retorna a espera inicial e a espera máxima entre tentativas de conexão,
+usando os padrões para os campos zerados.
+*/
+func (config MySQLClientConfig) backoff() (initial, max time.Duration) {
+	initial, max = config.RetryInterval, config.MaxRetryInterval
+	if initial <= 0 {
+		initial = defaultRetryInterval
+	}
+	if max <= 0 {
+		max = defaultMaxRetryInterval
+	}
+	if max < initial {
+		max = initial
+	}
+	return initial, max
 }
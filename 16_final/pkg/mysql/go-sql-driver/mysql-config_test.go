@@ -0,0 +1,191 @@
+package gosqldriver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// baseConfig é a configuração mínima usada como ponto de partida nos testes.
+func baseConfig() MySQLClientConfig {
+	return MySQLClientConfig{User: "api_user", Password: "secret", Host: "mysql", Port: "3306", Database: "inventory"}
+}
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*MySQLClientConfig)
+		want   string                   // DSN exata esperada (vazio = não verifica)
+		check  func(*mysql.Config) bool // Verificação sobre a DSN interpretada pelo driver
+	}{
+		{
+			name: "padrões",
+			want: "api_user:secret@tcp(mysql:3306)/inventory?parseTime=true&charset=utf8mb4",
+			check: func(c *mysql.Config) bool {
+				return c.Loc == time.UTC && c.ParseTime && c.Params["charset"] == "utf8mb4"
+			},
+		},
+		{
+			name:   "senha com caracteres especiais",
+			modify: func(c *MySQLClientConfig) { c.Password = "p@ss:w/rd?#%" },
+			check:  func(c *mysql.Config) bool { return c.Passwd == "p@ss:w/rd?#%" && c.DBName == "inventory" },
+		},
+		{
+			name:   "fuso horário",
+			modify: func(c *MySQLClientConfig) { c.Timezone = "America/Sao_Paulo" },
+			check:  func(c *mysql.Config) bool { return c.Loc.String() == "America/Sao_Paulo" },
+		},
+		{
+			name:   "socket unix",
+			modify: func(c *MySQLClientConfig) { c.Socket = "/var/run/mysqld/mysqld.sock" },
+			want:   "api_user:secret@unix(/var/run/mysqld/mysqld.sock)/inventory?parseTime=true&charset=utf8mb4",
+		},
+		{
+			name:   "modo TLS",
+			modify: func(c *MySQLClientConfig) { c.TLS = "skip-verify" },
+			check:  func(c *mysql.Config) bool { return c.TLSConfig == "skip-verify" },
+		},
+		{
+			name: "parâmetros extras",
+			modify: func(c *MySQLClientConfig) {
+				c.Params = map[string]string{"readTimeout": "5s", "charset": "latin1", "sql_mode": "'TRADITIONAL'"}
+			},
+			check: func(c *mysql.Config) bool {
+				return c.ReadTimeout == 5*time.Second && c.Params["charset"] == "latin1" && c.Params["sql_mode"] == "'TRADITIONAL'"
+			},
+		},
+		{
+			name:   "parseTime e loc dos parâmetros são respeitados",
+			modify: func(c *MySQLClientConfig) { c.Params = map[string]string{"parseTime": "false", "loc": "Local"} },
+			check:  func(c *mysql.Config) bool { return !c.ParseTime && c.Loc == time.Local },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := baseConfig()
+			if tt.modify != nil {
+				tt.modify(&config)
+			}
+
+			dsn, err := config.dsn()
+			if err != nil {
+				t.Fatalf("dsn(): %v", err)
+			}
+			if tt.want != "" && dsn != tt.want {
+				t.Fatalf("dsn() = %q, esperado %q", dsn, tt.want)
+			}
+
+			parsed, err := mysql.ParseDSN(dsn)
+			if err != nil {
+				t.Fatalf("o driver não aceitou a DSN %q: %v", dsn, err)
+			}
+			if tt.check != nil && !tt.check(parsed) {
+				t.Fatalf("DSN %q interpretada como %+v", dsn, parsed)
+			}
+		})
+	}
+}
+
+func TestDSNErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*MySQLClientConfig)
+		want   string
+	}{
+		{"fuso horário inválido", func(c *MySQLClientConfig) { c.Timezone = "Marte/Olympus" }, "fuso horário"},
+		{"parâmetro inválido", func(c *MySQLClientConfig) { c.Params = map[string]string{"readTimeout": "rápido"} }, "parâmetros"},
+		{"certificado sem chave", func(c *MySQLClientConfig) { c.TLSCertFile = "client.pem" }, "chave"},
+		{"CA inexistente", func(c *MySQLClientConfig) { c.TLSCAFile = "/nao/existe.pem" }, "CA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := baseConfig()
+			tt.modify(&config)
+			if _, err := config.dsn(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("dsn(): esperado erro contendo %q, obtido %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDSNRegistersTLSFromFiles(t *testing.T) {
+	config := baseConfig()
+	config.TLSCAFile = writeTestCA(t)
+
+	dsn, err := config.dsn()
+	if err != nil {
+		t.Fatalf("dsn(): %v", err)
+	}
+	// ParseDSN falha se o nome em tls=... não estiver registrado no driver
+	parsed, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("o driver não aceitou a DSN %q: %v", dsn, err)
+	}
+	if parsed.TLSConfig != "inventory-mysql-3306" || parsed.TLS == nil || parsed.TLS.ServerName != "mysql" {
+		t.Fatalf("TLS = %q / %+v, esperado a configuração registrada para mysql:3306", parsed.TLSConfig, parsed.TLS)
+	}
+}
+
+func TestReplicaDSNs(t *testing.T) {
+	config := baseConfig()
+	config.Socket = "/tmp/mysql.sock"
+	config.Replicas = []string{"replica1:3306", "10.0.0.5:3307"}
+
+	dsns, err := config.replicaDSNs()
+	if err != nil {
+		t.Fatalf("replicaDSNs(): %v", err)
+	}
+	want := []string{
+		"api_user:secret@tcp(replica1:3306)/inventory?parseTime=true&charset=utf8mb4",
+		"api_user:secret@tcp(10.0.0.5:3307)/inventory?parseTime=true&charset=utf8mb4",
+	}
+	if strings.Join(dsns, " ") != strings.Join(want, " ") {
+		t.Fatalf("replicaDSNs() = %q, esperado %q", dsns, want)
+	}
+
+	config.Replicas = []string{"sem-porta"}
+	if _, err := config.replicaDSNs(); err == nil {
+		t.Fatal("esperado erro para réplica sem porta")
+	}
+}
+
+// writeTestCA grava um certificado de CA autoassinado em um arquivo temporário.
+func writeTestCA(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("falha ao gerar chave: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "inventory test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("falha ao criar certificado: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("falha ao gravar a CA: %v", err)
+	}
+	return path
+}